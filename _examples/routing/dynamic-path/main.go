@@ -153,6 +153,36 @@ func main() {
 	// string of time.Weekday longname format ("sunday" to "monday" or "Sunday" to "Monday")
 	// format e.g. /schedule/{param:weekday} matches /schedule/monday.
 	//
+	// +------------------------+
+	// | {param:ulid}           |
+	// +------------------------+
+	// ULID, 26 characters of Crockford's base32 (case-insensitive).
+	//
+	// +------------------------+
+	// | {param:semver}         |
+	// +------------------------+
+	// semantic version, e.g. 1.2.0 or v1.2.0-beta.1, resolves to a semver.Version.
+	//
+	// +------------------------+
+	// | {param:base64url}      |
+	// +------------------------+
+	// base64 URL-encoded text, padded or not.
+	//
+	// +------------------------+
+	// | {param:hex}            |
+	// +------------------------+
+	// hexadecimal encoded text.
+	//
+	// +------------------------+
+	// | {param:decimal}        |
+	// +------------------------+
+	// arbitrary-precision decimal number, e.g. 19.99, resolves to a *big.Rat.
+	//
+	// +------------------------+
+	// | {param:duration}       |
+	// +------------------------+
+	// time.Duration format e.g. /cache/{param:duration} matches /cache/1h30m.
+	//
 	// If type is missing then parameter's type is defaulted to string, so
 	// {param} is identical to {param:string}.
 	//
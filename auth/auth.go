@@ -5,6 +5,7 @@ package auth
 
 import (
 	stdContext "context"
+	"errors"
 	"fmt"
 	"net/http"
 	"net/url"
@@ -12,6 +13,7 @@ import (
 	"time"
 
 	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/middleware/bruteforce"
 
 	"github.com/google/uuid"
 	"github.com/gorilla/securecookie"
@@ -54,8 +56,20 @@ type (
 		transformer Transformer[T]
 		// Not nil if a custom claims provider is registered.
 		claimsProvider ClaimsProvider
+		// Not nil if a redirect provider is registered.
+		redirectProvider RedirectProvider[T]
 		// True if KIDRefresh on config.Keys.
 		refreshEnabled bool
+		// Not nil if a refresh token store is registered.
+		refreshTokenStore RefreshTokenStore
+		// Not nil if a refresh token reuse handler is registered.
+		refreshTokenReuseHandler RefreshTokenReuseHandler[T]
+		// Not nil if a TOTP provider is registered.
+		totpProvider TOTPProvider[T]
+		// The used mfa tokens.
+		mfaTokens usedTokenStore
+		// Not nil if a brute-force tracker is registered.
+		bruteForce *bruteforce.Tracker
 	}
 
 	// VerifyUserFunc is passed on Verify and VerifyHandler method
@@ -64,18 +78,24 @@ type (
 
 	// SigninRequest is the request body the server expects
 	// on SignHandler. The Password and Username or Email should be filled.
+	// On the second step of a two-factor sign in the MFAToken and Code should be filled instead.
 	SigninRequest struct {
 		Username string `json:"username" form:"username,omitempty"` // username OR email, username has priority over email.
 		Email    string `json:"email" form:"email,omitempty"`       // email OR username.
 		Password string `json:"password" form:"password"`
+		MFAToken string `json:"mfa_token,omitempty" form:"mfa_token,omitempty"` // the mfa token of the first step.
+		Code     string `json:"code,omitempty" form:"code,omitempty"`           // a TOTP or a recovery code.
 	}
 
 	// SigninResponse is the response body the server sends
 	// to the client on the SignHandler. It contains a pair of the access token
 	// and the refresh token if the refresh jwt token id exists in the configuration.
+	// If the user has enabled two-factor authentication, it contains
+	// just the MFAToken which should be sent back along with a code.
 	SigninResponse struct {
-		AccessToken  string `json:"access_token"`
+		AccessToken  string `json:"access_token,omitempty"`
 		RefreshToken string `json:"refresh_token,omitempty"`
+		MFAToken     string `json:"mfa_token,omitempty"`
 	}
 
 	// RefreshRequest is the request body the server expects
@@ -189,6 +209,18 @@ func (s *Auth[T]) AddProvider(providers ...Provider[T]) *Auth[T] {
 				s.claimsProvider = claimsProvider
 			}
 		}
+
+		if s.redirectProvider == nil {
+			if redirectProvider, ok := p.(RedirectProvider[T]); ok {
+				s.redirectProvider = redirectProvider
+			}
+		}
+
+		if s.totpProvider == nil {
+			if totpProvider, ok := p.(TOTPProvider[T]); ok {
+				s.SetTOTPProvider(totpProvider)
+			}
+		}
 	}
 
 	s.providers = append(s.providers, providers...)
@@ -202,6 +234,21 @@ func (s *Auth[T]) SetErrorHandler(errHandler ErrorHandler) *Auth[T] {
 	return s
 }
 
+// SetBruteForce registers a brute-force tracker to the SigninHandler and returns itself.
+// The failed sign in attempts are tracked per username and per client IP,
+// the second factor ones (see SigninMFA) under the username of the mfa token.
+// Locked out attempts are handled by the ErrorHandler's Unauthenticated method
+// with a *bruteforce.LockedError.
+//
+// When the tracker requires a CAPTCHA, JSON clients should send its response
+// through the URL query, e.g. /signin?h-captcha-response=...
+//
+// See the bruteforce package for details.
+func (s *Auth[T]) SetBruteForce(tracker *bruteforce.Tracker) *Auth[T] {
+	s.bruteForce = tracker
+	return s
+}
+
 // SetTransformer sets a custom transformer to this Auth of T instance and returns itself.
 // Look the Provider and Transformer godoc for more.
 func (s *Auth[T]) SetTransformer(transformer Transformer[T]) *Auth[T] {
@@ -220,6 +267,10 @@ func (s *Auth[T]) SetTransformerFunc(transfermerFunc func(ctx stdContext.Context
 //
 // Signin calls the Provider.Signin method to check if a user
 // is authenticated by the given username and password combination.
+//
+// If a TOTPProvider is registered and the user has enabled two-factor authentication,
+// it returns a *MFARequiredError instead, which holds a short-lived mfa token
+// that should be passed to the SigninMFA method along with a code.
 func (s *Auth[T]) Signin(ctx stdContext.Context, username, password string) ([]byte, []byte, error) {
 	var t T
 
@@ -245,8 +296,24 @@ func (s *Auth[T]) Signin(ctx stdContext.Context, username, password string) ([]b
 		return nil, nil, fmt.Errorf("auth: signin: no provider")
 	}
 
+	if s.totpProvider != nil {
+		key, err := s.totpProvider.TOTPKey(ctx, t)
+		if err != nil {
+			return nil, nil, fmt.Errorf("auth: signin: %w", err)
+		}
+
+		if key != nil { // two-factor authentication is enabled.
+			mfaToken, err := s.signMFAToken(t, username)
+			if err != nil {
+				return nil, nil, fmt.Errorf("auth: signin: %w", err)
+			}
+
+			return nil, nil, &MFARequiredError{MFAToken: mfaToken}
+		}
+	}
+
 	// sign the tokens.
-	accessToken, refreshToken, err := s.sign(t)
+	accessToken, refreshToken, err := s.sign(ctx, t, "")
 	if err != nil {
 		return nil, nil, fmt.Errorf("auth: signin: %w", err)
 	}
@@ -254,7 +321,10 @@ func (s *Auth[T]) Signin(ctx stdContext.Context, username, password string) ([]b
 	return accessToken, refreshToken, nil
 }
 
-func (s *Auth[T]) sign(t T) ([]byte, []byte, error) {
+// sign signs a new pair of access and refresh tokens.
+// The refresh token is recorded to the refresh token store, if any,
+// as a member of the "family" or of a new family when "family" is empty.
+func (s *Auth[T]) sign(ctx stdContext.Context, t T, family string) ([]byte, []byte, error) {
 	// sign the tokens.
 	var (
 		accessStdClaims  StandardClaims
@@ -302,6 +372,23 @@ func (s *Auth[T]) sign(t T) ([]byte, []byte, error) {
 		if err != nil {
 			return nil, nil, fmt.Errorf("refresh: %w", err)
 		}
+
+		if s.refreshTokenStore != nil {
+			if family == "" { // a new sign in.
+				family = refreshStdClaims.ID
+			}
+
+			var expiresAt time.Time
+			if refreshStdClaims.Expiry > 0 {
+				expiresAt = time.Unix(refreshStdClaims.Expiry, 0)
+			} else if maxAge := s.keys[KIDRefresh].MaxAge; maxAge > 0 {
+				expiresAt = time.Unix(iat, 0).Add(maxAge)
+			}
+
+			if err = s.refreshTokenStore.Issue(ctx, family, refreshStdClaims.ID, expiresAt); err != nil {
+				return nil, nil, fmt.Errorf("refresh: %w", err)
+			}
+		}
 	}
 
 	return accessToken, refreshToken, nil
@@ -310,7 +397,21 @@ func (s *Auth[T]) sign(t T) ([]byte, []byte, error) {
 // SignHandler generates and sends a pair of access and refresh token to the client
 // as JSON body of `SigninResponse` and cookie (if cookie setting was provided).
 // See `Signin` method for more.
+//
+// If the user has enabled two-factor authentication, it sends
+// just the mfa token of the `SigninResponse` instead. The client should
+// send it back, along with a TOTP or a recovery code, through the
+// `SigninRequest.MFAToken` and `Code` fields to complete the sign in.
+// See `SigninMFA` method for more.
+//
+// If a RedirectProvider (e.g. OIDC) is registered, GET requests
+// are redirected to the identity provider and its callback request signs the tokens instead.
 func (s *Auth[T]) SigninHandler(ctx *context.Context) {
+	if s.redirectProvider != nil && ctx.Method() == http.MethodGet {
+		s.redirectSigninHandler(ctx)
+		return
+	}
+
 	// No, let the developer decide it based on a middleware, e.g. iris.LimitRequestBodySize.
 	// ctx.SetMaxRequestBodySize(s.maxRequestBodySize)
 
@@ -335,13 +436,159 @@ func (s *Auth[T]) SigninHandler(ctx *context.Context) {
 		req.Username = req.Email
 	}
 
-	accessTokenBytes, refreshTokenBytes, err := s.Signin(ctx, req.Username, req.Password)
+	// The second factor attempts are tracked under the username the mfa token was issued for.
+	var (
+		username      = req.Username
+		verifiedToken *VerifiedToken
+		mfaTokenErr   error
+	)
+	if req.MFAToken != "" {
+		username = ""
+		if verifiedToken, mfaTokenErr = s.verifyMFAToken([]byte(req.MFAToken)); mfaTokenErr == nil {
+			username = verifiedToken.StandardClaims.Subject
+		}
+	}
+
+	if s.bruteForce != nil {
+		if err = s.bruteForce.Check(ctx, username); err != nil {
+			s.errorHandler.Unauthenticated(ctx, err)
+			return
+		}
+	}
+
+	var accessTokenBytes, refreshTokenBytes []byte
+	if req.MFAToken != "" {
+		if mfaTokenErr != nil {
+			err = fmt.Errorf("auth: signin: mfa: %w", mfaTokenErr)
+		} else {
+			accessTokenBytes, refreshTokenBytes, err = s.signinMFA(ctx, verifiedToken, req.Code)
+		}
+	} else {
+		accessTokenBytes, refreshTokenBytes, err = s.Signin(ctx, req.Username, req.Password)
+	}
 	if err != nil {
 		s.tryRemoveCookie(ctx) // remove cookie on invalidated.
 
+		var mfaErr *MFARequiredError
+		if errors.As(err, &mfaErr) {
+			// The password was valid but the failures of the username are not reset
+			// until the second factor succeeds too, they include the failed codes.
+			ctx.JSON(SigninResponse{MFAToken: jwt.BytesToString(mfaErr.MFAToken)})
+			return
+		}
+
+		if s.bruteForce != nil {
+			s.bruteForce.Fail(ctx, username)
+		}
+
 		s.errorHandler.Unauthenticated(ctx, err)
 		return
 	}
+
+	if s.bruteForce != nil && username != "" {
+		s.bruteForce.Succeed(ctx, username)
+	}
+	accessToken := jwt.BytesToString(accessTokenBytes)
+	refreshToken := jwt.BytesToString(refreshTokenBytes)
+
+	s.trySetCookie(ctx, accessToken)
+
+	resp := SigninResponse{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+	}
+	ctx.JSON(resp)
+}
+
+// stateCookieName is the cookie name which holds the signed state
+// of a RedirectProvider's authorization request or a WebAuthn ceremony.
+const stateCookieName = "iris_auth_state"
+
+// stateCookieMaxAge is the max age of the state cookie.
+var stateCookieMaxAge = 10 * time.Minute
+
+// setStateCookie stores the "state" to a short-lived signed cookie.
+func (s *Auth[T]) setStateCookie(ctx *context.Context, state []byte) error {
+	value, err := s.securecookie.Encode(stateCookieName, string(state))
+	if err != nil {
+		return fmt.Errorf("state: %w", err)
+	}
+
+	ctx.SetCookie(&http.Cookie{
+		Name:     stateCookieName,
+		Value:    value,
+		Path:     "/",
+		HttpOnly: true,
+		Secure:   s.config.Cookie.Secure || ctx.IsSSL(),
+		SameSite: http.SameSiteLaxMode, // the callback is a top-level navigation from the identity provider.
+		Expires:  time.Now().Add(stateCookieMaxAge),
+		MaxAge:   int(stateCookieMaxAge.Seconds()),
+	})
+	return nil
+}
+
+// popStateCookie returns the state stored by setStateCookie and removes its cookie,
+// the state is valid for a single use. It returns nil if the cookie is missing or invalid.
+func (s *Auth[T]) popStateCookie(ctx *context.Context) []byte {
+	var state string
+	if value := ctx.GetCookie(stateCookieName); value != "" {
+		if err := s.securecookie.Decode(stateCookieName, value, &state); err != nil {
+			state = ""
+		}
+	}
+	ctx.RemoveCookie(stateCookieName)
+
+	if state == "" {
+		return nil
+	}
+
+	return []byte(state)
+}
+
+func (s *Auth[T]) redirectSigninHandler(ctx *context.Context) {
+	if !ctx.URLParamExists("code") && !ctx.URLParamExists("error") {
+		redirectURL, state, err := s.redirectProvider.AuthorizeURL(ctx)
+		if err != nil {
+			s.errorHandler.InvalidArgument(ctx, fmt.Errorf("auth: signin: %w", err))
+			return
+		}
+
+		if err = s.setStateCookie(ctx, state); err != nil {
+			s.errorHandler.InvalidArgument(ctx, fmt.Errorf("auth: signin: %w", err))
+			return
+		}
+
+		ctx.Redirect(redirectURL, http.StatusFound)
+		return
+	}
+
+	// callback.
+	state := s.popStateCookie(ctx)
+	if state == nil {
+		s.tryRemoveCookie(ctx)
+		s.errorHandler.Unauthenticated(ctx, fmt.Errorf("auth: signin: missing or invalid state"))
+		return
+	}
+
+	t, err := s.redirectProvider.Callback(ctx, state)
+	if err != nil {
+		s.tryRemoveCookie(ctx)
+		s.errorHandler.Unauthenticated(ctx, fmt.Errorf("auth: signin: %w", err))
+		return
+	}
+
+	s.signinResponse(ctx, t)
+}
+
+// signinResponse signs a new pair of tokens for the "t" user and sends them
+// to the client as JSON body of `SigninResponse` and cookie (if cookie setting was provided).
+func (s *Auth[T]) signinResponse(ctx *context.Context, t T) {
+	accessTokenBytes, refreshTokenBytes, err := s.sign(ctx, t, "")
+	if err != nil {
+		s.tryRemoveCookie(ctx)
+		s.errorHandler.Unauthenticated(ctx, fmt.Errorf("auth: signin: %w", err))
+		return
+	}
 	accessToken := jwt.BytesToString(accessTokenBytes)
 	refreshToken := jwt.BytesToString(refreshTokenBytes)
 
@@ -387,14 +634,12 @@ func (s *Auth[T]) verify(ctx stdContext.Context, token []byte) (T, StandardClaim
 		return t, StandardClaims{}, err
 	}
 
-	if s.transformer != nil {
-		if t, err = s.transformer.Transform(ctx, verifiedToken); err != nil {
-			return t, StandardClaims{}, err
-		}
-	} else {
-		if err = verifiedToken.Claims(&t); err != nil {
-			return t, StandardClaims{}, err
-		}
+	if isMFAToken(verifiedToken.StandardClaims) { // the first step of a two-factor sign in.
+		return t, StandardClaims{}, ErrMFARequired
+	}
+
+	if t, err = s.transform(ctx, verifiedToken); err != nil {
+		return t, StandardClaims{}, err
 	}
 
 	standardClaims := verifiedToken.StandardClaims
@@ -422,6 +667,18 @@ func (s *Auth[T]) verify(ctx stdContext.Context, token []byte) (T, StandardClaim
 	return t, standardClaims, nil
 }
 
+// transform returns the T value of a verified token,
+// through the registered Transformer, if any.
+func (s *Auth[T]) transform(ctx stdContext.Context, verifiedToken *VerifiedToken) (T, error) {
+	if s.transformer != nil {
+		return s.transformer.Transform(ctx, verifiedToken)
+	}
+
+	var t T
+	err := verifiedToken.Claims(&t)
+	return t, err
+}
+
 // VerifyHandler verifies and sets the necessary information about the user(claims) and
 // the verified token to the Iris Context and calls the Context's Next method.
 // This information is available through auth.GetAccessToken, auth.GetStandardClaims and
@@ -476,14 +733,23 @@ func (s *Auth[T]) Refresh(ctx stdContext.Context, refreshToken []byte) ([]byte,
 		return nil, nil, fmt.Errorf("auth: refresh: disabled")
 	}
 
-	t, _, err := s.verify(ctx, refreshToken)
+	t, standardClaims, err := s.verify(ctx, refreshToken)
 	if err != nil {
 		return nil, nil, fmt.Errorf("auth: refresh: %w", err)
 	}
 
+	var family string
+	if s.refreshTokenStore != nil {
+		// each refresh token can be used once,
+		// a reused one revokes its family.
+		if family, err = s.useRefreshToken(ctx, standardClaims, t); err != nil {
+			return nil, nil, fmt.Errorf("auth: refresh: %w", err)
+		}
+	}
+
 	// refresh the tokens, both refresh & access tokens will be renew to prevent
 	// malicious 😈 users that may hold a refresh token.
-	accessTok, refreshTok, err := s.sign(t)
+	accessTok, refreshTok, err := s.sign(ctx, t, family)
 	if err != nil {
 		return nil, nil, fmt.Errorf("auth: refresh: %w", err)
 	}
@@ -0,0 +1,178 @@
+//go:build go1.18
+// +build go1.18
+
+package auth
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// errCBOR is the base error of the CBOR decoder.
+var errCBOR = errors.New("cbor")
+
+// cborMaxDepth limits the nesting of arrays and maps.
+const cborMaxDepth = 16
+
+// cborDecode decodes the first CBOR (RFC 8949) data item of "data"
+// and returns it along with the rest of the bytes.
+// It decodes the subset of CBOR which is used by WebAuthn
+// (definite lengths only, as required by the CTAP2 canonical form):
+//   - unsigned and negative integers as int64
+//   - byte strings as []byte
+//   - text strings as string
+//   - arrays as []interface{}
+//   - maps as map[interface{}]interface{} (integer or text keys)
+//   - false, true, null and floats
+//
+// Tags are skipped and their content is returned.
+func cborDecode(data []byte) (interface{}, []byte, error) {
+	return cborDecodeItem(data, 0)
+}
+
+func cborDecodeItem(data []byte, depth int) (interface{}, []byte, error) {
+	if depth > cborMaxDepth {
+		return nil, nil, fmt.Errorf("%w: max depth exceeded", errCBOR)
+	}
+
+	major, arg, rest, err := cborHead(data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch major {
+	case 0: // unsigned integer.
+		if arg > math.MaxInt64 {
+			return nil, nil, fmt.Errorf("%w: integer overflow", errCBOR)
+		}
+		return int64(arg), rest, nil
+	case 1: // negative integer.
+		if arg > math.MaxInt64 {
+			return nil, nil, fmt.Errorf("%w: integer overflow", errCBOR)
+		}
+		return -1 - int64(arg), rest, nil
+	case 2, 3: // byte and text string.
+		if arg > uint64(len(rest)) {
+			return nil, nil, fmt.Errorf("%w: unexpected end of data", errCBOR)
+		}
+
+		b := rest[:arg]
+		if major == 3 {
+			return string(b), rest[arg:], nil
+		}
+		return append([]byte(nil), b...), rest[arg:], nil
+	case 4: // array.
+		if arg > uint64(len(rest)) { // each item is at least one byte.
+			return nil, nil, fmt.Errorf("%w: unexpected end of data", errCBOR)
+		}
+
+		items := make([]interface{}, 0, arg)
+		for i := uint64(0); i < arg; i++ {
+			var item interface{}
+			if item, rest, err = cborDecodeItem(rest, depth+1); err != nil {
+				return nil, nil, err
+			}
+			items = append(items, item)
+		}
+		return items, rest, nil
+	case 5: // map.
+		if arg > uint64(len(rest))/2 { // each pair is at least two bytes.
+			return nil, nil, fmt.Errorf("%w: unexpected end of data", errCBOR)
+		}
+
+		m := make(map[interface{}]interface{}, arg)
+		for i := uint64(0); i < arg; i++ {
+			var key, value interface{}
+			if key, rest, err = cborDecodeItem(rest, depth+1); err != nil {
+				return nil, nil, err
+			}
+
+			switch key.(type) {
+			case int64, string:
+			default:
+				return nil, nil, fmt.Errorf("%w: unsupported map key type: %T", errCBOR, key)
+			}
+
+			if value, rest, err = cborDecodeItem(rest, depth+1); err != nil {
+				return nil, nil, err
+			}
+			m[key] = value
+		}
+		return m, rest, nil
+	case 6: // tag.
+		return cborDecodeItem(rest, depth+1)
+	default: // 7: simple values and floats.
+		switch data[0] & 0x1f {
+		case 20:
+			return false, rest, nil
+		case 21:
+			return true, rest, nil
+		case 22, 23: // null, undefined.
+			return nil, rest, nil
+		case 25:
+			return float64(float16ToFloat32(uint16(arg))), rest, nil
+		case 26:
+			return float64(math.Float32frombits(uint32(arg))), rest, nil
+		case 27:
+			return math.Float64frombits(arg), rest, nil
+		default:
+			return nil, nil, fmt.Errorf("%w: unsupported simple value: %d", errCBOR, arg)
+		}
+	}
+}
+
+// cborHead decodes the head of a data item: its major type and its argument.
+func cborHead(data []byte) (major byte, arg uint64, rest []byte, err error) {
+	if len(data) == 0 {
+		return 0, 0, nil, fmt.Errorf("%w: unexpected end of data", errCBOR)
+	}
+
+	major, info := data[0]>>5, data[0]&0x1f
+	data = data[1:]
+
+	switch {
+	case info < 24:
+		return major, uint64(info), data, nil
+	case info <= 27:
+		n := 1 << (info - 24) // 1, 2, 4 or 8 bytes.
+		if len(data) < n {
+			return 0, 0, nil, fmt.Errorf("%w: unexpected end of data", errCBOR)
+		}
+
+		switch n {
+		case 1:
+			arg = uint64(data[0])
+		case 2:
+			arg = uint64(binary.BigEndian.Uint16(data))
+		case 4:
+			arg = uint64(binary.BigEndian.Uint32(data))
+		default:
+			arg = binary.BigEndian.Uint64(data)
+		}
+
+		return major, arg, data[n:], nil
+	default:
+		return 0, 0, nil, fmt.Errorf("%w: indefinite length or reserved value", errCBOR)
+	}
+}
+
+func float16ToFloat32(h uint16) float32 {
+	sign := uint32(h>>15) << 31
+	exp := uint32(h>>10) & 0x1f
+	frac := uint32(h) & 0x3ff
+
+	switch exp {
+	case 0: // zero and subnormal.
+		f := float32(frac) / (1 << 24)
+		if sign != 0 {
+			return -f
+		}
+		return f
+	case 0x1f: // infinity and NaN.
+		return math.Float32frombits(sign | 0x7f800000 | frac<<13)
+	default:
+		return math.Float32frombits(sign | (exp+112)<<23 | frac<<13)
+	}
+}
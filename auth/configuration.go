@@ -4,6 +4,7 @@
 package auth
 
 import (
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -130,7 +131,7 @@ func (c *Configuration) BindRandom() error {
 				MaxAge:        720 * time.Hour,
 				Public:        string(refreshPublic),
 				Private:       string(refreshPrivate),
-				EncryptionKey: string(jwt.MustGenerateRandom(32)),
+				EncryptionKey: hex.EncodeToString(jwt.MustGenerateRandom(32)),
 			},
 		},
 	}
@@ -0,0 +1,268 @@
+//go:build go1.18
+// +build go1.18
+
+package auth
+
+import (
+	stdContext "context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/auth/totp"
+
+	"github.com/google/uuid"
+	"github.com/kataras/jwt"
+)
+
+var (
+	// ErrMFARequired is reported when the user has enabled two-factor authentication,
+	// see the MFARequiredError type. It's also returned when
+	// an mfa token is used as an access or a refresh token.
+	ErrMFARequired = errors.New("mfa required")
+	// ErrTOTPCodeUsed should be returned from a TOTPProvider's UseTOTPStep
+	// method when a TOTP code is used again.
+	ErrTOTPCodeUsed = errors.New("totp code already used")
+	// ErrRecoveryCodeInvalid should be returned from a TOTPProvider's UseRecoveryCode
+	// method when a recovery code does not exist or it's already used.
+	ErrRecoveryCodeInvalid = errors.New("invalid recovery code")
+	// ErrMFAAttemptsExceeded should be returned from a TOTPProvider's UseMFAAttempt
+	// method when the user has exceeded the allowed second factor attempts.
+	ErrMFAAttemptsExceeded = errors.New("too many mfa attempts")
+	// ErrMFATokenUsed is reported when an mfa token was already exchanged
+	// for a pair of tokens or it has exceeded the allowed failed codes.
+	ErrMFATokenUsed = errors.New("mfa token already used")
+)
+
+// MFARequiredError is returned from the Auth.Signin method
+// when the user has enabled two-factor authentication.
+// Its MFAToken should be passed to the Auth.SigninMFA method along with a code.
+type MFARequiredError struct {
+	MFAToken []byte
+}
+
+// Error implements the error interface.
+func (e *MFARequiredError) Error() string {
+	return "auth: signin: " + ErrMFARequired.Error()
+}
+
+// Is reports whether the "target" is ErrMFARequired.
+func (e *MFARequiredError) Is(target error) bool {
+	return target == ErrMFARequired
+}
+
+// mfaAudience is the audience of the mfa tokens,
+// it tells them apart from the access and refresh tokens.
+const mfaAudience = "iris_auth_mfa"
+
+// mfaTokenMaxAge is the max age of the mfa tokens,
+// the time the user has to enter a code.
+var mfaTokenMaxAge = 5 * time.Minute
+
+// mfaTokenMaxFailures is the number of failed codes
+// an mfa token accepts before it's rejected.
+var mfaTokenMaxFailures = 5
+
+type (
+	// usedTokenStore records the attempts of short-lived tokens by their ID
+	// until they expire, e.g. the mfa tokens by their jti claim, so each token
+	// is exchanged once and it's rejected after a number of attempts.
+	usedTokenStore struct {
+		mu     sync.Mutex
+		tokens map[string]*usedToken // id -> state.
+		gcAt   time.Time
+	}
+
+	usedToken struct {
+		attempts  int
+		used      bool
+		expiresAt time.Time
+	}
+)
+
+// use counts an attempt of the "id" token and reports false
+// if the token was already used or it has made "maxAttempts" attempts.
+func (m *usedTokenStore) use(id string, expiresAt time.Time, maxAttempts int) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.gc(now)
+
+	if m.tokens == nil {
+		m.tokens = make(map[string]*usedToken)
+	}
+
+	token, ok := m.tokens[id]
+	if !ok {
+		token = &usedToken{expiresAt: expiresAt}
+		m.tokens[id] = token
+	}
+
+	if token.used || token.attempts >= maxAttempts {
+		return false
+	}
+
+	token.attempts++
+	return true
+}
+
+// consume marks the "id" token as used, e.g. after a successful code.
+func (m *usedTokenStore) consume(id string) {
+	m.mu.Lock()
+	if token, ok := m.tokens[id]; ok {
+		token.used = true
+	}
+	m.mu.Unlock()
+}
+
+func (m *usedTokenStore) gc(now time.Time) {
+	if now.Sub(m.gcAt) < memoryRefreshTokenStoreGCInterval {
+		return
+	}
+	m.gcAt = now
+
+	for id, token := range m.tokens {
+		if expired(token.expiresAt, now) {
+			delete(m.tokens, id)
+		}
+	}
+}
+
+// SetTOTPProvider sets a TOTP provider to this Auth of T instance and returns itself.
+// It enables two-factor authentication for the users with a TOTP key.
+// Look the TOTPProvider godoc for more.
+func (s *Auth[T]) SetTOTPProvider(provider TOTPProvider[T]) *Auth[T] {
+	s.totpProvider = provider
+	return s
+}
+
+// signMFAToken signs a short-lived token for the first step of a two-factor sign in.
+// It's signed by the access token key but it's not accepted as an access token.
+// Its subject is the "username" of the sign in.
+func (s *Auth[T]) signMFAToken(t T, username string) ([]byte, error) {
+	now := jwt.Clock()
+	claims := StandardClaims{
+		ID:       uuid.NewString(),
+		Subject:  username,
+		IssuedAt: now.Unix(),
+		Expiry:   now.Add(mfaTokenMaxAge).Unix(),
+		Audience: jwt.Audience{mfaAudience},
+	}
+
+	token, err := s.keys.SignToken(KIDAccess, t, claims)
+	if err != nil {
+		return nil, fmt.Errorf("mfa: %w", err)
+	}
+
+	return token, nil
+}
+
+func isMFAToken(claims StandardClaims) bool {
+	for _, aud := range claims.Audience {
+		if aud == mfaAudience {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SigninMFA completes a two-factor sign in. It accepts the mfa token
+// of the MFARequiredError returned from Signin and a TOTP or a recovery code
+// and returns a pair of access and refresh tokens.
+//
+// TOTP codes are verified against the user's TOTPProvider.TOTPKey, accepting a clock skew
+// of one time step, and each code can be used once. Any other code is treated as a recovery code.
+//
+// An mfa token can be exchanged once and it's rejected with ErrMFATokenUsed
+// after 5 failed codes. The attempts are also counted per user
+// through the TOTPProvider.UseMFAAttempt method, so signing in again
+// for a new mfa token does not reset them.
+func (s *Auth[T]) SigninMFA(ctx stdContext.Context, mfaToken []byte, code string) ([]byte, []byte, error) {
+	verifiedToken, err := s.verifyMFAToken(mfaToken)
+	if err != nil {
+		return nil, nil, fmt.Errorf("auth: signin: mfa: %w", err)
+	}
+
+	return s.signinMFA(ctx, verifiedToken, code)
+}
+
+// verifyMFAToken verifies the signature and the expiration of an mfa token.
+func (s *Auth[T]) verifyMFAToken(mfaToken []byte) (*VerifiedToken, error) {
+	verifiedToken, err := jwt.VerifyWithHeaderValidator(nil, nil, mfaToken, s.keys.ValidateHeader, jwt.Future(time.Minute), jwt.Leeway(time.Minute))
+	if err != nil {
+		return nil, err
+	}
+
+	if claims := verifiedToken.StandardClaims; !isMFAToken(claims) || claims.ID == "" {
+		return nil, fmt.Errorf("not an mfa token")
+	}
+
+	return verifiedToken, nil
+}
+
+// signinMFA completes a two-factor sign in of a verified mfa token.
+func (s *Auth[T]) signinMFA(ctx stdContext.Context, verifiedToken *VerifiedToken, code string) ([]byte, []byte, error) {
+	if s.totpProvider == nil {
+		return nil, nil, fmt.Errorf("auth: signin: mfa: no totp provider")
+	}
+
+	claims := verifiedToken.StandardClaims
+	if !s.mfaTokens.use(claims.ID, claims.ExpiresAt(), mfaTokenMaxFailures) {
+		return nil, nil, fmt.Errorf("auth: signin: mfa: %w", ErrMFATokenUsed)
+	}
+
+	t, err := s.transform(ctx, verifiedToken)
+	if err != nil {
+		return nil, nil, fmt.Errorf("auth: signin: mfa: %w", err)
+	}
+
+	if err = s.totpProvider.UseMFAAttempt(ctx, t); err != nil {
+		return nil, nil, fmt.Errorf("auth: signin: mfa: %w", err)
+	}
+
+	if err = s.verifyMFACode(ctx, t, code); err != nil {
+		return nil, nil, fmt.Errorf("auth: signin: mfa: %w", err)
+	}
+
+	s.mfaTokens.consume(claims.ID)
+	if err = s.totpProvider.ResetMFAAttempts(ctx, t); err != nil {
+		return nil, nil, fmt.Errorf("auth: signin: mfa: %w", err)
+	}
+
+	accessToken, refreshToken, err := s.sign(ctx, t, "")
+	if err != nil {
+		return nil, nil, fmt.Errorf("auth: signin: %w", err)
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+// verifyMFACode verifies and consumes a TOTP or a recovery code of the "t" user.
+func (s *Auth[T]) verifyMFACode(ctx stdContext.Context, t T, code string) error {
+	key, err := s.totpProvider.TOTPKey(ctx, t)
+	if err != nil {
+		return err
+	}
+
+	if key == nil {
+		return fmt.Errorf("two-factor authentication is not enabled")
+	}
+
+	if key.IsCode(code) {
+		step, err := key.Validate(code, time.Now(), totp.DefaultSkew)
+		if err != nil {
+			return err
+		}
+
+		return s.totpProvider.UseTOTPStep(ctx, t, step)
+	}
+
+	if code == "" {
+		return totp.ErrInvalidCode
+	}
+
+	return s.totpProvider.UseRecoveryCode(ctx, t, totp.HashRecoveryCode(code))
+}
@@ -0,0 +1,359 @@
+//go:build go1.18
+// +build go1.18
+
+package auth_test
+
+import (
+	stdContext "context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/auth"
+	"github.com/kataras/iris/v12/auth/totp"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/bruteforce"
+)
+
+type mfaUser struct {
+	Username string `json:"username"`
+}
+
+type mfaUserProvider struct {
+	refreshUserProvider
+
+	mu            sync.Mutex
+	keys          map[string]*totp.Key
+	lastSteps     map[string]int64
+	recoveryCodes map[string]map[string]struct{} // username -> code hashes.
+	attempts      map[string]int
+	maxAttempts   int
+}
+
+func (p *mfaUserProvider) Signin(ctx stdContext.Context, username, password string) (mfaUser, error) {
+	u, err := p.refreshUserProvider.Signin(ctx, username, password)
+	return mfaUser{Username: u.Username}, err
+}
+
+func (p *mfaUserProvider) ValidateToken(stdContext.Context, auth.StandardClaims, mfaUser) error {
+	return nil
+}
+
+func (p *mfaUserProvider) InvalidateToken(stdContext.Context, auth.StandardClaims, mfaUser) error {
+	return nil
+}
+
+func (p *mfaUserProvider) InvalidateTokens(stdContext.Context, mfaUser) error {
+	return nil
+}
+
+func (p *mfaUserProvider) TOTPKey(_ stdContext.Context, u mfaUser) (*totp.Key, error) {
+	return p.keys[u.Username], nil
+}
+
+func (p *mfaUserProvider) UseTOTPStep(_ stdContext.Context, u mfaUser, step int64) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if step <= p.lastSteps[u.Username] {
+		return auth.ErrTOTPCodeUsed
+	}
+
+	p.lastSteps[u.Username] = step
+	return nil
+}
+
+func (p *mfaUserProvider) UseRecoveryCode(_ stdContext.Context, u mfaUser, codeHash string) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if _, ok := p.recoveryCodes[u.Username][codeHash]; !ok {
+		return auth.ErrRecoveryCodeInvalid
+	}
+
+	delete(p.recoveryCodes[u.Username], codeHash)
+	return nil
+}
+
+func (p *mfaUserProvider) UseMFAAttempt(_ stdContext.Context, u mfaUser) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.maxAttempts > 0 && p.attempts[u.Username] >= p.maxAttempts {
+		return auth.ErrMFAAttemptsExceeded
+	}
+
+	p.attempts[u.Username]++
+	return nil
+}
+
+func (p *mfaUserProvider) ResetMFAAttempts(_ stdContext.Context, u mfaUser) error {
+	p.mu.Lock()
+	delete(p.attempts, u.Username)
+	p.mu.Unlock()
+	return nil
+}
+
+func TestSigninMFA(t *testing.T) {
+	key, err := totp.Generate("Iris", "kataras")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	recoveryCodes, err := totp.GenerateRecoveryCodes(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider := &mfaUserProvider{
+		keys:      map[string]*totp.Key{"kataras": key},
+		lastSteps: make(map[string]int64),
+		attempts:  make(map[string]int),
+		recoveryCodes: map[string]map[string]struct{}{
+			"kataras": {
+				totp.HashRecoveryCode(recoveryCodes[0]): {},
+				totp.HashRecoveryCode(recoveryCodes[1]): {},
+			},
+		},
+	}
+
+	s := auth.Must(auth.New[mfaUser](auth.MustGenerateConfiguration())).AddProvider(provider)
+
+	app := iris.New()
+	app.Post("/signin", s.SigninHandler)
+	app.Post("/refresh", s.RefreshHandler)
+	app.Get("/me", s.VerifyHandler(), func(ctx iris.Context) {
+		ctx.JSON(auth.GetUser[mfaUser](ctx))
+	})
+
+	e := httptest.New(t, app)
+
+	// Users without a key sign in with a single step.
+	e.POST("/signin").WithJSON(auth.SigninRequest{Username: "makis", Password: "password"}).Expect().
+		Status(httptest.StatusOK).JSON().Object().ContainsKey("access_token").NotContainsKey("mfa_token")
+
+	signin := func() string {
+		return e.POST("/signin").WithJSON(auth.SigninRequest{Username: "kataras", Password: "password"}).Expect().
+			Status(httptest.StatusOK).JSON().Object().NotContainsKey("access_token").
+			Value("mfa_token").String().NotEmpty().Raw()
+	}
+
+	mfaToken := signin()
+
+	// The mfa token is not an access token.
+	e.GET("/me").WithHeader("Authorization", "Bearer "+mfaToken).Expect().Status(httptest.StatusUnauthorized)
+	e.POST("/refresh").WithJSON(auth.RefreshRequest{RefreshToken: mfaToken}).Expect().Status(httptest.StatusUnauthorized)
+
+	// Invalid codes.
+	e.POST("/signin").WithJSON(auth.SigninRequest{MFAToken: mfaToken, Code: ""}).Expect().Status(httptest.StatusUnauthorized)
+	e.POST("/signin").WithJSON(auth.SigninRequest{MFAToken: mfaToken, Code: "abcde-fghij"}).Expect().Status(httptest.StatusUnauthorized)
+
+	code, err := key.Code(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accessToken := e.POST("/signin").WithJSON(auth.SigninRequest{MFAToken: mfaToken, Code: code}).Expect().
+		Status(httptest.StatusOK).JSON().Object().Value("access_token").String().NotEmpty().Raw()
+
+	e.GET("/me").WithHeader("Authorization", "Bearer "+accessToken).Expect().Status(httptest.StatusOK).
+		JSON().IsEqual(mfaUser{Username: "kataras"})
+
+	// An mfa token can be used once.
+	e.POST("/signin").WithJSON(auth.SigninRequest{MFAToken: mfaToken, Code: recoveryCodes[0]}).Expect().Status(httptest.StatusUnauthorized)
+
+	// A TOTP code can be used once.
+	e.POST("/signin").WithJSON(auth.SigninRequest{MFAToken: signin(), Code: code}).Expect().Status(httptest.StatusUnauthorized)
+
+	// Recovery codes can be used once.
+	e.POST("/signin").WithJSON(auth.SigninRequest{MFAToken: signin(), Code: recoveryCodes[0]}).Expect().
+		Status(httptest.StatusOK).JSON().Object().Value("access_token").String().NotEmpty()
+	e.POST("/signin").WithJSON(auth.SigninRequest{MFAToken: signin(), Code: recoveryCodes[0]}).Expect().Status(httptest.StatusUnauthorized)
+
+	// Programmatic sign in.
+	ctx := stdContext.Background()
+	_, _, err = s.Signin(ctx, "kataras", "password")
+	var mfaErr *auth.MFARequiredError
+	if !errors.As(err, &mfaErr) || !errors.Is(err, auth.ErrMFARequired) {
+		t.Fatalf("expected error: %v but got: %v", auth.ErrMFARequired, err)
+	}
+
+	if _, _, err = s.SigninMFA(ctx, mfaErr.MFAToken, recoveryCodes[1]); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err = s.SigninMFA(ctx, []byte(accessToken), recoveryCodes[1]); err == nil {
+		t.Fatal("expected an error when an access token is used as an mfa token")
+	}
+}
+
+func TestSigninBruteForce(t *testing.T) {
+	key, err := totp.Generate("Iris", "kataras")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider := &mfaUserProvider{
+		keys:      map[string]*totp.Key{"kataras": key},
+		lastSteps: make(map[string]int64),
+		attempts:  make(map[string]int),
+	}
+
+	s := auth.Must(auth.New[mfaUser](auth.MustGenerateConfiguration())).AddProvider(provider).
+		SetBruteForce(bruteforce.New(bruteforce.Options{
+			UsernameLimit: 2,
+			IPLimit:       3,
+			IP: func(ctx iris.Context) string {
+				if ip := ctx.GetHeader("X-IP"); ip != "" {
+					return ip
+				}
+
+				return "1.1.1.1"
+			},
+		}))
+
+	app := iris.New()
+	app.Post("/signin", s.SigninHandler)
+
+	e := httptest.New(t, app)
+
+	e.POST("/signin").WithJSON(auth.SigninRequest{Username: "makis", Password: "invalid"}).Expect().Status(httptest.StatusUnauthorized)
+	e.POST("/signin").WithJSON(auth.SigninRequest{Username: "makis", Password: "invalid"}).Expect().Status(httptest.StatusUnauthorized)
+	e.POST("/signin").WithJSON(auth.SigninRequest{Username: "makis", Password: "password"}).Expect().
+		Status(httptest.StatusTooManyRequests).Header("Retry-After").IsEqual("60")
+
+	// The password of a user with two-factor authentication is valid, the code is not.
+	mfaToken := e.POST("/signin").WithJSON(auth.SigninRequest{Username: "kataras", Password: "password"}).Expect().
+		Status(httptest.StatusOK).JSON().Object().Value("mfa_token").String().NotEmpty().Raw()
+	e.POST("/signin").WithJSON(auth.SigninRequest{MFAToken: mfaToken, Code: "000000"}).Expect().Status(httptest.StatusUnauthorized)
+
+	// The client's IP is locked out for any user.
+	e.POST("/signin").WithJSON(auth.SigninRequest{Username: "george", Password: "password"}).Expect().
+		Status(httptest.StatusTooManyRequests)
+
+	// The failed codes are tracked under the user of the mfa token, from any IP.
+	e.POST("/signin").WithHeader("X-IP", "2.2.2.2").WithJSON(auth.SigninRequest{MFAToken: mfaToken, Code: "000000"}).Expect().
+		Status(httptest.StatusUnauthorized)
+	e.POST("/signin").WithHeader("X-IP", "3.3.3.3").WithJSON(auth.SigninRequest{Username: "kataras", Password: "password"}).Expect().
+		Status(httptest.StatusTooManyRequests)
+
+	// A forged mfa token does not lock out anyone.
+	e.POST("/signin").WithHeader("X-IP", "4.4.4.4").WithJSON(auth.SigninRequest{MFAToken: "invalid", Code: "000000"}).Expect().
+		Status(httptest.StatusUnauthorized)
+	e.POST("/signin").WithHeader("X-IP", "5.5.5.5").WithJSON(auth.SigninRequest{Username: "george", Password: "password"}).Expect().
+		Status(httptest.StatusOK)
+}
+
+func TestSigninMFAFailures(t *testing.T) {
+	key, err := totp.Generate("Iris", "kataras")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider := &mfaUserProvider{
+		keys:        map[string]*totp.Key{"kataras": key},
+		lastSteps:   make(map[string]int64),
+		attempts:    make(map[string]int),
+		maxAttempts: 7,
+	}
+
+	s := auth.Must(auth.New[mfaUser](auth.MustGenerateConfiguration())).AddProvider(provider)
+
+	ctx := stdContext.Background()
+	signin := func() []byte {
+		_, _, err := s.Signin(ctx, "kataras", "password")
+		var mfaErr *auth.MFARequiredError
+		if !errors.As(err, &mfaErr) {
+			t.Fatalf("expected error: %v but got: %v", auth.ErrMFARequired, err)
+		}
+
+		return mfaErr.MFAToken
+	}
+
+	code, err := key.Code(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An mfa token is rejected after 5 failed codes, even with a valid code.
+	mfaToken := signin()
+	for i := 0; i < 5; i++ {
+		if _, _, err = s.SigninMFA(ctx, mfaToken, "000000"); err == nil {
+			t.Fatal("expected an error on an invalid code")
+		}
+	}
+
+	if _, _, err = s.SigninMFA(ctx, mfaToken, code); !errors.Is(err, auth.ErrMFATokenUsed) {
+		t.Fatalf("expected error: %v but got: %v", auth.ErrMFATokenUsed, err)
+	}
+
+	// The attempts of the user are counted across mfa tokens.
+	mfaToken = signin()
+	for i := 0; i < 2; i++ {
+		if _, _, err = s.SigninMFA(ctx, mfaToken, "000000"); err == nil {
+			t.Fatal("expected an error on an invalid code")
+		}
+	}
+
+	if _, _, err = s.SigninMFA(ctx, mfaToken, code); !errors.Is(err, auth.ErrMFAAttemptsExceeded) {
+		t.Fatalf("expected error: %v but got: %v", auth.ErrMFAAttemptsExceeded, err)
+	}
+
+	// A successful code resets the attempts of the user.
+	provider.mu.Lock()
+	delete(provider.attempts, "kataras")
+	provider.mu.Unlock()
+
+	if _, _, err = s.SigninMFA(ctx, signin(), code); err != nil {
+		t.Fatal(err)
+	}
+
+	provider.mu.Lock()
+	attempts := provider.attempts["kataras"]
+	provider.mu.Unlock()
+	if attempts != 0 {
+		t.Fatalf("expected the attempts to be reset but got: %d", attempts)
+	}
+}
+
+func TestSigninBruteForceMFAResignin(t *testing.T) {
+	key, err := totp.Generate("Iris", "kataras")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provider := &mfaUserProvider{
+		keys:      map[string]*totp.Key{"kataras": key},
+		lastSteps: make(map[string]int64),
+		attempts:  make(map[string]int),
+	}
+
+	ip := 0
+	s := auth.Must(auth.New[mfaUser](auth.MustGenerateConfiguration())).AddProvider(provider).
+		SetBruteForce(bruteforce.New(bruteforce.Options{
+			UsernameLimit: 3,
+			IPLimit:       -1,
+			IP: func(iris.Context) string {
+				ip++ // a different client IP on each attempt.
+				return fmt.Sprintf("10.0.0.%d", ip)
+			},
+		}))
+
+	app := iris.New()
+	app.Post("/signin", s.SigninHandler)
+
+	e := httptest.New(t, app)
+
+	// Signing in again with the password does not reset the failed codes.
+	for i := 0; i < 3; i++ {
+		mfaToken := e.POST("/signin").WithJSON(auth.SigninRequest{Username: "kataras", Password: "password"}).Expect().
+			Status(httptest.StatusOK).JSON().Object().Value("mfa_token").String().NotEmpty().Raw()
+		e.POST("/signin").WithJSON(auth.SigninRequest{MFAToken: mfaToken, Code: "000000"}).Expect().Status(httptest.StatusUnauthorized)
+	}
+
+	e.POST("/signin").WithJSON(auth.SigninRequest{Username: "kataras", Password: "password"}).Expect().
+		Status(httptest.StatusTooManyRequests)
+}
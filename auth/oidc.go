@@ -0,0 +1,473 @@
+//go:build go1.18
+// +build go1.18
+
+package auth
+
+import (
+	stdContext "context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+
+	"github.com/kataras/jwt"
+)
+
+type (
+	// OIDCConfiguration holds the necessary information
+	// for an OpenID Connect relying party (client).
+	//
+	// See the `NewOIDC` package-level function.
+	OIDCConfiguration struct {
+		// Issuer is the identity provider's issuer URL,
+		// its discovery document is loaded from
+		// the Issuer + "/.well-known/openid-configuration" URL.
+		// E.g. https://keycloak.example.com/realms/myrealm or https://dex.example.com.
+		Issuer string `json:"issuer" yaml:"Issuer" toml:"Issuer" ini:"issuer"`
+		// ClientID is the client identifier registered to the identity provider.
+		ClientID string `json:"client_id" yaml:"ClientID" toml:"ClientID" ini:"client_id"`
+		// ClientSecret is the client secret, it can be empty for public clients.
+		ClientSecret string `json:"client_secret" yaml:"ClientSecret" toml:"ClientSecret" ini:"client_secret"`
+		// RedirectURL is the full URL of the route which serves the Auth.SigninHandler,
+		// the identity provider redirects the client back to that URL.
+		RedirectURL string `json:"redirect_url" yaml:"RedirectURL" toml:"RedirectURL" ini:"redirect_url"`
+		// Scopes to request. The "openid" scope is always requested.
+		//
+		// Defaults to: openid, profile, email.
+		Scopes []string `json:"scopes" yaml:"Scopes" toml:"Scopes" ini:"scopes"`
+		// HTTPClient is the client which performs the requests to the identity provider.
+		//
+		// Defaults to http.DefaultClient.
+		HTTPClient *http.Client `json:"-" yaml:"-" toml:"-" ini:"-"`
+	}
+
+	// OIDCDiscovery holds the OpenID Provider metadata
+	// which are required by the authorization code flow.
+	//
+	// See https://openid.net/specs/openid-connect-discovery-1_0.html#ProviderMetadata.
+	OIDCDiscovery struct {
+		Issuer                string `json:"issuer"`
+		AuthorizationEndpoint string `json:"authorization_endpoint"`
+		TokenEndpoint         string `json:"token_endpoint"`
+		UserinfoEndpoint      string `json:"userinfo_endpoint,omitempty"`
+		JWKSURI               string `json:"jwks_uri"`
+		EndSessionEndpoint    string `json:"end_session_endpoint,omitempty"`
+	}
+
+	// OIDCClaimsMapper is the hook which converts a verified ID token to a T value.
+	// Use the tok.Claims method to bind the ID token's claims to a custom struct value.
+	//
+	// The first input argument standard context can be
+	// casted to iris.Context as it's executed through Auth.SigninHandler.
+	OIDCClaimsMapper[T User] func(ctx stdContext.Context, tok *VerifiedToken) (T, error)
+
+	// OIDC is an OpenID Connect relying party Provider of T.
+	// It signs in users through the authorization code flow with PKCE,
+	// verifies the identity provider's ID token against its JSON Web Key Set
+	// and maps the ID token's claims to a T value.
+	//
+	// Register it to an Auth instance through the AddProvider method
+	// and serve the Auth.SigninHandler on a GET route of the OIDCConfiguration.RedirectURL.
+	//
+	// Usage Example:
+	//
+	//	provider, err := auth.NewOIDC(ctx, auth.OIDCConfiguration{
+	//	  Issuer:       "http://localhost:8080/realms/myrealm",
+	//	  ClientID:     "myclient",
+	//	  ClientSecret: "mysecret",
+	//	  RedirectURL:  "http://localhost:3000/signin",
+	//	}, func(ctx context.Context, tok *auth.VerifiedToken) (MyUser, error) {
+	//	  var user MyUser
+	//	  err := tok.Claims(&user)
+	//	  return user, err
+	//	})
+	//	s := auth.Must(auth.New[MyUser](authConfig)).AddProvider(provider)
+	//	app.Get("/signin", s.SigninHandler)
+	OIDC[T User] struct {
+		config    OIDCConfiguration
+		discovery OIDCDiscovery
+		mapClaims OIDCClaimsMapper[T]
+
+		mu             sync.RWMutex
+		keys           jwt.Keys
+		keysFetchedAt  time.Time
+		keysMinRefresh time.Duration
+	}
+
+	// oidcState is the state of an authorization request,
+	// it's stored to the signed cookie until the callback.
+	oidcState struct {
+		State        string `json:"state"`
+		Nonce        string `json:"nonce"`
+		CodeVerifier string `json:"code_verifier"`
+	}
+
+	// oidcTokenResponse is the token endpoint's response.
+	oidcTokenResponse struct {
+		AccessToken      string `json:"access_token"`
+		IDToken          string `json:"id_token"`
+		Error            string `json:"error"`
+		ErrorDescription string `json:"error_description"`
+	}
+
+	// oidcIDTokenClaims holds the ID token claims which are verified
+	// by the relying party, besides the standard ones.
+	oidcIDTokenClaims struct {
+		Nonce           string `json:"nonce"`
+		AuthorizedParty string `json:"azp"`
+	}
+)
+
+var (
+	_ Provider[User]         = (*OIDC[User])(nil)
+	_ RedirectProvider[User] = (*OIDC[User])(nil)
+)
+
+// ErrOIDCPasswordSignin is returned from the OIDC's Signin method,
+// users of an OIDC provider can only sign in through the identity provider.
+var ErrOIDCPasswordSignin = errors.New("oidc: password signin is not supported")
+
+// oidcMaxResponseSize is the maximum size of an identity provider's response body,
+// e.g. the discovery document, the JWKS and the token responses.
+const oidcMaxResponseSize = 1 << 20 // 1MB.
+
+// NewOIDC loads the discovery document of the "config.Issuer"
+// and returns a new OIDC provider of T.
+// The "mapClaims" hook is required, it converts the verified ID token to a T value.
+func NewOIDC[T User](ctx stdContext.Context, config OIDCConfiguration, mapClaims OIDCClaimsMapper[T]) (*OIDC[T], error) {
+	if config.Issuer == "" || config.ClientID == "" || config.RedirectURL == "" {
+		return nil, fmt.Errorf("oidc: issuer, client id and redirect url are required")
+	}
+
+	if mapClaims == nil {
+		return nil, fmt.Errorf("oidc: claims mapper is required")
+	}
+
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{"openid", "profile", "email"}
+	} else if !containsString(config.Scopes, "openid") {
+		config.Scopes = append([]string{"openid"}, config.Scopes...)
+	}
+
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+
+	p := &OIDC[T]{
+		config:         config,
+		mapClaims:      mapClaims,
+		keysMinRefresh: time.Minute,
+	}
+
+	if err := p.discover(ctx); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Discovery returns the loaded discovery document of the identity provider.
+func (p *OIDC[T]) Discovery() OIDCDiscovery {
+	return p.discovery
+}
+
+func (p *OIDC[T]) discover(ctx stdContext.Context) error {
+	discoveryURL := strings.TrimSuffix(p.config.Issuer, "/") + "/.well-known/openid-configuration"
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, discoveryURL, nil)
+	if err != nil {
+		return fmt.Errorf("oidc: discovery: %w", err)
+	}
+
+	var discovery OIDCDiscovery
+	if err = p.do(req, &discovery); err != nil {
+		return fmt.Errorf("oidc: discovery: %w", err)
+	}
+
+	// The issuer of the document MUST be identical to the configured one.
+	if strings.TrimSuffix(discovery.Issuer, "/") != strings.TrimSuffix(p.config.Issuer, "/") {
+		return fmt.Errorf("oidc: discovery: issuer mismatch: expected %q but got %q", p.config.Issuer, discovery.Issuer)
+	}
+
+	if discovery.AuthorizationEndpoint == "" || discovery.TokenEndpoint == "" || discovery.JWKSURI == "" {
+		return fmt.Errorf("oidc: discovery: authorization, token and jwks endpoints are required")
+	}
+
+	p.discovery = discovery
+	return nil
+}
+
+// AuthorizeURL completes the RedirectProvider interface.
+// It generates a new state, nonce and PKCE code verifier and returns
+// the authorization endpoint's URL of the identity provider.
+func (p *OIDC[T]) AuthorizeURL(ctx *context.Context) (string, []byte, error) {
+	state := oidcState{
+		State:        randomString(),
+		Nonce:        randomString(),
+		CodeVerifier: randomString(),
+	}
+
+	stateData, err := json.Marshal(state)
+	if err != nil {
+		return "", nil, fmt.Errorf("oidc: %w", err)
+	}
+
+	challenge := sha256.Sum256([]byte(state.CodeVerifier))
+
+	query := url.Values{}
+	query.Set("response_type", "code")
+	query.Set("client_id", p.config.ClientID)
+	query.Set("redirect_uri", p.config.RedirectURL)
+	query.Set("scope", strings.Join(p.config.Scopes, " "))
+	query.Set("state", state.State)
+	query.Set("nonce", state.Nonce)
+	query.Set("code_challenge", base64.RawURLEncoding.EncodeToString(challenge[:]))
+	query.Set("code_challenge_method", "S256")
+
+	authorizeURL := p.discovery.AuthorizationEndpoint
+	if strings.Contains(authorizeURL, "?") {
+		authorizeURL += "&" + query.Encode()
+	} else {
+		authorizeURL += "?" + query.Encode()
+	}
+
+	return authorizeURL, stateData, nil
+}
+
+// Callback completes the RedirectProvider interface.
+// It validates the state, exchanges the authorization code for the tokens,
+// verifies the ID token and maps its claims to a T value.
+func (p *OIDC[T]) Callback(ctx *context.Context, stateData []byte) (T, error) {
+	var t T
+
+	var state oidcState
+	if err := json.Unmarshal(stateData, &state); err != nil {
+		return t, fmt.Errorf("oidc: state: %w", err)
+	}
+
+	if errCode := ctx.URLParam("error"); errCode != "" {
+		if description := ctx.URLParam("error_description"); description != "" {
+			return t, fmt.Errorf("oidc: %s: %s", errCode, description)
+		}
+
+		return t, fmt.Errorf("oidc: %s", errCode)
+	}
+
+	if subtle.ConstantTimeCompare([]byte(ctx.URLParam("state")), []byte(state.State)) != 1 {
+		return t, fmt.Errorf("oidc: state mismatch")
+	}
+
+	code := ctx.URLParam("code")
+	if code == "" {
+		return t, fmt.Errorf("oidc: missing authorization code")
+	}
+
+	tokens, err := p.exchange(ctx, code, state.CodeVerifier)
+	if err != nil {
+		return t, err
+	}
+
+	verifiedToken, err := p.VerifyIDToken(ctx, []byte(tokens.IDToken), state.Nonce)
+	if err != nil {
+		return t, err
+	}
+
+	return p.mapClaims(ctx, verifiedToken)
+}
+
+func (p *OIDC[T]) exchange(ctx stdContext.Context, code, codeVerifier string) (*oidcTokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.config.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+	if p.config.ClientSecret == "" { // public client.
+		form.Set("client_id", p.config.ClientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.discovery.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("oidc: token: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if p.config.ClientSecret != "" {
+		req.SetBasicAuth(url.QueryEscape(p.config.ClientID), url.QueryEscape(p.config.ClientSecret))
+	}
+
+	var tokens oidcTokenResponse
+	if err = p.do(req, &tokens); err != nil {
+		if tokens.Error != "" {
+			return nil, fmt.Errorf("oidc: token: %s: %s", tokens.Error, tokens.ErrorDescription)
+		}
+
+		return nil, fmt.Errorf("oidc: token: %w", err)
+	}
+
+	if tokens.IDToken == "" {
+		return nil, fmt.Errorf("oidc: token: missing id_token")
+	}
+
+	return &tokens, nil
+}
+
+// VerifyIDToken verifies an ID token issued by the identity provider.
+// It verifies the signature against the JSON Web Key Set of the identity provider,
+// the expiration, issuer and audience claims and, if not empty, the nonce.
+func (p *OIDC[T]) VerifyIDToken(ctx stdContext.Context, token []byte, nonce string) (*VerifiedToken, error) {
+	verifiedToken, err := jwt.VerifyWithHeaderValidator(nil, nil, token, p.validateHeader(ctx))
+	if err != nil {
+		return nil, fmt.Errorf("oidc: id token: %w", err)
+	}
+
+	claims := verifiedToken.StandardClaims
+	if strings.TrimSuffix(claims.Issuer, "/") != strings.TrimSuffix(p.discovery.Issuer, "/") {
+		return nil, fmt.Errorf("oidc: id token: issuer mismatch")
+	}
+
+	if !containsString(claims.Audience, p.config.ClientID) {
+		return nil, fmt.Errorf("oidc: id token: audience mismatch")
+	}
+
+	var idTokenClaims oidcIDTokenClaims
+	if err = verifiedToken.Claims(&idTokenClaims); err != nil {
+		return nil, fmt.Errorf("oidc: id token: %w", err)
+	}
+
+	if len(claims.Audience) > 1 && idTokenClaims.AuthorizedParty != p.config.ClientID {
+		return nil, fmt.Errorf("oidc: id token: authorized party mismatch")
+	}
+
+	if nonce != "" && subtle.ConstantTimeCompare([]byte(idTokenClaims.Nonce), []byte(nonce)) != 1 {
+		return nil, fmt.Errorf("oidc: id token: nonce mismatch")
+	}
+
+	return verifiedToken, nil
+}
+
+// validateHeader returns a jwt header validator which resolves the key
+// of the token's "kid" from the JSON Web Key Set of the identity provider.
+// The key set is fetched again on unknown key ids, e.g. after a keys rotation.
+func (p *OIDC[T]) validateHeader(ctx stdContext.Context) jwt.HeaderValidator {
+	return func(alg string, headerDecoded []byte) (jwt.Alg, jwt.PublicKey, jwt.InjectFunc, error) {
+		p.mu.RLock()
+		keys := p.keys
+		fetchedAt := p.keysFetchedAt
+		p.mu.RUnlock()
+
+		if keys != nil {
+			verifyAlg, publicKey, decrypt, err := keys.ValidateHeader(alg, headerDecoded)
+			if !errors.Is(err, jwt.ErrUnknownKid) || time.Since(fetchedAt) < p.keysMinRefresh {
+				return verifyAlg, publicKey, decrypt, err
+			}
+		}
+
+		keys, err := p.fetchKeys(ctx)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+
+		return keys.ValidateHeader(alg, headerDecoded)
+	}
+}
+
+func (p *OIDC[T]) fetchKeys(ctx stdContext.Context) (jwt.Keys, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.discovery.JWKSURI, nil)
+	if err != nil {
+		return nil, fmt.Errorf("jwks: %w", err)
+	}
+
+	var set jwt.JWKS
+	if err = p.do(req, &set); err != nil {
+		return nil, fmt.Errorf("jwks: %w", err)
+	}
+
+	keys := set.PublicKeys()
+
+	p.mu.Lock()
+	p.keys = keys
+	p.keysFetchedAt = time.Now()
+	p.mu.Unlock()
+
+	return keys, nil
+}
+
+// do sends the request and binds the JSON response body to the "dest".
+// The body is decoded even on error status codes, so error responses can be inspected.
+func (p *OIDC[T]) do(req *http.Request, dest interface{}) error {
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.config.HTTPClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, oidcMaxResponseSize+1))
+	if err != nil {
+		return err
+	}
+
+	if len(body) > oidcMaxResponseSize {
+		return fmt.Errorf("%s: response body exceeds %d bytes", req.URL.Path, oidcMaxResponseSize)
+	}
+
+	if resp.StatusCode >= http.StatusBadRequest {
+		_ = json.Unmarshal(body, dest)
+		return fmt.Errorf("%s: %d: %s", req.URL.Path, resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+
+	return json.Unmarshal(body, dest)
+}
+
+// Signin completes the Provider interface.
+// It always returns ErrOIDCPasswordSignin, so other
+// registered providers are tried on password sign in.
+func (p *OIDC[T]) Signin(ctx stdContext.Context, username, password string) (T, error) {
+	var t T
+	return t, ErrOIDCPasswordSignin
+}
+
+// ValidateToken completes the Provider interface. It does nothing.
+func (p *OIDC[T]) ValidateToken(ctx stdContext.Context, standardClaims StandardClaims, t T) error {
+	return nil
+}
+
+// InvalidateToken completes the Provider interface. It does nothing.
+func (p *OIDC[T]) InvalidateToken(ctx stdContext.Context, standardClaims StandardClaims, t T) error {
+	return nil
+}
+
+// InvalidateTokens completes the Provider interface. It does nothing.
+func (p *OIDC[T]) InvalidateTokens(ctx stdContext.Context, t T) error {
+	return nil
+}
+
+func randomString() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err) // crypto/rand never fails on supported platforms.
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
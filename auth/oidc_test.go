@@ -0,0 +1,192 @@
+//go:build go1.18
+// +build go1.18
+
+package auth_test
+
+import (
+	stdContext "context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/auth"
+	"github.com/kataras/iris/v12/httptest"
+
+	"github.com/iris-contrib/httpexpect/v2"
+	"github.com/kataras/jwt"
+)
+
+type oidcUser struct {
+	ID    string `json:"sub"`
+	Email string `json:"email"`
+}
+
+type mockIssuer struct {
+	*stdhttptest.Server
+	keys jwt.Keys
+
+	mu    sync.Mutex
+	codes map[string]url.Values // code -> authorize request.
+}
+
+// newMockIssuer starts an OpenID Connect identity provider which
+// issues a code for the "sub" user on each authorization request.
+func newMockIssuer(t *testing.T) *mockIssuer {
+	t.Helper()
+
+	privateKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := &mockIssuer{keys: make(jwt.Keys), codes: make(map[string]url.Values)}
+	m.keys.Register(jwt.RS256, "key-1", &privateKey.PublicKey, privateKey)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, map[string]string{
+			"issuer":                 m.URL,
+			"authorization_endpoint": m.URL + "/authorize",
+			"token_endpoint":         m.URL + "/token",
+			"jwks_uri":               m.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		set, err := m.keys.JWKS()
+		if err != nil {
+			t.Error(err)
+		}
+		writeJSON(w, set)
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if clientID, clientSecret, _ := r.BasicAuth(); clientID != "client" || clientSecret != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			writeJSON(w, map[string]string{"error": "invalid_client"})
+			return
+		}
+
+		m.mu.Lock()
+		authorizeRequest, ok := m.codes[r.FormValue("code")]
+		delete(m.codes, r.FormValue("code"))
+		m.mu.Unlock()
+
+		challenge := sha256.Sum256([]byte(r.FormValue("code_verifier")))
+		if !ok || authorizeRequest.Get("code_challenge") != base64.RawURLEncoding.EncodeToString(challenge[:]) {
+			w.WriteHeader(http.StatusBadRequest)
+			writeJSON(w, map[string]string{"error": "invalid_grant"})
+			return
+		}
+
+		idToken, err := m.keys.SignToken("key-1", map[string]interface{}{
+			"nonce": authorizeRequest.Get("nonce"),
+			"email": "kataras2006@hotmail.com",
+		}, jwt.Claims{
+			Issuer:   m.URL,
+			Subject:  "user-1",
+			Audience: jwt.Audience{authorizeRequest.Get("client_id")},
+			Expiry:   time.Now().Add(time.Minute).Unix(),
+		})
+		if err != nil {
+			t.Error(err)
+		}
+
+		writeJSON(w, map[string]string{"access_token": "access", "id_token": string(idToken)})
+	})
+	m.Server = stdhttptest.NewServer(mux)
+	t.Cleanup(m.Close)
+
+	return m
+}
+
+// authorize acts as the user who signs in at the identity provider
+// and returns the callback's query of the authorization request of the "location".
+func (m *mockIssuer) authorize(t *testing.T, location string) url.Values {
+	t.Helper()
+
+	u, err := url.Parse(location)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	query := u.Query()
+	if query.Get("code_challenge_method") != "S256" || query.Get("nonce") == "" || query.Get("state") == "" {
+		t.Fatalf("expected a PKCE authorization request with state and nonce but got: %s", location)
+	}
+
+	code := base64.RawURLEncoding.EncodeToString(jwt.MustGenerateRandom(16))
+
+	m.mu.Lock()
+	m.codes[code] = query
+	m.mu.Unlock()
+
+	return url.Values{"code": {code}, "state": {query.Get("state")}}
+}
+
+func writeJSON(w http.ResponseWriter, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+func TestOIDC(t *testing.T) {
+	issuer := newMockIssuer(t)
+
+	provider, err := auth.NewOIDC(stdContext.Background(), auth.OIDCConfiguration{
+		Issuer:       issuer.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		RedirectURL:  "http://localhost/signin",
+	}, func(ctx stdContext.Context, tok *auth.VerifiedToken) (oidcUser, error) {
+		var user oidcUser
+		err := tok.Claims(&user)
+		return user, err
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := auth.Must(auth.New[oidcUser](auth.MustGenerateConfiguration())).AddProvider(provider)
+
+	app := iris.New()
+	app.Get("/signin", s.SigninHandler)
+	app.Get("/me", s.VerifyHandler(), func(ctx iris.Context) {
+		ctx.JSON(auth.GetUser[oidcUser](ctx))
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://localhost"))
+
+	// Without an authorization request.
+	e.GET("/signin").WithQuery("code", "code").WithQuery("state", "state").Expect().Status(httptest.StatusUnauthorized)
+
+	location := e.GET("/signin").WithRedirectPolicy(httpexpect.DontFollowRedirects).Expect().
+		Status(httptest.StatusFound).Header("Location").Raw()
+
+	// State mismatch.
+	callback := issuer.authorize(t, location)
+	e.GET("/signin").WithQuery("code", callback.Get("code")).WithQuery("state", "invalid").Expect().Status(httptest.StatusUnauthorized)
+
+	location = e.GET("/signin").WithRedirectPolicy(httpexpect.DontFollowRedirects).Expect().
+		Status(httptest.StatusFound).Header("Location").Raw()
+	callback = issuer.authorize(t, location)
+
+	accessToken := e.GET("/signin").WithQueryString(callback.Encode()).Expect().Status(httptest.StatusOK).
+		JSON().Object().Value("access_token").String().NotEmpty().Raw()
+
+	// The state is valid for a single callback.
+	e.GET("/signin").WithQueryString(callback.Encode()).Expect().Status(httptest.StatusUnauthorized)
+
+	e.GET("/me").WithHeader("Authorization", "Bearer "+accessToken).Expect().Status(httptest.StatusOK).
+		JSON().IsEqual(oidcUser{ID: "user-1", Email: "kataras2006@hotmail.com"})
+
+	// An identity provider's error.
+	e.GET("/signin").WithRedirectPolicy(httpexpect.DontFollowRedirects).Expect().Status(httptest.StatusFound)
+	e.GET("/signin").WithQuery("error", "access_denied").Expect().Status(httptest.StatusUnauthorized)
+}
@@ -6,7 +6,9 @@ package auth
 import (
 	stdContext "context"
 
+	"github.com/kataras/iris/v12/auth/totp"
 	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/middleware/bruteforce"
 	"github.com/kataras/iris/v12/x/errors"
 
 	"github.com/kataras/jwt"
@@ -100,6 +102,53 @@ func (fn TransformerFunc[T]) Transform(ctx stdContext.Context, tok *VerifiedToke
 	return fn(ctx, tok)
 }
 
+// RedirectProvider is an optional interface which can be implemented by a Provider
+// to sign in users through an external identity provider instead of a username and password,
+// e.g. the OIDC provider. When a RedirectProvider is registered, GET requests
+// to the Auth.SigninHandler redirect the client to the identity provider and
+// the identity provider's redirect back (callback) to the same handler signs the tokens.
+type RedirectProvider[T User] interface {
+	// AuthorizeURL should return the identity provider's URL the client
+	// should be redirected to and the state of the authorization request.
+	// The state is stored to a short-lived signed cookie and
+	// it's given back to the Callback method.
+	AuthorizeURL(ctx *context.Context) (redirectURL string, state []byte, err error)
+	// Callback is called when the identity provider redirects back
+	// to the Auth.SigninHandler. It accepts the state returned from AuthorizeURL
+	// and should return a valid T value or an error describing the reason of failure.
+	Callback(ctx *context.Context, state []byte) (T, error)
+}
+
+// TOTPProvider is an optional interface which can be implemented by a Provider as well,
+// or set through the Auth.SetTOTPProvider method, to enable two-factor authentication.
+// When a TOTPProvider is registered, users with a TOTP key sign in with two steps:
+// their username and password are exchanged for a short-lived mfa token
+// and then the mfa token along with a TOTP or a recovery code for the access and refresh tokens.
+// See the Auth.SigninMFA method and the totp sub-package for key provisioning.
+//
+// The first input argument standard context can be
+// casted to iris.Context if executed through Auth.SigninHandler.
+type TOTPProvider[T User] interface {
+	// TOTPKey should return the TOTP key of the "t" user
+	// or nil if the user has not enabled two-factor authentication.
+	TOTPKey(ctx stdContext.Context, t T) (*totp.Key, error)
+	// UseTOTPStep should record the time step of a verified TOTP code of the "t" user,
+	// it should atomically return ErrTOTPCodeUsed if the step is equal or older than
+	// the last recorded one, so each code can be used only once.
+	UseTOTPStep(ctx stdContext.Context, t T, step int64) error
+	// UseRecoveryCode should remove the recovery code of the "t" user
+	// which its totp.HashRecoveryCode hash is "codeHash",
+	// it should atomically return ErrRecoveryCodeInvalid if that code does not exist.
+	UseRecoveryCode(ctx stdContext.Context, t T, codeHash string) error
+	// UseMFAAttempt should count a second factor attempt of the "t" user,
+	// it should atomically return ErrMFAAttemptsExceeded if the user has
+	// exceeded the allowed attempts, e.g. 5 attempts per 15 minutes.
+	UseMFAAttempt(ctx stdContext.Context, t T) error
+	// ResetMFAAttempts should reset the counted attempts of the "t" user,
+	// it's called after a successful second factor.
+	ResetMFAAttempts(ctx stdContext.Context, t T) error
+}
+
 // ErrorHandler is an optional interface which can be implemented by a Provider as well.
 //
 // ErrorHandler is the interface which controls the HTTP errors on
@@ -125,6 +174,13 @@ func (e *DefaultErrorHandler) InvalidArgument(ctx *context.Context, err error) {
 }
 
 // Unauthenticated sends 401 (unauthenticated) with the "err" value as its message.
+// It sends 429 (too many requests) instead when the sign in attempts
+// were locked out by the brute-force tracker, see Auth.SetBruteForce.
 func (e *DefaultErrorHandler) Unauthenticated(ctx *context.Context, err error) {
+	if errors.Is(err, bruteforce.ErrLocked) {
+		errors.ResourceExhausted.Err(ctx, err)
+		return
+	}
+
 	errors.Unauthenticated.Err(ctx, err)
 }
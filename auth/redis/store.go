@@ -0,0 +1,188 @@
+//go:build go1.18
+// +build go1.18
+
+package redis
+
+import (
+	"context"
+	"io"
+	"sync/atomic"
+	"time"
+
+	"github.com/kataras/iris/v12/auth"
+	"github.com/kataras/iris/v12/core/host"
+
+	"github.com/redis/go-redis/v9"
+)
+
+type (
+	// Options is just a type alias for the go-redis Client Options.
+	Options = redis.Options
+	// ClusterOptions is just a type alias for the go-redis Cluster Client Options.
+	ClusterOptions = redis.ClusterOptions
+)
+
+// Client is the interface which both
+// go-redis Client and Cluster Client implements.
+type Client interface {
+	redis.Cmdable // Commands.
+	io.Closer     // CloseConnection.
+}
+
+// RefreshTokenStore is an auth.RefreshTokenStore backed by Redis
+// or any other database which speaks the Redis protocol.
+//
+// It stores the following keys, each one expires along with its refresh token:
+//   - {Prefix}token:{id} holds the family of an issued refresh token.
+//   - {Prefix}used:{id} exists if the refresh token is used.
+//   - {Prefix}family:{family} expires along with the latest refresh token of the family.
+//   - {Prefix}revoked:{family} exists if the family is revoked.
+type RefreshTokenStore struct {
+	// Prefix the keys into the redis database.
+	// Note that if you can also select a different database
+	// through ClientOptions (or ClusterOptions).
+	// Defaults to "iris_auth_".
+	Prefix string
+	// RevokeDuration is the expiration of a revoked family
+	// when the expiration of its tokens is unknown (e.g. refresh tokens without max age).
+	// Defaults to 30 days.
+	RevokeDuration time.Duration
+	// Both Client and ClusterClient implements this interface.
+	client    Client
+	connected uint32
+	// Customize any go-redis fields manually
+	// before Connect.
+	ClientOptions  Options
+	ClusterOptions ClusterOptions
+}
+
+var _ auth.RefreshTokenStore = (*RefreshTokenStore)(nil)
+
+// NewRefreshTokenStore returns a new redis-based auth.RefreshTokenStore.
+// Modify its ClientOptions or ClusterOptions depending the application needs
+// and call its Connect.
+//
+// Usage:
+//
+//	store := NewRefreshTokenStore()
+//	store.ClientOptions.Addr = ...
+//	err := store.Connect()
+//
+// And register it:
+//
+//	s := auth.Must(auth.New[MyUser](config)).SetRefreshTokenStore(store)
+func NewRefreshTokenStore() *RefreshTokenStore {
+	return &RefreshTokenStore{
+		Prefix:         "iris_auth_",
+		RevokeDuration: 30 * 24 * time.Hour,
+		ClientOptions: Options{
+			Addr: "127.0.0.1:6379",
+			// The rest are defaulted to good values already.
+		},
+		// If its Addrs > 0 before connect then cluster client is used instead.
+		ClusterOptions: ClusterOptions{},
+	}
+}
+
+// Connect prepares the redis client and fires a ping response to it.
+func (s *RefreshTokenStore) Connect() error {
+	if len(s.ClusterOptions.Addrs) > 0 {
+		// Use cluster client.
+		s.client = redis.NewClusterClient(&s.ClusterOptions)
+	} else {
+		s.client = redis.NewClient(&s.ClientOptions)
+	}
+
+	_, err := s.client.Ping(context.Background()).Result()
+	if err != nil {
+		return err
+	}
+
+	host.RegisterOnInterrupt(func() {
+		atomic.StoreUint32(&s.connected, 0)
+		s.client.Close()
+	})
+	atomic.StoreUint32(&s.connected, 1)
+
+	return nil
+}
+
+// IsConnected reports whether the Connect function was called.
+func (s *RefreshTokenStore) IsConnected() bool {
+	return atomic.LoadUint32(&s.connected) > 0
+}
+
+// Issue records the refresh token "id" of the "family".
+func (s *RefreshTokenStore) Issue(ctx context.Context, family, id string, expiresAt time.Time) error {
+	var ttl time.Duration // zero for no expiration.
+	if !expiresAt.IsZero() {
+		if ttl = time.Until(expiresAt); ttl <= 0 {
+			return nil // already expired.
+		}
+	}
+
+	_, err := s.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.Set(ctx, s.Prefix+"token:"+id, family, ttl)
+		pipe.Set(ctx, s.Prefix+"family:"+family, "", ttl)
+		return nil
+	})
+	return err
+}
+
+// Use marks the refresh token "id" as used and returns its family.
+func (s *RefreshTokenStore) Use(ctx context.Context, id string) (string, error) {
+	tokenKey := s.Prefix + "token:" + id
+
+	family, err := s.client.Get(ctx, tokenKey).Result()
+	if err != nil {
+		if err == redis.Nil {
+			return "", auth.ErrRefreshTokenUnknown
+		}
+
+		return "", err
+	}
+
+	revoked, err := s.client.Exists(ctx, s.Prefix+"revoked:"+family).Result()
+	if err != nil {
+		return "", err
+	}
+	if revoked > 0 {
+		return family, auth.ErrRefreshTokenRevoked
+	}
+
+	ttl, err := s.client.PTTL(ctx, tokenKey).Result()
+	if err != nil {
+		return "", err
+	}
+	if ttl < 0 { // no expiration.
+		ttl = 0
+	}
+
+	// SETNX is the atomic point: only the first request can use the token.
+	ok, err := s.client.SetNX(ctx, s.Prefix+"used:"+id, "", ttl).Result()
+	if err != nil {
+		return "", err
+	}
+	if !ok {
+		return family, auth.ErrRefreshTokenReused
+	}
+
+	return family, nil
+}
+
+// RevokeFamily revokes all the refresh tokens of the "family".
+func (s *RefreshTokenStore) RevokeFamily(ctx context.Context, family string) error {
+	ttl, err := s.client.PTTL(ctx, s.Prefix+"family:"+family).Result()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case ttl == -1: // no expiration.
+		ttl = 0
+	case ttl < 0: // missing.
+		ttl = s.RevokeDuration
+	}
+
+	return s.client.Set(ctx, s.Prefix+"revoked:"+family, "", ttl).Err()
+}
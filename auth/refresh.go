@@ -0,0 +1,210 @@
+//go:build go1.18
+// +build go1.18
+
+package auth
+
+import (
+	stdContext "context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrRefreshTokenReused is returned from a RefreshTokenStore when
+	// an already used refresh token is presented again. The Auth revokes the whole family
+	// of the token and calls the refresh token reuse handler.
+	ErrRefreshTokenReused = errors.New("refresh token reused")
+	// ErrRefreshTokenRevoked is returned from a RefreshTokenStore when
+	// the family of the presented refresh token is revoked.
+	ErrRefreshTokenRevoked = errors.New("refresh token revoked")
+	// ErrRefreshTokenUnknown is returned from a RefreshTokenStore when
+	// the presented refresh token was not issued through the store.
+	ErrRefreshTokenUnknown = errors.New("refresh token unknown")
+)
+
+type (
+	// RefreshTokenStore records the refresh tokens issued by an Auth instance,
+	// so each refresh token can be used only once.
+	//
+	// Every sign in starts a new family of refresh tokens and each refresh
+	// issues a new refresh token of the same family. Presenting an already
+	// used refresh token is treated as a token theft: the whole family is revoked,
+	// so both the attacker and the legitimate user have to sign in again.
+	//
+	// Set a store through the Auth.SetRefreshTokenStore method.
+	// See the NewMemoryRefreshTokenStore package-level function and
+	// the auth/redis sub-package for the builtin implementations.
+	RefreshTokenStore interface {
+		// Issue should record the refresh token "id" of the "family"
+		// which expires at "expiresAt" (zero for no expiration).
+		Issue(ctx stdContext.Context, family, id string, expiresAt time.Time) error
+		// Use should mark the refresh token "id" as used and return its family.
+		// It should return ErrRefreshTokenUnknown if the token was not issued,
+		// ErrRefreshTokenRevoked if its family is revoked and
+		// ErrRefreshTokenReused (along with the family) if the token was already used.
+		Use(ctx stdContext.Context, id string) (family string, err error)
+		// RevokeFamily should revoke all the refresh tokens of the "family".
+		RevokeFamily(ctx stdContext.Context, family string) error
+	}
+
+	// RefreshTokenReuseHandler is called when an already used refresh token is presented,
+	// after its family is revoked. It can be used to audit token thefts.
+	//
+	// The first input argument standard context can be
+	// casted to iris.Context if executed through Auth.RefreshHandler.
+	RefreshTokenReuseHandler[T User] func(ctx stdContext.Context, family string, standardClaims StandardClaims, t T)
+)
+
+// SetRefreshTokenStore sets a refresh token store to this Auth of T instance and returns itself.
+// When a store is set, refresh tokens are rotated on each refresh and
+// a reused refresh token revokes its whole family.
+// Look the RefreshTokenStore godoc for more.
+func (s *Auth[T]) SetRefreshTokenStore(store RefreshTokenStore) *Auth[T] {
+	s.refreshTokenStore = store
+	return s
+}
+
+// SetRefreshTokenReuseHandler sets a handler which is called
+// when a reused refresh token is detected and returns itself.
+// Look the RefreshTokenReuseHandler godoc for more.
+func (s *Auth[T]) SetRefreshTokenReuseHandler(handler RefreshTokenReuseHandler[T]) *Auth[T] {
+	s.refreshTokenReuseHandler = handler
+	return s
+}
+
+// useRefreshToken marks the verified refresh token as used and returns its family.
+// On reuse it revokes the family and calls the reuse handler.
+func (s *Auth[T]) useRefreshToken(ctx stdContext.Context, standardClaims StandardClaims, t T) (string, error) {
+	family, err := s.refreshTokenStore.Use(ctx, standardClaims.ID)
+	if err != nil {
+		if errors.Is(err, ErrRefreshTokenReused) {
+			if revokeErr := s.refreshTokenStore.RevokeFamily(ctx, family); revokeErr != nil {
+				err = errors.Join(err, revokeErr)
+			}
+
+			if s.refreshTokenReuseHandler != nil {
+				s.refreshTokenReuseHandler(ctx, family, standardClaims, t)
+			}
+		}
+
+		return "", err
+	}
+
+	return family, nil
+}
+
+type (
+	memoryRefreshTokenStore struct {
+		mu       sync.Mutex
+		tokens   map[string]*memoryRefreshToken  // id -> token.
+		families map[string]*memoryRefreshFamily // family -> family.
+		gcAt     time.Time
+	}
+
+	memoryRefreshToken struct {
+		family    string
+		used      bool
+		expiresAt time.Time
+	}
+
+	memoryRefreshFamily struct {
+		revoked   bool
+		expiresAt time.Time // the expiration of its latest token.
+	}
+)
+
+var _ RefreshTokenStore = (*memoryRefreshTokenStore)(nil)
+
+// memoryRefreshTokenStoreGCInterval is the minimum interval between
+// two removals of the expired tokens of the memory store.
+var memoryRefreshTokenStoreGCInterval = time.Minute
+
+// NewMemoryRefreshTokenStore returns a new in-memory RefreshTokenStore.
+// Expired tokens and families are removed periodically.
+// Note that the refresh tokens are lost on server restarts,
+// so all users have to sign in again.
+//
+// Usage:
+//
+//	s.SetRefreshTokenStore(auth.NewMemoryRefreshTokenStore())
+func NewMemoryRefreshTokenStore() RefreshTokenStore {
+	return &memoryRefreshTokenStore{
+		tokens:   make(map[string]*memoryRefreshToken),
+		families: make(map[string]*memoryRefreshFamily),
+	}
+}
+
+func (m *memoryRefreshTokenStore) Issue(_ stdContext.Context, family, id string, expiresAt time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.gc(now)
+
+	m.tokens[id] = &memoryRefreshToken{family: family, expiresAt: expiresAt}
+
+	if f, ok := m.families[family]; !ok {
+		m.families[family] = &memoryRefreshFamily{expiresAt: expiresAt}
+	} else if !f.expiresAt.IsZero() && (expiresAt.IsZero() || expiresAt.After(f.expiresAt)) {
+		f.expiresAt = expiresAt
+	}
+
+	return nil
+}
+
+func (m *memoryRefreshTokenStore) Use(_ stdContext.Context, id string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	token, ok := m.tokens[id]
+	if !ok || expired(token.expiresAt, time.Now()) {
+		return "", ErrRefreshTokenUnknown
+	}
+
+	if f, ok := m.families[token.family]; ok && f.revoked {
+		return token.family, ErrRefreshTokenRevoked
+	}
+
+	if token.used {
+		return token.family, ErrRefreshTokenReused
+	}
+
+	token.used = true
+	return token.family, nil
+}
+
+func (m *memoryRefreshTokenStore) RevokeFamily(_ stdContext.Context, family string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if f, ok := m.families[family]; ok {
+		f.revoked = true
+	}
+
+	return nil
+}
+
+// gc removes the expired tokens and families.
+func (m *memoryRefreshTokenStore) gc(now time.Time) {
+	if now.Sub(m.gcAt) < memoryRefreshTokenStoreGCInterval {
+		return
+	}
+	m.gcAt = now
+
+	for id, token := range m.tokens {
+		if expired(token.expiresAt, now) {
+			delete(m.tokens, id)
+		}
+	}
+
+	for family, f := range m.families {
+		if expired(f.expiresAt, now) {
+			delete(m.families, family)
+		}
+	}
+}
+
+func expired(expiresAt, now time.Time) bool {
+	return !expiresAt.IsZero() && now.After(expiresAt)
+}
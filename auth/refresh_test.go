@@ -0,0 +1,86 @@
+//go:build go1.18
+// +build go1.18
+
+package auth_test
+
+import (
+	stdContext "context"
+	"errors"
+	"testing"
+
+	"github.com/kataras/iris/v12/auth"
+)
+
+type refreshUser struct {
+	Username string `json:"username"`
+}
+
+type refreshUserProvider struct{}
+
+func (refreshUserProvider) Signin(_ stdContext.Context, username, password string) (refreshUser, error) {
+	if password != "password" {
+		return refreshUser{}, errors.New("invalid credentials")
+	}
+
+	return refreshUser{Username: username}, nil
+}
+
+func (refreshUserProvider) ValidateToken(stdContext.Context, auth.StandardClaims, refreshUser) error {
+	return nil
+}
+
+func (refreshUserProvider) InvalidateToken(stdContext.Context, auth.StandardClaims, refreshUser) error {
+	return nil
+}
+
+func (refreshUserProvider) InvalidateTokens(stdContext.Context, refreshUser) error {
+	return nil
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	var reused []string
+
+	s := auth.Must(auth.New[refreshUser](auth.MustGenerateConfiguration())).
+		AddProvider(refreshUserProvider{}).
+		SetRefreshTokenStore(auth.NewMemoryRefreshTokenStore()).
+		SetRefreshTokenReuseHandler(func(ctx stdContext.Context, family string, claims auth.StandardClaims, u refreshUser) {
+			reused = append(reused, u.Username)
+		})
+
+	ctx := stdContext.Background()
+
+	_, refreshToken, err := s.Signin(ctx, "kataras", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, rotatedRefreshToken, err := s.Refresh(ctx, refreshToken)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The access token is not a refresh token of the store.
+	accessToken, otherRefreshToken, err := s.Signin(ctx, "makis", "password")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err = s.Refresh(ctx, accessToken); !errors.Is(err, auth.ErrRefreshTokenUnknown) {
+		t.Fatalf("expected error: %v but got: %v", auth.ErrRefreshTokenUnknown, err)
+	}
+
+	// Reuse revokes the whole family.
+	if _, _, err = s.Refresh(ctx, refreshToken); !errors.Is(err, auth.ErrRefreshTokenReused) {
+		t.Fatalf("expected error: %v but got: %v", auth.ErrRefreshTokenReused, err)
+	}
+	if len(reused) != 1 || reused[0] != "kataras" {
+		t.Fatalf("expected the reuse handler to be called once for kataras but got: %v", reused)
+	}
+	if _, _, err = s.Refresh(ctx, rotatedRefreshToken); !errors.Is(err, auth.ErrRefreshTokenRevoked) {
+		t.Fatalf("expected error: %v but got: %v", auth.ErrRefreshTokenRevoked, err)
+	}
+
+	// Other families are not affected.
+	if _, _, err = s.Refresh(ctx, otherRefreshToken); err != nil {
+		t.Fatal(err)
+	}
+}
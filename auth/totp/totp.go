@@ -0,0 +1,285 @@
+// Package totp implements the Time-Based One-Time Password algorithm (RFC 6238)
+// which is used as a second authentication factor by the auth package
+// and the basicauth middleware. It is compatible with the common authenticator applications.
+package totp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"encoding/base32"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Algorithm is the HMAC hash function of a Key.
+type Algorithm string
+
+// The supported algorithms.
+// Most authenticator applications only support the SHA1 one.
+const (
+	SHA1   Algorithm = "SHA1"
+	SHA256 Algorithm = "SHA256"
+	SHA512 Algorithm = "SHA512"
+)
+
+const (
+	// DefaultDigits is the default number of digits of a code.
+	DefaultDigits = 6
+	// DefaultPeriod is the default time step of a code.
+	DefaultPeriod = 30 * time.Second
+	// DefaultSkew is the default number of time steps, before and after the current one,
+	// which their codes are accepted too, so clock drifts between the server and the clients are tolerated.
+	DefaultSkew = 1
+	// SecretSize is the size, in bytes, of the generated secrets.
+	SecretSize = 20
+)
+
+var (
+	// ErrInvalidCode is returned when a code does not match.
+	ErrInvalidCode = errors.New("totp: invalid code")
+	// ErrInvalidKey is returned when a key's secret or algorithm is invalid.
+	ErrInvalidKey = errors.New("totp: invalid key")
+)
+
+var secretEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Key holds the shared secret and the parameters of a user's TOTP generator.
+// It should be stored, encrypted, next to the user.
+// Zero Algorithm, Digits and Period fields fall back to their defaults.
+type Key struct {
+	// Issuer is the name of the application, e.g. "Iris".
+	Issuer string `json:"issuer" yaml:"Issuer"`
+	// AccountName is the user's account, e.g. "kataras2006@hotmail.com".
+	AccountName string `json:"account_name" yaml:"AccountName"`
+	// Secret is the base32-encoded (without padding) shared secret.
+	Secret string `json:"secret" yaml:"Secret"`
+	// Algorithm defaults to SHA1.
+	Algorithm Algorithm `json:"algorithm,omitempty" yaml:"Algorithm"`
+	// Digits defaults to 6.
+	Digits int `json:"digits,omitempty" yaml:"Digits"`
+	// Period defaults to 30 seconds.
+	Period time.Duration `json:"period,omitempty" yaml:"Period"`
+}
+
+// Generate returns a new Key with a random secret and the default parameters.
+// Show its URI to the user, e.g. as a QR code, so it can be added to
+// an authenticator application and ask for a code to confirm the enrollment
+// before storing it.
+func Generate(issuer, accountName string) (*Key, error) {
+	secret := make([]byte, SecretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, fmt.Errorf("totp: generate: %w", err)
+	}
+
+	key := &Key{
+		Issuer:      issuer,
+		AccountName: accountName,
+		Secret:      secretEncoding.EncodeToString(secret),
+		Algorithm:   SHA1,
+		Digits:      DefaultDigits,
+		Period:      DefaultPeriod,
+	}
+	return key, nil
+}
+
+// URI returns the otpauth:// key URI of the key, see
+// https://github.com/google/google-authenticator/wiki/Key-Uri-Format.
+func (k *Key) URI() string {
+	label := url.PathEscape(k.AccountName)
+	if k.Issuer != "" {
+		label = url.PathEscape(k.Issuer) + ":" + label
+	}
+
+	query := url.Values{}
+	query.Set("secret", k.Secret)
+	if k.Issuer != "" {
+		query.Set("issuer", k.Issuer)
+	}
+	query.Set("algorithm", string(k.algorithm()))
+	query.Set("digits", strconv.Itoa(k.digits()))
+	query.Set("period", strconv.Itoa(int(k.period().Seconds())))
+
+	return "otpauth://totp/" + label + "?" + query.Encode()
+}
+
+// Step returns the time step of "t".
+func (k *Key) Step(t time.Time) int64 {
+	return t.Unix() / int64(k.period().Seconds())
+}
+
+// Code returns the code of "t".
+func (k *Key) Code(t time.Time) (string, error) {
+	return k.code(k.Step(t))
+}
+
+// Validate reports whether the "code" is valid at "t",
+// the codes of "skew" time steps before and after "t" are accepted too.
+// It returns the time step of the matched code or ErrInvalidCode.
+//
+// A code is valid for its whole time step, so callers must record the
+// returned step of the user and reject codes of equal or older steps (replay prevention).
+func (k *Key) Validate(code string, t time.Time, skew int) (int64, error) {
+	code = strings.TrimSpace(code)
+	if len(code) != k.digits() || !isDigits(code) {
+		return 0, ErrInvalidCode
+	}
+
+	if skew < 0 {
+		skew = 0
+	}
+
+	step := k.Step(t)
+	for i := -int64(skew); i <= int64(skew); i++ {
+		expected, err := k.code(step + i)
+		if err != nil {
+			return 0, err
+		}
+
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return step + i, nil
+		}
+	}
+
+	return 0, ErrInvalidCode
+}
+
+// code implements the HOTP (RFC 4226) of the time step counter.
+func (k *Key) code(step int64) (string, error) {
+	secret, err := secretEncoding.DecodeString(strings.ToUpper(strings.TrimRight(k.Secret, "=")))
+	if err != nil || len(secret) == 0 {
+		return "", ErrInvalidKey
+	}
+
+	var newHash func() hash.Hash
+	switch k.algorithm() {
+	case SHA1:
+		newHash = sha1.New
+	case SHA256:
+		newHash = sha256.New
+	case SHA512:
+		newHash = sha512.New
+	default:
+		return "", ErrInvalidKey
+	}
+
+	var counter [8]byte
+	binary.BigEndian.PutUint64(counter[:], uint64(step))
+
+	mac := hmac.New(newHash, secret)
+	mac.Write(counter[:])
+	sum := mac.Sum(nil)
+
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+
+	digits := k.digits()
+	mod := uint32(1)
+	for i := 0; i < digits; i++ {
+		mod *= 10
+	}
+
+	return fmt.Sprintf("%0*d", digits, value%mod), nil
+}
+
+func (k *Key) algorithm() Algorithm {
+	if k.Algorithm == "" {
+		return SHA1
+	}
+
+	return Algorithm(strings.ToUpper(string(k.Algorithm)))
+}
+
+func (k *Key) digits() int {
+	if k.Digits <= 0 || k.Digits > 9 {
+		return DefaultDigits
+	}
+
+	return k.Digits
+}
+
+func (k *Key) period() time.Duration {
+	if k.Period < time.Second {
+		return DefaultPeriod
+	}
+
+	return k.Period
+}
+
+// IsCode reports whether the "code" has the format of a code of the key,
+// e.g. to tell it apart from a recovery code.
+func (k *Key) IsCode(code string) bool {
+	code = strings.TrimSpace(code)
+	return len(code) == k.digits() && isDigits(code)
+}
+
+func isDigits(s string) bool {
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+
+	return len(s) > 0
+}
+
+// recoveryCodeAlphabet excludes the ambiguous 0, 1, i, l, o characters.
+const recoveryCodeAlphabet = "23456789abcdefghjkmnpqrstuvwxyz"
+
+// GenerateRecoveryCodes returns "n" random single-use recovery codes
+// of the form "xxxxx-xxxxx". Show them once to the user and
+// store only their HashRecoveryCode hashes.
+func GenerateRecoveryCodes(n int) ([]string, error) {
+	codes := make([]string, 0, n)
+	buf := make([]byte, 10)
+	for i := 0; i < n; i++ {
+		if _, err := rand.Read(buf); err != nil {
+			return nil, fmt.Errorf("totp: recovery codes: %w", err)
+		}
+
+		var b strings.Builder
+		for j, c := range buf {
+			if j == 5 {
+				b.WriteByte('-')
+			}
+			// 248 is the largest multiple of the alphabet's length (31) below 256,
+			// draw again the biased values.
+			for c >= 248 {
+				var one [1]byte
+				if _, err := rand.Read(one[:]); err != nil {
+					return nil, fmt.Errorf("totp: recovery codes: %w", err)
+				}
+				c = one[0]
+			}
+			b.WriteByte(recoveryCodeAlphabet[int(c)%len(recoveryCodeAlphabet)])
+		}
+
+		codes = append(codes, b.String())
+	}
+
+	return codes, nil
+}
+
+// HashRecoveryCode returns the hex-encoded SHA-256 hash of the normalized "code",
+// the case, spaces and dashes of the user input are ignored.
+func HashRecoveryCode(code string) string {
+	normalized := strings.Map(func(r rune) rune {
+		if r == '-' || r == ' ' {
+			return -1
+		}
+		return r
+	}, strings.ToLower(strings.TrimSpace(code)))
+
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,139 @@
+package totp
+
+import (
+	"encoding/base32"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// See https://www.rfc-editor.org/rfc/rfc6238#appendix-B.
+func TestKeyCode(t *testing.T) {
+	secrets := map[Algorithm]string{
+		SHA1:   "12345678901234567890",
+		SHA256: "12345678901234567890123456789012",
+		SHA512: "1234567890123456789012345678901234567890123456789012345678901234",
+	}
+
+	tests := []struct {
+		unix      int64
+		algorithm Algorithm
+		code      string
+	}{
+		{59, SHA1, "94287082"},
+		{59, SHA256, "46119246"},
+		{59, SHA512, "90693936"},
+		{1111111109, SHA1, "07081804"},
+		{1111111109, SHA256, "68084774"},
+		{1111111109, SHA512, "25091201"},
+		{20000000000, SHA1, "65353130"},
+		{20000000000, SHA256, "77737706"},
+		{20000000000, SHA512, "47863826"},
+	}
+
+	for i, tt := range tests {
+		key := &Key{
+			Secret:    base32.StdEncoding.EncodeToString([]byte(secrets[tt.algorithm])),
+			Algorithm: tt.algorithm,
+			Digits:    8,
+		}
+
+		code, err := key.Code(time.Unix(tt.unix, 0))
+		if err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		if code != tt.code {
+			t.Fatalf("[%d] expected code: %s but got: %s", i, tt.code, code)
+		}
+	}
+}
+
+func TestKeyValidate(t *testing.T) {
+	key, err := Generate("Iris", "kataras2006@hotmail.com")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	now := time.Now()
+	for _, offset := range []time.Duration{-DefaultPeriod, 0, DefaultPeriod} {
+		code, err := key.Code(now.Add(offset))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		step, err := key.Validate(code, now, DefaultSkew)
+		if err != nil {
+			t.Fatalf("[%s] %v", offset, err)
+		}
+
+		if expected := key.Step(now.Add(offset)); step != expected {
+			t.Fatalf("[%s] expected step: %d but got: %d", offset, expected, step)
+		}
+	}
+
+	code, _ := key.Code(now.Add(-2 * DefaultPeriod))
+	if _, err = key.Validate(code, now, DefaultSkew); err != ErrInvalidCode {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidCode, err)
+	}
+
+	for _, code := range []string{"", "12345", "1234567", "abcdef"} {
+		if _, err = key.Validate(code, now, DefaultSkew); err != ErrInvalidCode {
+			t.Fatalf("[%s] expected error: %v but got: %v", code, ErrInvalidCode, err)
+		}
+	}
+}
+
+func TestKeyURI(t *testing.T) {
+	key := &Key{Issuer: "Iris Web", AccountName: "kataras2006@hotmail.com", Secret: "JBSWY3DPEHPK3PXP"}
+
+	u, err := url.Parse(key.URI())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u.Scheme != "otpauth" || u.Host != "totp" {
+		t.Fatalf("unexpected uri: %s", key.URI())
+	}
+
+	if expected := "/Iris Web:kataras2006@hotmail.com"; u.Path != expected {
+		t.Fatalf("expected label: %s but got: %s", expected, u.Path)
+	}
+
+	query := u.Query()
+	for k, expected := range map[string]string{
+		"secret":    "JBSWY3DPEHPK3PXP",
+		"issuer":    "Iris Web",
+		"algorithm": "SHA1",
+		"digits":    "6",
+		"period":    "30",
+	} {
+		if got := query.Get(k); got != expected {
+			t.Fatalf("expected %s: %s but got: %s", k, expected, got)
+		}
+	}
+}
+
+func TestRecoveryCodes(t *testing.T) {
+	codes, err := GenerateRecoveryCodes(10)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	seen := make(map[string]struct{})
+	for _, code := range codes {
+		if len(code) != 11 || code[5] != '-' {
+			t.Fatalf("unexpected recovery code format: %s", code)
+		}
+
+		if _, ok := seen[code]; ok {
+			t.Fatalf("duplicated recovery code: %s", code)
+		}
+		seen[code] = struct{}{}
+
+		if HashRecoveryCode(code) != HashRecoveryCode(" "+strings.ToUpper(strings.ReplaceAll(code, "-", ""))) {
+			t.Fatalf("expected the hash to ignore the case and dashes of: %s", code)
+		}
+	}
+}
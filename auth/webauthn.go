@@ -0,0 +1,1008 @@
+//go:build go1.18
+// +build go1.18
+
+package auth
+
+import (
+	"bytes"
+	stdContext "context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"crypto/subtle"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// ErrWebAuthnCredentialNotFound should be returned from
+// a WebAuthnStore when a credential does not exist.
+var ErrWebAuthnCredentialNotFound = errors.New("webauthn: credential not found")
+
+// ErrWebAuthnPasswordSignin is returned from the WebAuthn's Signin method,
+// users of a WebAuthn provider can only sign in through their authenticators.
+var ErrWebAuthnPasswordSignin = errors.New("webauthn: password signin is not supported")
+
+// ErrWebAuthnUnauthenticated is returned from the WebAuthn's registration methods
+// when the request has no verified user, see Auth.VerifyHandler.
+var ErrWebAuthnUnauthenticated = errors.New("webauthn: registration requires a verified user")
+
+// The COSE algorithm identifiers which are supported by the WebAuthn provider.
+// See https://www.iana.org/assignments/cose/cose.xhtml#algorithms.
+const (
+	COSEAlgES256 int64 = -7
+	COSEAlgES384 int64 = -35
+	COSEAlgES512 int64 = -36
+	COSEAlgEdDSA int64 = -8
+	COSEAlgRS256 int64 = -257
+	COSEAlgPS256 int64 = -37
+)
+
+const (
+	webAuthnCeremonyRegistration   = "registration"
+	webAuthnCeremonyAuthentication = "authentication"
+
+	webAuthnFlagUserPresent  byte = 0x01
+	webAuthnFlagUserVerified byte = 0x04
+	webAuthnFlagAttestedData byte = 0x40
+)
+
+type (
+	// WebAuthnConfiguration holds the necessary information
+	// for a WebAuthn relying party.
+	//
+	// See the `NewWebAuthn` package-level function.
+	WebAuthnConfiguration struct {
+		// RPID is the relying party identifier, the domain of the application,
+		// e.g. "example.com". Credentials are scoped to it.
+		RPID string `json:"rp_id" yaml:"RPID" toml:"RPID" ini:"rp_id"`
+		// RPName is the human-palatable name of the relying party, e.g. "Example".
+		//
+		// Defaults to the RPID.
+		RPName string `json:"rp_name" yaml:"RPName" toml:"RPName" ini:"rp_name"`
+		// Origins are the allowed origins of the ceremonies, e.g. "https://example.com".
+		Origins []string `json:"origins" yaml:"Origins" toml:"Origins" ini:"origins"`
+		// Timeout of the ceremonies.
+		//
+		// Defaults to 5 minutes.
+		Timeout time.Duration `json:"timeout" yaml:"Timeout" toml:"Timeout" ini:"timeout"`
+		// UserVerification is the user verification requirement
+		// of the ceremonies: "required", "preferred" or "discouraged".
+		// When "required" the authenticator's user verified flag is checked.
+		//
+		// Defaults to "preferred".
+		UserVerification string `json:"user_verification" yaml:"UserVerification" toml:"UserVerification" ini:"user_verification"`
+	}
+
+	// WebAuthnCredential is a public key credential (passkey) registered by a user.
+	WebAuthnCredential struct {
+		// ID is the credential ID generated by the authenticator.
+		ID []byte `json:"id"`
+		// UserHandle is the user handle of the credential's user.
+		UserHandle []byte `json:"user_handle"`
+		// PublicKey is the COSE-encoded public key of the credential.
+		PublicKey []byte `json:"public_key"`
+		// SignCount is the last signature counter of the authenticator.
+		SignCount uint32 `json:"sign_count"`
+		// AAGUID is the authenticator's model identifier.
+		AAGUID []byte `json:"aaguid"`
+		// AttestationFormat is the attestation statement format
+		// of the registration, "none" or "packed".
+		AttestationFormat string `json:"attestation_format"`
+		// CreatedAt is the registration time.
+		CreatedAt time.Time `json:"created_at"`
+	}
+
+	// WebAuthnStore is the interface which should be completed by a custom value type
+	// to store the users and their WebAuthn credentials.
+	WebAuthnStore[T User] interface {
+		// FindUser should return the user of the "username" and its user handle.
+		// The user handle is an opaque and unique identifier of up to 64 bytes,
+		// it should not contain personal information (e.g. the username or email).
+		FindUser(ctx stdContext.Context, username string) (T, []byte, error)
+		// UserHandle should return the name and the user handle of the "user",
+		// which is the verified user (see Auth.VerifyHandler) of a registration ceremony.
+		UserHandle(ctx stdContext.Context, user T) (string, []byte, error)
+		// FindUserByHandle should return the user of the "userHandle".
+		FindUserByHandle(ctx stdContext.Context, userHandle []byte) (T, error)
+		// Credentials should return the registered credentials of the "userHandle".
+		Credentials(ctx stdContext.Context, userHandle []byte) ([]*WebAuthnCredential, error)
+		// FindCredential should return the credential of the "id"
+		// or ErrWebAuthnCredentialNotFound.
+		FindCredential(ctx stdContext.Context, id []byte) (*WebAuthnCredential, error)
+		// SaveCredential should store a new credential or update
+		// an existing one (e.g. its SignCount).
+		SaveCredential(ctx stdContext.Context, credential *WebAuthnCredential) error
+	}
+
+	// WebAuthn is a passwordless (passkeys) Provider of T.
+	// It implements the registration and the authentication ceremonies
+	// of the Web Authentication API (https://www.w3.org/TR/webauthn-2).
+	// The challenges are stored to the short-lived signed cookie of the Auth instance
+	// and a successful authentication signs the Auth's tokens, exactly like the SigninHandler does.
+	//
+	// The "none" and "packed" attestation statement formats are supported,
+	// the attestation certificates are not validated against a trust anchor.
+	//
+	// Usage Example:
+	//
+	//	s := auth.Must(auth.New[MyUser](authConfig)).AddProvider(passwordProvider)
+	//	w, err := auth.NewWebAuthn(s, auth.WebAuthnConfiguration{
+	//	  RPID:    "example.com",
+	//	  Origins: []string{"https://example.com"},
+	//	}, store)
+	//	app.Post("/webauthn/register/begin", s.VerifyHandler(), w.BeginRegistrationHandler)
+	//	app.Post("/webauthn/register/finish", s.VerifyHandler(), w.FinishRegistrationHandler)
+	//	app.Post("/webauthn/signin/begin", w.BeginSigninHandler)
+	//	app.Post("/webauthn/signin/finish", w.FinishSigninHandler)
+	WebAuthn[T User] struct {
+		auth     *Auth[T]
+		config   WebAuthnConfiguration
+		store    WebAuthnStore[T]
+		rpIDHash [32]byte
+		// the challenges of the finished ceremonies, until they expire.
+		usedChallenges usedTokenStore
+	}
+
+	// WebAuthnRequest is the request body the server expects on the
+	// WebAuthn's BeginSigninHandler.
+	// The Username is optional, for discoverable credentials.
+	WebAuthnRequest struct {
+		Username string `json:"username" form:"username,omitempty"`
+	}
+
+	// WebAuthnCreationOptions is the response of the BeginRegistrationHandler.
+	// It's the JSON form of the PublicKeyCredentialCreationOptions,
+	// parse it through the PublicKeyCredential.parseCreationOptionsFromJSON browser function.
+	WebAuthnCreationOptions struct {
+		Challenge              base64URL                      `json:"challenge"`
+		RP                     webAuthnRelyingParty           `json:"rp"`
+		User                   webAuthnUserEntity             `json:"user"`
+		PubKeyCredParams       []webAuthnCredentialParameters `json:"pubKeyCredParams"`
+		Timeout                int64                          `json:"timeout"`
+		ExcludeCredentials     []webAuthnCredentialDescriptor `json:"excludeCredentials"`
+		AuthenticatorSelection webAuthnAuthenticatorSelection `json:"authenticatorSelection"`
+		Attestation            string                         `json:"attestation"`
+	}
+
+	// WebAuthnRequestOptions is the response of the BeginSigninHandler.
+	// It's the JSON form of the PublicKeyCredentialRequestOptions,
+	// parse it through the PublicKeyCredential.parseRequestOptionsFromJSON browser function.
+	WebAuthnRequestOptions struct {
+		Challenge        base64URL                      `json:"challenge"`
+		Timeout          int64                          `json:"timeout"`
+		RPID             string                         `json:"rpId"`
+		AllowCredentials []webAuthnCredentialDescriptor `json:"allowCredentials"`
+		UserVerification string                         `json:"userVerification"`
+	}
+
+	// WebAuthnCredentialResponse is the request body the server expects on the
+	// WebAuthn's FinishRegistrationHandler and FinishSigninHandler.
+	// It's the result of the browser's PublicKeyCredential.toJSON function.
+	WebAuthnCredentialResponse struct {
+		ID       string    `json:"id"`
+		RawID    base64URL `json:"rawId"`
+		Type     string    `json:"type"`
+		Response struct {
+			ClientDataJSON    base64URL `json:"clientDataJSON"`
+			AttestationObject base64URL `json:"attestationObject,omitempty"` // registration.
+			AuthenticatorData base64URL `json:"authenticatorData,omitempty"` // authentication.
+			Signature         base64URL `json:"signature,omitempty"`         // authentication.
+			UserHandle        base64URL `json:"userHandle,omitempty"`        // authentication.
+		} `json:"response"`
+	}
+
+	webAuthnRelyingParty struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	}
+
+	webAuthnUserEntity struct {
+		ID          base64URL `json:"id"`
+		Name        string    `json:"name"`
+		DisplayName string    `json:"displayName"`
+	}
+
+	webAuthnCredentialParameters struct {
+		Type string `json:"type"`
+		Alg  int64  `json:"alg"`
+	}
+
+	webAuthnCredentialDescriptor struct {
+		Type string    `json:"type"`
+		ID   base64URL `json:"id"`
+	}
+
+	webAuthnAuthenticatorSelection struct {
+		ResidentKey      string `json:"residentKey"`
+		UserVerification string `json:"userVerification"`
+	}
+
+	// webAuthnState is the state of a ceremony,
+	// it's stored to the signed cookie until the ceremony is finished.
+	webAuthnState struct {
+		Ceremony   string    `json:"ceremony"`
+		Challenge  base64URL `json:"challenge"`
+		UserHandle base64URL `json:"user_handle,omitempty"`
+		ExpiresAt  int64     `json:"expires_at"`
+	}
+
+	webAuthnClientData struct {
+		Type      string `json:"type"`
+		Challenge string `json:"challenge"`
+		Origin    string `json:"origin"`
+	}
+
+	webAuthnAuthenticatorData struct {
+		raw                 []byte
+		rpIDHash            []byte
+		flags               byte
+		signCount           uint32
+		aaguid              []byte
+		credentialID        []byte
+		credentialPublicKey []byte
+	}
+
+	// base64URL is a byte slice which is JSON-encoded as
+	// a base64 URL-safe string without padding, as the WebAuthn JSON forms expect.
+	base64URL []byte
+)
+
+var (
+	_ Provider[User] = (*WebAuthn[User])(nil)
+
+	webAuthnCredentialAlgs = []int64{COSEAlgEdDSA, COSEAlgES256, COSEAlgRS256}
+)
+
+// MarshalJSON encodes the bytes as a base64 URL-safe string.
+func (b base64URL) MarshalJSON() ([]byte, error) {
+	return json.Marshal(base64.RawURLEncoding.EncodeToString(b))
+}
+
+// UnmarshalJSON decodes a base64 URL-safe string, with or without padding.
+func (b *base64URL) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	decoded, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(s, "="))
+	if err != nil {
+		return err
+	}
+
+	*b = decoded
+	return nil
+}
+
+// NewWebAuthn returns a new WebAuthn provider of T and registers it to the "s" Auth instance.
+// The Auth's Configuration.Cookie.Hash and Block are required to sign the ceremonies' state.
+func NewWebAuthn[T User](s *Auth[T], config WebAuthnConfiguration, store WebAuthnStore[T]) (*WebAuthn[T], error) {
+	if config.RPID == "" || len(config.Origins) == 0 {
+		return nil, fmt.Errorf("webauthn: relying party id and origins are required")
+	}
+
+	if store == nil {
+		return nil, fmt.Errorf("webauthn: store is required")
+	}
+
+	if config.RPName == "" {
+		config.RPName = config.RPID
+	}
+
+	if config.Timeout <= 0 {
+		config.Timeout = 5 * time.Minute
+	}
+
+	switch config.UserVerification {
+	case "":
+		config.UserVerification = "preferred"
+	case "required", "preferred", "discouraged":
+	default:
+		return nil, fmt.Errorf("webauthn: invalid user verification requirement: %q", config.UserVerification)
+	}
+
+	w := &WebAuthn[T]{
+		auth:     s,
+		config:   config,
+		store:    store,
+		rpIDHash: sha256.Sum256([]byte(config.RPID)),
+	}
+	s.AddProvider(w)
+
+	return w, nil
+}
+
+// BeginRegistration starts the registration ceremony of a new credential for the verified user
+// of the request (see Auth.VerifyHandler), it returns ErrWebAuthnUnauthenticated if there is none.
+// It stores the challenge to the signed state cookie and returns the options
+// which should be passed to the browser's navigator.credentials.create function.
+func (w *WebAuthn[T]) BeginRegistration(ctx *context.Context) (*WebAuthnCreationOptions, error) {
+	username, userHandle, err := w.verifiedUserHandle(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	credentials, err := w.store.Credentials(ctx, userHandle)
+	if err != nil {
+		return nil, fmt.Errorf("webauthn: %w", err)
+	}
+
+	challenge, err := w.begin(ctx, webAuthnCeremonyRegistration, userHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &WebAuthnCreationOptions{
+		Challenge: challenge,
+		RP: webAuthnRelyingParty{
+			ID:   w.config.RPID,
+			Name: w.config.RPName,
+		},
+		User: webAuthnUserEntity{
+			ID:          userHandle,
+			Name:        username,
+			DisplayName: username,
+		},
+		PubKeyCredParams:   make([]webAuthnCredentialParameters, 0, len(webAuthnCredentialAlgs)),
+		Timeout:            w.config.Timeout.Milliseconds(),
+		ExcludeCredentials: credentialDescriptors(credentials),
+		AuthenticatorSelection: webAuthnAuthenticatorSelection{
+			ResidentKey:      "preferred",
+			UserVerification: w.config.UserVerification,
+		},
+		Attestation: "none",
+	}
+
+	for _, alg := range webAuthnCredentialAlgs {
+		options.PubKeyCredParams = append(options.PubKeyCredParams, webAuthnCredentialParameters{Type: "public-key", Alg: alg})
+	}
+
+	return options, nil
+}
+
+// FinishRegistration finishes the registration ceremony of the verified user of the request.
+// It verifies the authenticator's attestation response and saves the new credential.
+func (w *WebAuthn[T]) FinishRegistration(ctx *context.Context, response *WebAuthnCredentialResponse) (*WebAuthnCredential, error) {
+	_, userHandle, err := w.verifiedUserHandle(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	state, err := w.finish(ctx, webAuthnCeremonyRegistration, response, "webauthn.create")
+	if err != nil {
+		return nil, err
+	}
+
+	if subtle.ConstantTimeCompare(state.UserHandle, userHandle) != 1 {
+		return nil, fmt.Errorf("webauthn: registration was started by another user")
+	}
+
+	attestationObject, _, err := cborDecode(response.Response.AttestationObject)
+	if err != nil {
+		return nil, fmt.Errorf("webauthn: attestation object: %w", err)
+	}
+
+	attestation, ok := attestationObject.(map[interface{}]interface{})
+	if !ok {
+		return nil, fmt.Errorf("webauthn: attestation object: invalid format")
+	}
+
+	format, _ := attestation["fmt"].(string)
+	statement, _ := attestation["attStmt"].(map[interface{}]interface{})
+	rawAuthData, _ := attestation["authData"].([]byte)
+
+	authData, err := w.parseAuthenticatorData(rawAuthData)
+	if err != nil {
+		return nil, err
+	}
+
+	if authData.flags&webAuthnFlagAttestedData == 0 {
+		return nil, fmt.Errorf("webauthn: authenticator data: missing attested credential data")
+	}
+
+	alg, publicKey, err := parseCOSEKey(authData.credentialPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("webauthn: credential public key: %w", err)
+	}
+
+	if !containsInt64(webAuthnCredentialAlgs, alg) {
+		return nil, fmt.Errorf("webauthn: credential public key: unsupported algorithm: %d", alg)
+	}
+
+	clientDataHash := sha256.Sum256(response.Response.ClientDataJSON)
+	signedData := append(append([]byte(nil), authData.raw...), clientDataHash[:]...)
+
+	switch format {
+	case "none":
+		if len(statement) > 0 {
+			return nil, fmt.Errorf("webauthn: attestation: none: unexpected statement")
+		}
+	case "packed":
+		if err = verifyPackedAttestation(statement, authData, alg, publicKey, signedData); err != nil {
+			return nil, fmt.Errorf("webauthn: attestation: packed: %w", err)
+		}
+	default:
+		return nil, fmt.Errorf("webauthn: attestation: unsupported format: %q", format)
+	}
+
+	if _, err = w.store.FindCredential(ctx, authData.credentialID); err == nil {
+		return nil, fmt.Errorf("webauthn: credential is already registered")
+	} else if !errors.Is(err, ErrWebAuthnCredentialNotFound) {
+		return nil, fmt.Errorf("webauthn: %w", err)
+	}
+
+	credential := &WebAuthnCredential{
+		ID:                authData.credentialID,
+		UserHandle:        state.UserHandle,
+		PublicKey:         authData.credentialPublicKey,
+		SignCount:         authData.signCount,
+		AAGUID:            authData.aaguid,
+		AttestationFormat: format,
+		CreatedAt:         time.Now(),
+	}
+
+	if err = w.store.SaveCredential(ctx, credential); err != nil {
+		return nil, fmt.Errorf("webauthn: %w", err)
+	}
+
+	return credential, nil
+}
+
+// BeginSignin starts the authentication ceremony. The "username" is optional,
+// if empty then the user selects one of its discoverable credentials (passkeys).
+// It stores the challenge to the signed state cookie and returns the options
+// which should be passed to the browser's navigator.credentials.get function.
+func (w *WebAuthn[T]) BeginSignin(ctx *context.Context, username string) (*WebAuthnRequestOptions, error) {
+	var (
+		userHandle  []byte
+		credentials []*WebAuthnCredential
+	)
+
+	if username != "" {
+		_, handle, err := w.store.FindUser(ctx, username)
+		if err != nil {
+			return nil, fmt.Errorf("webauthn: %w", err)
+		}
+
+		if credentials, err = w.store.Credentials(ctx, handle); err != nil {
+			return nil, fmt.Errorf("webauthn: %w", err)
+		}
+
+		if len(credentials) == 0 {
+			return nil, fmt.Errorf("webauthn: %w", ErrWebAuthnCredentialNotFound)
+		}
+
+		userHandle = handle
+	}
+
+	challenge, err := w.begin(ctx, webAuthnCeremonyAuthentication, userHandle)
+	if err != nil {
+		return nil, err
+	}
+
+	options := &WebAuthnRequestOptions{
+		Challenge:        challenge,
+		Timeout:          w.config.Timeout.Milliseconds(),
+		RPID:             w.config.RPID,
+		AllowCredentials: credentialDescriptors(credentials),
+		UserVerification: w.config.UserVerification,
+	}
+
+	return options, nil
+}
+
+// FinishSignin finishes the authentication ceremony.
+// It verifies the authenticator's assertion response, updates the signature counter
+// of the credential and returns its user.
+func (w *WebAuthn[T]) FinishSignin(ctx *context.Context, response *WebAuthnCredentialResponse) (T, error) {
+	var t T
+
+	state, err := w.finish(ctx, webAuthnCeremonyAuthentication, response, "webauthn.get")
+	if err != nil {
+		return t, err
+	}
+
+	credential, err := w.store.FindCredential(ctx, response.RawID)
+	if err != nil {
+		return t, fmt.Errorf("webauthn: %w", err)
+	}
+
+	if len(state.UserHandle) > 0 && !bytes.Equal(state.UserHandle, credential.UserHandle) {
+		return t, fmt.Errorf("webauthn: credential does not belong to the user")
+	}
+
+	if len(response.Response.UserHandle) > 0 && !bytes.Equal(response.Response.UserHandle, credential.UserHandle) {
+		return t, fmt.Errorf("webauthn: user handle mismatch")
+	}
+
+	authData, err := w.parseAuthenticatorData(response.Response.AuthenticatorData)
+	if err != nil {
+		return t, err
+	}
+
+	alg, publicKey, err := parseCOSEKey(credential.PublicKey)
+	if err != nil {
+		return t, fmt.Errorf("webauthn: credential public key: %w", err)
+	}
+
+	clientDataHash := sha256.Sum256(response.Response.ClientDataJSON)
+	signedData := append(append([]byte(nil), authData.raw...), clientDataHash[:]...)
+	if err = verifyCOSESignature(alg, publicKey, signedData, response.Response.Signature); err != nil {
+		return t, fmt.Errorf("webauthn: signature: %w", err)
+	}
+
+	// A counter which does not increase indicates a cloned authenticator.
+	// Authenticators which do not support counters always send zero.
+	if authData.signCount != 0 || credential.SignCount != 0 {
+		if authData.signCount <= credential.SignCount {
+			return t, fmt.Errorf("webauthn: signature counter did not increase, the authenticator may be cloned")
+		}
+	}
+
+	credential.SignCount = authData.signCount
+	if err = w.store.SaveCredential(ctx, credential); err != nil {
+		return t, fmt.Errorf("webauthn: %w", err)
+	}
+
+	t, err = w.store.FindUserByHandle(ctx, credential.UserHandle)
+	if err != nil {
+		return t, fmt.Errorf("webauthn: %w", err)
+	}
+
+	return t, nil
+}
+
+// BeginRegistrationHandler sends the `WebAuthnCreationOptions` of a new registration ceremony
+// of the verified user. It should be registered after the Auth.VerifyHandler,
+// so users can only register credentials to their own accounts.
+// See `BeginRegistration` method for more.
+func (w *WebAuthn[T]) BeginRegistrationHandler(ctx *context.Context) {
+	options, err := w.BeginRegistration(ctx)
+	if err != nil {
+		w.auth.errorHandler.Unauthenticated(ctx, err)
+		return
+	}
+
+	ctx.JSON(options)
+}
+
+// FinishRegistrationHandler reads the `WebAuthnCredentialResponse` request body
+// and, on success, sends the 201 Created status code.
+// It should be registered after the Auth.VerifyHandler, like the BeginRegistrationHandler.
+// See `FinishRegistration` method for more.
+func (w *WebAuthn[T]) FinishRegistrationHandler(ctx *context.Context) {
+	var response WebAuthnCredentialResponse
+	if err := ctx.ReadJSON(&response); err != nil {
+		w.auth.errorHandler.InvalidArgument(ctx, err)
+		return
+	}
+
+	if _, err := w.FinishRegistration(ctx, &response); err != nil {
+		w.auth.errorHandler.Unauthenticated(ctx, err)
+		return
+	}
+
+	ctx.StatusCode(http.StatusCreated)
+}
+
+// BeginSigninHandler reads the optional `WebAuthnRequest` request body
+// and sends the `WebAuthnRequestOptions` of a new authentication ceremony.
+// See `BeginSignin` method for more.
+func (w *WebAuthn[T]) BeginSigninHandler(ctx *context.Context) {
+	var req WebAuthnRequest
+	if err := ctx.ReadJSON(&req); err != nil && !context.IsErrEmptyJSON(err) {
+		w.auth.errorHandler.InvalidArgument(ctx, err)
+		return
+	}
+
+	options, err := w.BeginSignin(ctx, req.Username)
+	if err != nil {
+		w.auth.errorHandler.Unauthenticated(ctx, err)
+		return
+	}
+
+	ctx.JSON(options)
+}
+
+// FinishSigninHandler reads the `WebAuthnCredentialResponse` request body and,
+// on success, generates and sends a pair of access and refresh token to the client
+// as JSON body of `SigninResponse` and cookie (if cookie setting was provided),
+// exactly like the Auth.SigninHandler does.
+// See `FinishSignin` method for more.
+func (w *WebAuthn[T]) FinishSigninHandler(ctx *context.Context) {
+	var response WebAuthnCredentialResponse
+	if err := ctx.ReadJSON(&response); err != nil {
+		w.auth.errorHandler.InvalidArgument(ctx, err)
+		return
+	}
+
+	t, err := w.FinishSignin(ctx, &response)
+	if err != nil {
+		w.auth.tryRemoveCookie(ctx)
+		w.auth.errorHandler.Unauthenticated(ctx, fmt.Errorf("auth: signin: %w", err))
+		return
+	}
+
+	w.auth.signinResponse(ctx, t)
+}
+
+// verifiedUserHandle returns the name and the user handle of the request's verified user.
+func (w *WebAuthn[T]) verifiedUserHandle(ctx *context.Context) (string, []byte, error) {
+	t, ok := ctx.Values().Get(userContextKey).(T)
+	if !ok {
+		return "", nil, ErrWebAuthnUnauthenticated
+	}
+
+	username, userHandle, err := w.store.UserHandle(ctx, t)
+	if err != nil {
+		return "", nil, fmt.Errorf("webauthn: %w", err)
+	}
+
+	if len(userHandle) == 0 {
+		return "", nil, fmt.Errorf("webauthn: empty user handle")
+	}
+
+	return username, userHandle, nil
+}
+
+// begin generates a new challenge and stores the state of the ceremony.
+func (w *WebAuthn[T]) begin(ctx *context.Context, ceremony string, userHandle []byte) ([]byte, error) {
+	challenge, err := randomBytes(32)
+	if err != nil {
+		return nil, fmt.Errorf("webauthn: challenge: %w", err)
+	}
+
+	state := webAuthnState{
+		Ceremony:   ceremony,
+		Challenge:  challenge,
+		UserHandle: userHandle,
+		ExpiresAt:  time.Now().Add(w.config.Timeout).Unix(),
+	}
+
+	stateData, err := json.Marshal(state)
+	if err != nil {
+		return nil, fmt.Errorf("webauthn: %w", err)
+	}
+
+	if err = w.auth.setStateCookie(ctx, stateData); err != nil {
+		return nil, fmt.Errorf("webauthn: %w", err)
+	}
+
+	return challenge, nil
+}
+
+// finish reads the state of the ceremony and verifies the client data of the response.
+func (w *WebAuthn[T]) finish(ctx *context.Context, ceremony string, response *WebAuthnCredentialResponse, clientDataType string) (*webAuthnState, error) {
+	stateData := w.auth.popStateCookie(ctx)
+	if stateData == nil {
+		return nil, fmt.Errorf("webauthn: missing or invalid state")
+	}
+
+	var state webAuthnState
+	if err := json.Unmarshal(stateData, &state); err != nil {
+		return nil, fmt.Errorf("webauthn: state: %w", err)
+	}
+
+	if state.Ceremony != ceremony {
+		return nil, fmt.Errorf("webauthn: state: unexpected ceremony: %s", state.Ceremony)
+	}
+
+	if time.Now().Unix() > state.ExpiresAt {
+		return nil, fmt.Errorf("webauthn: ceremony timed out")
+	}
+
+	// The state is held by the client, so a ceremony can be finished once,
+	// a captured state and response cannot be replayed.
+	if !w.usedChallenges.use(string(state.Challenge), time.Unix(state.ExpiresAt, 0), 1) {
+		return nil, fmt.Errorf("webauthn: ceremony already finished")
+	}
+
+	if response.Type != "public-key" {
+		return nil, fmt.Errorf("webauthn: unexpected credential type: %q", response.Type)
+	}
+
+	var clientData webAuthnClientData
+	if err := json.Unmarshal(response.Response.ClientDataJSON, &clientData); err != nil {
+		return nil, fmt.Errorf("webauthn: client data: %w", err)
+	}
+
+	if clientData.Type != clientDataType {
+		return nil, fmt.Errorf("webauthn: client data: unexpected type: %q", clientData.Type)
+	}
+
+	challenge, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(clientData.Challenge, "="))
+	if err != nil || subtle.ConstantTimeCompare(challenge, state.Challenge) != 1 {
+		return nil, fmt.Errorf("webauthn: client data: challenge mismatch")
+	}
+
+	if !containsString(w.config.Origins, clientData.Origin) {
+		return nil, fmt.Errorf("webauthn: client data: unexpected origin: %q", clientData.Origin)
+	}
+
+	return &state, nil
+}
+
+// parseAuthenticatorData parses and verifies the authenticator data.
+// See https://www.w3.org/TR/webauthn-2/#sctn-authenticator-data.
+func (w *WebAuthn[T]) parseAuthenticatorData(data []byte) (*webAuthnAuthenticatorData, error) {
+	if len(data) < 37 {
+		return nil, fmt.Errorf("webauthn: authenticator data: too short")
+	}
+
+	authData := &webAuthnAuthenticatorData{
+		raw:       data,
+		rpIDHash:  data[:32],
+		flags:     data[32],
+		signCount: binary.BigEndian.Uint32(data[33:37]),
+	}
+
+	if subtle.ConstantTimeCompare(authData.rpIDHash, w.rpIDHash[:]) != 1 {
+		return nil, fmt.Errorf("webauthn: authenticator data: relying party id mismatch")
+	}
+
+	if authData.flags&webAuthnFlagUserPresent == 0 {
+		return nil, fmt.Errorf("webauthn: authenticator data: user not present")
+	}
+
+	if w.config.UserVerification == "required" && authData.flags&webAuthnFlagUserVerified == 0 {
+		return nil, fmt.Errorf("webauthn: authenticator data: user not verified")
+	}
+
+	if authData.flags&webAuthnFlagAttestedData != 0 {
+		rest := data[37:]
+		if len(rest) < 18 {
+			return nil, fmt.Errorf("webauthn: authenticator data: attested credential data: too short")
+		}
+
+		authData.aaguid = rest[:16]
+		n := int(binary.BigEndian.Uint16(rest[16:18]))
+		rest = rest[18:]
+		if n == 0 || n > 1023 || len(rest) < n {
+			return nil, fmt.Errorf("webauthn: authenticator data: attested credential data: invalid credential id")
+		}
+		authData.credentialID = rest[:n]
+		rest = rest[n:]
+
+		_, extensions, err := cborDecode(rest)
+		if err != nil {
+			return nil, fmt.Errorf("webauthn: authenticator data: credential public key: %w", err)
+		}
+		authData.credentialPublicKey = rest[:len(rest)-len(extensions)]
+	}
+
+	return authData, nil
+}
+
+// verifyPackedAttestation verifies a packed attestation statement.
+// See https://www.w3.org/TR/webauthn-2/#sctn-packed-attestation.
+func verifyPackedAttestation(statement map[interface{}]interface{}, authData *webAuthnAuthenticatorData, alg int64, publicKey crypto.PublicKey, signedData []byte) error {
+	statementAlg, _ := statement["alg"].(int64)
+	sig, _ := statement["sig"].([]byte)
+	if len(sig) == 0 {
+		return fmt.Errorf("missing signature")
+	}
+
+	x5c, ok := statement["x5c"].([]interface{})
+	if !ok || len(x5c) == 0 { // self attestation.
+		if statementAlg != alg {
+			return fmt.Errorf("algorithm mismatch")
+		}
+
+		return verifyCOSESignature(alg, publicKey, signedData, sig)
+	}
+
+	// basic attestation, the chain is not validated against a trust anchor.
+	der, _ := x5c[0].([]byte)
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return err
+	}
+
+	if cert.IsCA {
+		return fmt.Errorf("attestation certificate is a CA")
+	}
+
+	// The id-fido-gen-ce-aaguid extension, if present, must match the authenticator's AAGUID.
+	for _, ext := range cert.Extensions {
+		if ext.Id.Equal(asn1.ObjectIdentifier{1, 3, 6, 1, 4, 1, 45724, 1, 1, 4}) {
+			var aaguid []byte
+			if _, err = asn1.Unmarshal(ext.Value, &aaguid); err != nil || !bytes.Equal(aaguid, authData.aaguid) {
+				return fmt.Errorf("attestation certificate aaguid mismatch")
+			}
+		}
+	}
+
+	return verifyCOSESignature(statementAlg, cert.PublicKey, signedData, sig)
+}
+
+// parseCOSEKey parses a COSE_Key (RFC 8152) public key.
+func parseCOSEKey(data []byte) (int64, crypto.PublicKey, error) {
+	v, _, err := cborDecode(data)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	key, ok := v.(map[interface{}]interface{})
+	if !ok {
+		return 0, nil, fmt.Errorf("invalid format")
+	}
+
+	kty, _ := key[int64(1)].(int64)
+	alg, _ := key[int64(3)].(int64)
+
+	switch kty {
+	case 1: // OKP.
+		crv, _ := key[int64(-1)].(int64)
+		x, _ := key[int64(-2)].([]byte)
+		if crv != 6 || len(x) != ed25519.PublicKeySize { // Ed25519.
+			return 0, nil, fmt.Errorf("invalid OKP key")
+		}
+
+		return alg, ed25519.PublicKey(x), nil
+	case 2: // EC2.
+		crv, _ := key[int64(-1)].(int64)
+		x, _ := key[int64(-2)].([]byte)
+		y, _ := key[int64(-3)].([]byte)
+
+		var curve elliptic.Curve
+		switch crv {
+		case 1:
+			curve = elliptic.P256()
+		case 2:
+			curve = elliptic.P384()
+		case 3:
+			curve = elliptic.P521()
+		default:
+			return 0, nil, fmt.Errorf("unsupported EC2 curve: %d", crv)
+		}
+
+		size := (curve.Params().BitSize + 7) / 8
+		if len(x) != size || len(y) != size {
+			return 0, nil, fmt.Errorf("invalid EC2 key")
+		}
+
+		publicKey := &ecdsa.PublicKey{
+			Curve: curve,
+			X:     new(big.Int).SetBytes(x),
+			Y:     new(big.Int).SetBytes(y),
+		}
+		if !curve.IsOnCurve(publicKey.X, publicKey.Y) {
+			return 0, nil, fmt.Errorf("invalid EC2 key: point is not on curve")
+		}
+
+		return alg, publicKey, nil
+	case 3: // RSA.
+		n, _ := key[int64(-1)].([]byte)
+		e, _ := key[int64(-2)].([]byte)
+		if len(n) == 0 || len(e) == 0 || len(e) > 4 {
+			return 0, nil, fmt.Errorf("invalid RSA key")
+		}
+
+		exponent := int(new(big.Int).SetBytes(e).Int64())
+		return alg, &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: exponent}, nil
+	default:
+		return 0, nil, fmt.Errorf("unsupported key type: %d", kty)
+	}
+}
+
+// verifyCOSESignature verifies the "sig" of the "data" by the "alg" COSE algorithm.
+func verifyCOSESignature(alg int64, publicKey crypto.PublicKey, data, sig []byte) error {
+	switch alg {
+	case COSEAlgES256, COSEAlgES384, COSEAlgES512:
+		key, ok := publicKey.(*ecdsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("unexpected key type: %T", publicKey)
+		}
+
+		var digest []byte
+		switch alg {
+		case COSEAlgES256:
+			h := sha256.Sum256(data)
+			digest = h[:]
+		case COSEAlgES384:
+			h := sha512.Sum384(data)
+			digest = h[:]
+		default:
+			h := sha512.Sum512(data)
+			digest = h[:]
+		}
+
+		if !ecdsa.VerifyASN1(key, digest, sig) {
+			return fmt.Errorf("invalid signature")
+		}
+
+		return nil
+	case COSEAlgEdDSA:
+		key, ok := publicKey.(ed25519.PublicKey)
+		if !ok {
+			return fmt.Errorf("unexpected key type: %T", publicKey)
+		}
+
+		if !ed25519.Verify(key, data, sig) {
+			return fmt.Errorf("invalid signature")
+		}
+
+		return nil
+	case COSEAlgRS256, COSEAlgPS256:
+		key, ok := publicKey.(*rsa.PublicKey)
+		if !ok {
+			return fmt.Errorf("unexpected key type: %T", publicKey)
+		}
+
+		digest := sha256.Sum256(data)
+		if alg == COSEAlgPS256 {
+			return rsa.VerifyPSS(key, crypto.SHA256, digest[:], sig, nil)
+		}
+
+		return rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig)
+	default:
+		return fmt.Errorf("unsupported algorithm: %d", alg)
+	}
+}
+
+func credentialDescriptors(credentials []*WebAuthnCredential) []webAuthnCredentialDescriptor {
+	descriptors := make([]webAuthnCredentialDescriptor, 0, len(credentials))
+	for _, credential := range credentials {
+		descriptors = append(descriptors, webAuthnCredentialDescriptor{Type: "public-key", ID: credential.ID})
+	}
+
+	return descriptors
+}
+
+// Signin completes the Provider interface.
+// It always returns ErrWebAuthnPasswordSignin, so other
+// registered providers are tried on password sign in.
+func (w *WebAuthn[T]) Signin(ctx stdContext.Context, username, password string) (T, error) {
+	var t T
+	return t, ErrWebAuthnPasswordSignin
+}
+
+// ValidateToken completes the Provider interface. It does nothing.
+func (w *WebAuthn[T]) ValidateToken(ctx stdContext.Context, standardClaims StandardClaims, t T) error {
+	return nil
+}
+
+// InvalidateToken completes the Provider interface. It does nothing.
+func (w *WebAuthn[T]) InvalidateToken(ctx stdContext.Context, standardClaims StandardClaims, t T) error {
+	return nil
+}
+
+// InvalidateTokens completes the Provider interface. It does nothing.
+func (w *WebAuthn[T]) InvalidateTokens(ctx stdContext.Context, t T) error {
+	return nil
+}
+
+func randomBytes(n int) ([]byte, error) {
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	return b, nil
+}
+
+func containsInt64(values []int64, v int64) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,386 @@
+//go:build go1.18
+// +build go1.18
+
+package auth_test
+
+import (
+	"bytes"
+	stdContext "context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/auth"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+type webAuthnUser struct {
+	Username string `json:"username"`
+}
+
+type webAuthnStore struct {
+	mu          sync.Mutex
+	handles     map[string]string // username -> user handle.
+	credentials map[string]*auth.WebAuthnCredential
+}
+
+func (s *webAuthnStore) FindUser(_ stdContext.Context, username string) (webAuthnUser, []byte, error) {
+	handle, ok := s.handles[username]
+	if !ok {
+		return webAuthnUser{}, nil, fmt.Errorf("user not found")
+	}
+
+	return webAuthnUser{Username: username}, []byte(handle), nil
+}
+
+func (s *webAuthnStore) UserHandle(_ stdContext.Context, u webAuthnUser) (string, []byte, error) {
+	handle, ok := s.handles[u.Username]
+	if !ok {
+		return "", nil, fmt.Errorf("user not found")
+	}
+
+	return u.Username, []byte(handle), nil
+}
+
+func (s *webAuthnStore) FindUserByHandle(_ stdContext.Context, userHandle []byte) (webAuthnUser, error) {
+	for username, handle := range s.handles {
+		if handle == string(userHandle) {
+			return webAuthnUser{Username: username}, nil
+		}
+	}
+
+	return webAuthnUser{}, fmt.Errorf("user not found")
+}
+
+// Signin, ValidateToken, InvalidateToken and InvalidateTokens complete the auth.Provider,
+// the users sign in with a password before they register their credentials.
+func (s *webAuthnStore) Signin(_ stdContext.Context, username, password string) (webAuthnUser, error) {
+	if _, ok := s.handles[username]; !ok || password != "password" {
+		return webAuthnUser{}, fmt.Errorf("invalid credentials")
+	}
+
+	return webAuthnUser{Username: username}, nil
+}
+
+func (s *webAuthnStore) ValidateToken(stdContext.Context, auth.StandardClaims, webAuthnUser) error {
+	return nil
+}
+
+func (s *webAuthnStore) InvalidateToken(stdContext.Context, auth.StandardClaims, webAuthnUser) error {
+	return nil
+}
+
+func (s *webAuthnStore) InvalidateTokens(stdContext.Context, webAuthnUser) error {
+	return nil
+}
+
+func (s *webAuthnStore) Credentials(_ stdContext.Context, userHandle []byte) ([]*auth.WebAuthnCredential, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var credentials []*auth.WebAuthnCredential
+	for _, credential := range s.credentials {
+		if bytes.Equal(credential.UserHandle, userHandle) {
+			credentials = append(credentials, credential)
+		}
+	}
+
+	return credentials, nil
+}
+
+func (s *webAuthnStore) FindCredential(_ stdContext.Context, id []byte) (*auth.WebAuthnCredential, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	credential, ok := s.credentials[string(id)]
+	if !ok {
+		return nil, auth.ErrWebAuthnCredentialNotFound
+	}
+
+	c := *credential
+	return &c, nil
+}
+
+func (s *webAuthnStore) SaveCredential(_ stdContext.Context, credential *auth.WebAuthnCredential) error {
+	s.mu.Lock()
+	s.credentials[string(credential.ID)] = credential
+	s.mu.Unlock()
+	return nil
+}
+
+// cborPairs is an ordered CBOR map of the test's encoder.
+type cborPairs []interface{}
+
+// cborEncode encodes the subset of CBOR which the tests need.
+func cborEncode(v interface{}) []byte {
+	head := func(major byte, n uint64) []byte {
+		switch {
+		case n < 24:
+			return []byte{major<<5 | byte(n)}
+		case n <= 0xff:
+			return []byte{major<<5 | 24, byte(n)}
+		default:
+			b := []byte{major<<5 | 25, 0, 0}
+			binary.BigEndian.PutUint16(b[1:], uint16(n))
+			return b
+		}
+	}
+
+	switch v := v.(type) {
+	case int:
+		if v < 0 {
+			return head(1, uint64(-1-v))
+		}
+		return head(0, uint64(v))
+	case []byte:
+		return append(head(2, uint64(len(v))), v...)
+	case string:
+		return append(head(3, uint64(len(v))), v...)
+	case cborPairs:
+		b := head(5, uint64(len(v)/2))
+		for _, item := range v {
+			b = append(b, cborEncode(item)...)
+		}
+		return b
+	default:
+		panic(fmt.Sprintf("cbor: unsupported type: %T", v))
+	}
+}
+
+// authenticator is a software ES256 WebAuthn authenticator.
+type authenticator struct {
+	origin     string
+	id         []byte
+	key        *ecdsa.PrivateKey
+	signCount  uint32
+	userHandle []byte
+	// passkeys may not implement a signature counter.
+	zeroCounter bool
+}
+
+func newAuthenticator(t *testing.T, origin string) *authenticator {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id := make([]byte, 16)
+	rand.Read(id)
+
+	return &authenticator{origin: origin, id: id, key: key}
+}
+
+func (a *authenticator) clientData(typ, challenge string) []byte {
+	b, _ := json.Marshal(map[string]string{
+		"type":      typ,
+		"challenge": challenge,
+		"origin":    a.origin,
+	})
+	return b
+}
+
+func (a *authenticator) authData(flags byte, attested bool) []byte {
+	rpIDHash := sha256.Sum256([]byte("localhost"))
+	b := append(rpIDHash[:], flags, 0, 0, 0, 0)
+	binary.BigEndian.PutUint32(b[33:], a.signCount)
+
+	if attested {
+		b = append(b, make([]byte, 16)...) // aaguid.
+		b = append(b, byte(len(a.id)>>8), byte(len(a.id)))
+		b = append(b, a.id...)
+		b = append(b, cborEncode(cborPairs{
+			1, 2, // kty: EC2.
+			3, -7, // alg: ES256.
+			-1, 1, // crv: P-256.
+			-2, a.key.X.FillBytes(make([]byte, 32)),
+			-3, a.key.Y.FillBytes(make([]byte, 32)),
+		})...)
+	}
+
+	return b
+}
+
+func (a *authenticator) sign(authData, clientData []byte) []byte {
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte(nil), authData...), clientDataHash[:]...))
+	sig, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		panic(err)
+	}
+	return sig
+}
+
+func (a *authenticator) create(options map[string]interface{}, format string) map[string]interface{} {
+	a.userHandle, _ = base64.RawURLEncoding.DecodeString(options["user"].(map[string]interface{})["id"].(string))
+
+	clientData := a.clientData("webauthn.create", options["challenge"].(string))
+	authData := a.authData(0x45, true) // UP, UV and AT.
+
+	statement := cborPairs{}
+	if format == "packed" { // self attestation.
+		statement = cborPairs{"alg", -7, "sig", a.sign(authData, clientData)}
+	}
+
+	attestationObject := cborEncode(cborPairs{"fmt", format, "attStmt", statement, "authData", authData})
+
+	return a.response(map[string]interface{}{
+		"clientDataJSON":    b64(clientData),
+		"attestationObject": b64(attestationObject),
+	})
+}
+
+func (a *authenticator) get(options map[string]interface{}) map[string]interface{} {
+	if !a.zeroCounter {
+		a.signCount++
+	}
+
+	clientData := a.clientData("webauthn.get", options["challenge"].(string))
+	authData := a.authData(0x05, false) // UP and UV.
+
+	return a.response(map[string]interface{}{
+		"clientDataJSON":    b64(clientData),
+		"authenticatorData": b64(authData),
+		"signature":         b64(a.sign(authData, clientData)),
+		"userHandle":        b64(a.userHandle),
+	})
+}
+
+func (a *authenticator) response(response map[string]interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		"id":       b64(a.id),
+		"rawId":    b64(a.id),
+		"type":     "public-key",
+		"response": response,
+	}
+}
+
+func b64(b []byte) string {
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+func TestWebAuthn(t *testing.T) {
+	store := &webAuthnStore{
+		handles:     map[string]string{"kataras": "user-1", "makis": "user-2"},
+		credentials: make(map[string]*auth.WebAuthnCredential),
+	}
+
+	s := auth.Must(auth.New[webAuthnUser](auth.MustGenerateConfiguration())).AddProvider(store)
+	w, err := auth.NewWebAuthn(s, auth.WebAuthnConfiguration{
+		RPID:             "localhost",
+		Origins:          []string{"http://localhost"},
+		UserVerification: "required",
+	}, store)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := iris.New()
+	app.Post("/register/begin", s.VerifyHandler(), w.BeginRegistrationHandler)
+	app.Post("/register/finish", s.VerifyHandler(), w.FinishRegistrationHandler)
+	app.Post("/signin/begin", w.BeginSigninHandler)
+	app.Post("/signin/finish", w.FinishSigninHandler)
+	app.Get("/me", s.VerifyHandler(), func(ctx iris.Context) {
+		ctx.JSON(auth.GetUser[webAuthnUser](ctx))
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://localhost"))
+
+	signin := func(username string) string {
+		accessToken, _, err := s.Signin(stdContext.Background(), username, "password")
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return "Bearer " + string(accessToken)
+	}
+	kataras, makis := signin("kataras"), signin("makis")
+
+	beginRegistration := func(authorization string) map[string]interface{} {
+		return e.POST("/register/begin").WithHeader("Authorization", authorization).Expect().
+			Status(httptest.StatusOK).JSON().Object().Raw()
+	}
+	finishRegistration := func(authorization string, response map[string]interface{}, expectedStatusCode int) {
+		e.POST("/register/finish").WithHeader("Authorization", authorization).WithJSON(response).Expect().
+			Status(expectedStatusCode)
+	}
+
+	// Registration requires a verified user.
+	e.POST("/register/begin").WithJSON(auth.WebAuthnRequest{Username: "kataras"}).Expect().Status(httptest.StatusUnauthorized)
+	beginSignin := func(username string) map[string]interface{} {
+		return e.POST("/signin/begin").WithJSON(auth.WebAuthnRequest{Username: username}).Expect().
+			Status(httptest.StatusOK).JSON().Object().Raw()
+	}
+
+	// Registration with the "none" attestation.
+	a := newAuthenticator(t, "http://localhost")
+	finishRegistration(kataras, a.create(beginRegistration(kataras), "none"), httptest.StatusCreated)
+
+	// Registration with the "packed" self attestation.
+	b := newAuthenticator(t, "http://localhost")
+	finishRegistration(makis, b.create(beginRegistration(makis), "packed"), httptest.StatusCreated)
+
+	// The registered credential is excluded from new registrations.
+	options := beginRegistration(kataras)
+	if excluded := options["excludeCredentials"].([]interface{}); len(excluded) != 1 {
+		t.Fatalf("expected one excluded credential but got: %v", excluded)
+	}
+
+	// Origin mismatch.
+	evil := newAuthenticator(t, "http://evil.localhost")
+	finishRegistration(kataras, evil.create(options, "none"), httptest.StatusUnauthorized)
+
+	// A registration which was started by another user.
+	c := newAuthenticator(t, "http://localhost")
+	finishRegistration(makis, c.create(beginRegistration(kataras), "none"), httptest.StatusUnauthorized)
+
+	// Sign in.
+	assertion := a.get(beginSignin("kataras"))
+	accessToken := e.POST("/signin/finish").WithJSON(assertion).Expect().Status(httptest.StatusOK).
+		JSON().Object().Value("access_token").String().NotEmpty().Raw()
+
+	e.GET("/me").WithHeader("Authorization", "Bearer "+accessToken).Expect().Status(httptest.StatusOK).
+		JSON().IsEqual(webAuthnUser{Username: "kataras"})
+
+	// The challenge is valid for a single assertion.
+	e.POST("/signin/finish").WithJSON(assertion).Expect().Status(httptest.StatusUnauthorized)
+
+	// A credential of another user.
+	e.POST("/signin/finish").WithJSON(b.get(beginSignin("kataras"))).Expect().Status(httptest.StatusUnauthorized)
+
+	// The signature counter did not increase (cloned authenticator).
+	a.signCount--
+	e.POST("/signin/finish").WithJSON(a.get(beginSignin("kataras"))).Expect().Status(httptest.StatusUnauthorized)
+
+	// Discoverable credential, without a username.
+	options = e.POST("/signin/begin").Expect().Status(httptest.StatusOK).JSON().Object().Raw()
+	e.POST("/signin/finish").WithJSON(b.get(options)).Expect().Status(httptest.StatusOK).
+		JSON().Object().Value("access_token").String().NotEmpty()
+
+	// A captured state cookie and assertion of a passkey without a signature counter cannot be replayed.
+	p := newAuthenticator(t, "http://localhost")
+	p.zeroCounter = true
+	finishRegistration(makis, p.create(beginRegistration(makis), "none"), httptest.StatusCreated)
+
+	resp := e.POST("/signin/begin").WithJSON(auth.WebAuthnRequest{Username: "makis"}).Expect().Status(httptest.StatusOK)
+	stateCookie := resp.Cookie("iris_auth_state").Value().Raw()
+	assertion = p.get(resp.JSON().Object().Raw())
+	e.POST("/signin/finish").WithJSON(assertion).Expect().Status(httptest.StatusOK)
+	e.POST("/signin/finish").WithCookie("iris_auth_state", stateCookie).WithJSON(assertion).Expect().
+		Status(httptest.StatusUnauthorized)
+
+	// Password sign in is not supported.
+	if _, err = w.Signin(stdContext.Background(), "kataras", "password"); !errors.Is(err, auth.ErrWebAuthnPasswordSignin) {
+		t.Fatalf("expected error: %v but got: %v", auth.ErrWebAuthnPasswordSignin, err)
+	}
+}
@@ -0,0 +1,141 @@
+package authz
+
+import (
+	"encoding/json"
+	"io"
+	"sort"
+
+	"github.com/kataras/iris/v12/core/router"
+)
+
+type (
+	// Audit is a snapshot of the authorization rules of an application:
+	// the roles and their permissions, the policies and the requirements of each route.
+	// See the Authorizer.Audit and Dump methods.
+	Audit struct {
+		Roles    []AuditRole   `json:"roles"`
+		Policies []AuditPolicy `json:"policies"`
+		Routes   []AuditRoute  `json:"routes"`
+		Summary  AuditSummary  `json:"summary"`
+	}
+
+	// AuditRole describes a role.
+	AuditRole struct {
+		Name        string   `json:"name"`
+		Inherits    []string `json:"inherits,omitempty"`
+		Permissions []string `json:"permissions,omitempty"`
+		// EffectivePermissions includes the permissions of the inherited roles.
+		EffectivePermissions []string `json:"effective_permissions,omitempty"`
+	}
+
+	// AuditPolicy describes a policy.
+	AuditPolicy struct {
+		Name        string `json:"name"`
+		Description string `json:"description,omitempty"`
+	}
+
+	// AuditRoute describes the requirements of a route.
+	// A route without requirements is accessible by anyone.
+	AuditRoute struct {
+		Method       string             `json:"method"`
+		Subdomain    string             `json:"subdomain,omitempty"`
+		Path         string             `json:"path"`
+		Name         string             `json:"name"`
+		Requirements []AuditRequirement `json:"requirements,omitempty"`
+	}
+
+	// AuditRequirement describes a route's requirement and its resolved kind.
+	AuditRequirement struct {
+		Name string `json:"name"`
+		Kind Kind   `json:"kind"`
+	}
+
+	// AuditSummary holds the number of the protected and unprotected routes.
+	AuditSummary struct {
+		ProtectedRoutes   int `json:"protected_routes"`
+		UnprotectedRoutes int `json:"unprotected_routes"`
+	}
+)
+
+// Audit returns the authorization rules of the Authorizer and
+// the requirements of the "routes", e.g. app.GetRoutes().
+// Note that the requirements of handlers registered manually
+// through the Handler method are not recorded to the routes.
+func (a *Authorizer) Audit(routes []*router.Route) *Audit {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	audit := &Audit{
+		Roles:    make([]AuditRole, 0, len(a.roles)),
+		Policies: make([]AuditPolicy, 0, len(a.policies)),
+		Routes:   make([]AuditRoute, 0, len(routes)),
+	}
+
+	for _, name := range sortedKeys(a.roles) {
+		r := a.roles[name]
+
+		var effective []string
+		for role := range a.effectiveRolesLocked([]string{name}) {
+			for _, permission := range a.roles[role].permissionsOrNil() {
+				if !containsString(effective, permission) {
+					effective = append(effective, permission)
+				}
+			}
+		}
+		sort.Strings(effective)
+
+		audit.Roles = append(audit.Roles, AuditRole{
+			Name:                 name,
+			Inherits:             r.inherits,
+			Permissions:          r.permissions,
+			EffectivePermissions: effective,
+		})
+	}
+
+	for _, name := range sortedKeys(a.policies) {
+		audit.Policies = append(audit.Policies, AuditPolicy{Name: name, Description: a.policies[name].description})
+	}
+
+	for _, r := range routes {
+		route := AuditRoute{
+			Method:    r.Method,
+			Subdomain: r.Subdomain,
+			Path:      r.Path,
+			Name:      r.Name,
+		}
+
+		for _, requirement := range r.Authorization {
+			kind, _ := a.kindLocked(requirement)
+			route.Requirements = append(route.Requirements, AuditRequirement{Name: requirement, Kind: kind})
+		}
+
+		if len(route.Requirements) > 0 {
+			audit.Summary.ProtectedRoutes++
+		} else {
+			audit.Summary.UnprotectedRoutes++
+		}
+
+		audit.Routes = append(audit.Routes, route)
+	}
+
+	return audit
+}
+
+// Dump writes the Audit of the "routes" to "w" as indented JSON.
+//
+// Usage:
+//
+//	a.Dump(os.Stdout, app.GetRoutes())
+func (a *Authorizer) Dump(w io.Writer, routes []*router.Route) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(a.Audit(routes))
+}
+
+func (r *roleEntry) permissionsOrNil() []string {
+	if r == nil {
+		return nil
+	}
+
+	return r.permissions
+}
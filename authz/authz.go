@@ -0,0 +1,418 @@
+// Package authz provides role, permission and attribute based authorization
+// for the users set by the authentication middlewares (e.g. auth, jwt and basicauth)
+// through the Context.SetUser method.
+//
+// An Authorizer evaluates the requirements declared through the Party.Authorize method,
+// the Party.AuthorizeHandler and the MVC controllers' authorization tags (see mvc.Authorize).
+// The kind of a requirement is explicit: "role:" prefixed requirements
+// are roles of the user, "perm:" prefixed ones are permissions granted to the user's roles
+// and any other requirement is the name of a registered policy.
+// Unknown requirements, e.g. a policy which was never registered, are always denied.
+//
+// Usage:
+//
+//	a := authz.New().
+//	  Grant("editor", "articles:read", "articles:write").
+//	  Inherit("admin", "editor").Grant("admin", "users:*").
+//	  Policy("business-hours", "weekdays from 9 to 5", authz.PolicyFunc(isBusinessHours))
+//	app.SetAuthorizer(a)
+//
+//	api := app.Party("/api", verifyMiddleware)
+//	api.Authorize("perm:articles:read").Get("/articles", listArticles)
+//	api.Party("/users").Authorize("perm:users:delete", "business-hours").Delete("/{id}", deleteUser)
+//	api.Party("/admin").Authorize("role:admin").Get("/", adminDashboard)
+package authz
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/core/router"
+)
+
+// Policy is an attribute based authorization rule.
+// It reports whether the "user" is allowed to perform the current request,
+// it can make decisions based on the user's fields (e.g. roles, claims, custom fields)
+// and the request's attributes (e.g. path parameters, time, client IP).
+// Register a named policy through the Authorizer.Policy method.
+type Policy interface {
+	Evaluate(ctx *context.Context, user context.User) bool
+}
+
+// PolicyFunc completes the Policy interface.
+type PolicyFunc func(ctx *context.Context, user context.User) bool
+
+// Evaluate calls itself.
+func (fn PolicyFunc) Evaluate(ctx *context.Context, user context.User) bool {
+	return fn(ctx, user)
+}
+
+// Roles returns a Policy which allows the users with any of the given roles.
+func Roles(roles ...string) Policy {
+	return PolicyFunc(func(ctx *context.Context, user context.User) bool {
+		userRoles, _ := user.GetRoles()
+		for _, role := range roles {
+			if containsString(userRoles, role) {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// All returns a Policy which allows the users that satisfy all the given policies.
+func All(policies ...Policy) Policy {
+	return PolicyFunc(func(ctx *context.Context, user context.User) bool {
+		for _, p := range policies {
+			if !p.Evaluate(ctx, user) {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+// Any returns a Policy which allows the users that satisfy any of the given policies.
+func Any(policies ...Policy) Policy {
+	return PolicyFunc(func(ctx *context.Context, user context.User) bool {
+		for _, p := range policies {
+			if p.Evaluate(ctx, user) {
+				return true
+			}
+		}
+
+		return false
+	})
+}
+
+// Not returns a Policy which allows the users that do not satisfy the given policy.
+func Not(policy Policy) Policy {
+	return PolicyFunc(func(ctx *context.Context, user context.User) bool {
+		return !policy.Evaluate(ctx, user)
+	})
+}
+
+// Denial describes a denied authorization, it's passed to the Authorizer's DenyHandler.
+type Denial struct {
+	// StatusCode is 401 when there is no user and 403 otherwise.
+	StatusCode int
+	// Requirement is the first requirement that the user did not satisfy.
+	Requirement string
+	// Kind of the requirement, see the Kind type.
+	Kind Kind
+}
+
+// Kind is the kind of a resolved requirement.
+type Kind string
+
+// The requirement kinds.
+const (
+	KindPolicy     Kind = "policy"
+	KindPermission Kind = "permission"
+	KindRole       Kind = "role"
+	// KindUnknown is the kind of a requirement without a prefix
+	// which is not a registered policy, it's always denied.
+	KindUnknown Kind = "unknown"
+)
+
+// The requirement prefixes.
+const (
+	// RolePrefix is the prefix of the role requirements, e.g. "role:admin".
+	RolePrefix = "role:"
+	// PermissionPrefix is the prefix of the permission requirements, e.g. "perm:users:delete".
+	PermissionPrefix = "perm:"
+)
+
+// DefaultDenyHandler sends an RFC 7807 problem with the denial's status code,
+// the missing requirement is not exposed to the client.
+func DefaultDenyHandler(ctx *context.Context, denial Denial) {
+	ctx.Problem(context.NewProblem().Status(denial.StatusCode).Detail(denyDetail(denial.StatusCode)))
+	ctx.StopExecution()
+}
+
+func denyDetail(statusCode int) string {
+	if statusCode == http.StatusUnauthorized {
+		return "authentication is required to access this resource"
+	}
+
+	return "you are not allowed to access this resource"
+}
+
+type (
+	policyEntry struct {
+		description string
+		policy      Policy
+	}
+
+	roleEntry struct {
+		permissions []string
+		inherits    []string
+	}
+)
+
+// Authorizer holds the role based access control (roles, their permissions and inheritance)
+// and the attribute based policies of an application. It completes the router.Authorizer interface,
+// register it through the Party.SetAuthorizer method.
+//
+// It is safe for concurrent use.
+type Authorizer struct {
+	// DenyHandler handles the denied requests.
+	// Defaults to the DefaultDenyHandler.
+	DenyHandler func(ctx *context.Context, denial Denial)
+
+	mu       sync.RWMutex
+	policies map[string]*policyEntry
+	roles    map[string]*roleEntry
+}
+
+var _ router.Authorizer = (*Authorizer)(nil)
+
+// New returns a new empty Authorizer.
+func New() *Authorizer {
+	return &Authorizer{
+		DenyHandler: DefaultDenyHandler,
+		policies:    make(map[string]*policyEntry),
+		roles:       make(map[string]*roleEntry),
+	}
+}
+
+// Policy registers a named attribute based policy, the "description" is used on audits.
+// Requirements without a "role:" or "perm:" prefix are resolved to the policy of that name.
+func (a *Authorizer) Policy(name, description string, policy Policy) *Authorizer {
+	a.mu.Lock()
+	a.policies[name] = &policyEntry{description: description, policy: policy}
+	a.mu.Unlock()
+	return a
+}
+
+// Grant grants one or more permissions to a role.
+// A permission ending with "*" grants all the permissions with that prefix,
+// e.g. "users:*" grants "users:read" and "users:delete" and "*" grants everything.
+func (a *Authorizer) Grant(role string, permissions ...string) *Authorizer {
+	a.mu.Lock()
+	r := a.roleLocked(role)
+	for _, permission := range permissions {
+		if !containsString(r.permissions, permission) {
+			r.permissions = append(r.permissions, permission)
+		}
+	}
+	a.mu.Unlock()
+	return a
+}
+
+// Inherit makes a role inherit the permissions of one or more parent roles,
+// e.g. Inherit("admin", "editor") grants all the editor's permissions to the admins.
+// A user with the "admin" role satisfies the "editor" role requirements too.
+func (a *Authorizer) Inherit(role string, parents ...string) *Authorizer {
+	a.mu.Lock()
+	r := a.roleLocked(role)
+	for _, parent := range parents {
+		if parent != role && !containsString(r.inherits, parent) {
+			r.inherits = append(r.inherits, parent)
+		}
+	}
+	a.mu.Unlock()
+	return a
+}
+
+func (a *Authorizer) roleLocked(name string) *roleEntry {
+	r, ok := a.roles[name]
+	if !ok {
+		r = new(roleEntry)
+		a.roles[name] = r
+	}
+
+	return r
+}
+
+// effectiveRolesLocked returns the "roles" and the roles they inherit from.
+func (a *Authorizer) effectiveRolesLocked(roles []string) map[string]struct{} {
+	effective := make(map[string]struct{}, len(roles))
+
+	var visit func(role string)
+	visit = func(role string) {
+		if _, ok := effective[role]; ok {
+			return
+		}
+		effective[role] = struct{}{}
+
+		if r, ok := a.roles[role]; ok {
+			for _, parent := range r.inherits {
+				visit(parent)
+			}
+		}
+	}
+
+	for _, role := range roles {
+		visit(role)
+	}
+
+	return effective
+}
+
+// kindLocked resolves the kind of a requirement by its prefix
+// and returns the requirement without its prefix.
+func (a *Authorizer) kindLocked(requirement string) (Kind, string) {
+	if name := strings.TrimPrefix(requirement, RolePrefix); len(name) < len(requirement) {
+		return KindRole, name
+	}
+
+	if name := strings.TrimPrefix(requirement, PermissionPrefix); len(name) < len(requirement) {
+		return KindPermission, name
+	}
+
+	if _, ok := a.policies[requirement]; ok {
+		return KindPolicy, requirement
+	}
+
+	return KindUnknown, requirement
+}
+
+func permissionMatches(granted, permission string) bool {
+	if strings.HasSuffix(granted, "*") {
+		return strings.HasPrefix(permission, granted[:len(granted)-1])
+	}
+
+	return granted == permission
+}
+
+// Can reports whether the "user" has been granted the "permission"
+// through its roles (see context.User.GetRoles) and their parent roles.
+func (a *Authorizer) Can(user context.User, permission string) bool {
+	if user == nil {
+		return false
+	}
+
+	roles, _ := user.GetRoles()
+
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+
+	return a.canLocked(roles, permission)
+}
+
+func (a *Authorizer) canLocked(roles []string, permission string) bool {
+	for role := range a.effectiveRolesLocked(roles) {
+		if r, ok := a.roles[role]; ok {
+			for _, granted := range r.permissions {
+				if permissionMatches(granted, permission) {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+// Allowed reports whether the current user (see Context.User) satisfies all the "requirements".
+// It returns the first unsatisfied requirement and its kind when not allowed.
+// Unknown requirements are never satisfied.
+func (a *Authorizer) Allowed(ctx *context.Context, requirements ...string) (bool, string, Kind) {
+	user := ctx.User()
+	if user == nil {
+		if len(requirements) == 0 {
+			return true, "", ""
+		}
+
+		return false, requirements[0], a.KindOf(requirements[0])
+	}
+
+	roles, _ := user.GetRoles()
+
+	for _, requirement := range requirements {
+		a.mu.RLock()
+		kind, name := a.kindLocked(requirement)
+		var (
+			policy  Policy
+			allowed bool
+		)
+		switch kind {
+		case KindPolicy:
+			policy = a.policies[name].policy
+		case KindPermission:
+			allowed = a.canLocked(roles, name)
+		case KindRole:
+			_, allowed = a.effectiveRolesLocked(roles)[name]
+		}
+		a.mu.RUnlock()
+
+		if policy != nil { // evaluate it without holding the lock.
+			allowed = policy.Evaluate(ctx, user)
+		}
+
+		if !allowed {
+			return false, requirement, kind
+		}
+	}
+
+	return true, "", ""
+}
+
+// KindOf returns the kind that the "requirement" is resolved to.
+func (a *Authorizer) KindOf(requirement string) Kind {
+	a.mu.RLock()
+	kind, _ := a.kindLocked(requirement)
+	a.mu.RUnlock()
+
+	return kind
+}
+
+// Authorize completes the router.Authorizer interface.
+// It calls the Context's Next method if the current user satisfies
+// all the "requirements" or the DenyHandler otherwise.
+func (a *Authorizer) Authorize(ctx *context.Context, requirements []string) {
+	allowed, requirement, kind := a.Allowed(ctx, requirements...)
+	if allowed {
+		ctx.Next()
+		return
+	}
+
+	statusCode := http.StatusForbidden
+	if ctx.User() == nil {
+		statusCode = http.StatusUnauthorized
+	}
+
+	ctx.Application().Logger().Debugf("authz: %s %s: denied: %s %q", ctx.Method(), ctx.Path(), kind, requirement)
+
+	denyHandler := a.DenyHandler
+	if denyHandler == nil {
+		denyHandler = DefaultDenyHandler
+	}
+
+	denyHandler(ctx, Denial{StatusCode: statusCode, Requirement: requirement, Kind: kind})
+}
+
+// Handler returns a handler which allows only the users that satisfy all the "requirements".
+// Prefer the Party.Authorize method, its requirements are recorded for audits.
+func (a *Authorizer) Handler(requirements ...string) context.Handler {
+	requirements = append([]string(nil), requirements...)
+	return func(ctx *context.Context) {
+		a.Authorize(ctx, requirements)
+	}
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sortedKeys returns the keys of a map sorted.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,142 @@
+package authz_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/authz"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestAuthorizer(t *testing.T) {
+	a := authz.New().
+		Grant("editor", "articles:read", "articles:write").
+		Grant("admin", "users:*").Inherit("admin", "editor").
+		Policy("owner", "the user owns the resource", authz.PolicyFunc(func(ctx iris.Context, user iris.User) bool {
+			id, _ := user.GetID()
+			return id == ctx.Params().Get("id")
+		}))
+
+	app := iris.New()
+	app.SetAuthorizer(a)
+	app.UseRouter(func(ctx iris.Context) {
+		if username := ctx.GetHeader("X-User"); username != "" {
+			ctx.SetUser(&context.SimpleUser{ID: username, Roles: []string{ctx.GetHeader("X-Role")}})
+		}
+		ctx.Next()
+	})
+
+	handler := func(ctx iris.Context) {
+		ctx.WriteString(ctx.Path())
+	}
+
+	app.Get("/public", handler)
+	articles := app.Party("/articles").Authorize("perm:articles:read")
+	articles.Get("/", handler)
+	articles.Post("/", a.Handler("perm:articles:write"), handler)
+
+	users := app.Party("/users").Authorize("perm:users:read")
+	users.Get("/{id}", handler)
+	users.Party("/{id}").Authorize("owner").Put("/", handler)
+	app.Party("/admin").Authorize("role:admin").Get("/", handler)
+	// A permission that is granted to no role and an unregistered policy.
+	app.Party("/reports").Authorize("perm:reports:read").Get("/", handler)
+	app.Party("/billing").Authorize("billing-owner").Get("/", handler)
+
+	e := httptest.New(t, app)
+
+	type request struct {
+		method string
+		path   string
+		role   string
+		status int
+	}
+
+	for i, tt := range []request{
+		{"GET", "/public", "", httptest.StatusOK},
+		{"GET", "/articles", "", httptest.StatusUnauthorized},
+		{"GET", "/articles", "guest", httptest.StatusForbidden},
+		{"GET", "/articles", "editor", httptest.StatusOK},
+		{"POST", "/articles", "editor", httptest.StatusOK},
+		{"GET", "/users/kataras", "editor", httptest.StatusForbidden},
+		// Inherited permissions, wildcards and roles.
+		{"GET", "/articles", "admin", httptest.StatusOK},
+		{"GET", "/users/makis", "admin", httptest.StatusOK},
+		{"GET", "/admin", "admin", httptest.StatusOK},
+		{"GET", "/admin", "editor", httptest.StatusForbidden},
+		// Policy.
+		{"PUT", "/users/kataras", "admin", httptest.StatusOK},
+		{"PUT", "/users/makis", "admin", httptest.StatusForbidden},
+		// Unknown requirements are denied, even if they match a role's name.
+		{"GET", "/reports", "admin", httptest.StatusForbidden},
+		{"GET", "/billing", "billing-owner", httptest.StatusForbidden},
+	} {
+		req := e.Request(tt.method, tt.path)
+		if tt.role != "" {
+			req.WithHeader("X-User", "kataras").WithHeader("X-Role", tt.role)
+		}
+
+		resp := req.Expect().Status(tt.status)
+		if tt.status == httptest.StatusOK {
+			continue
+		}
+
+		resp.ContentType(context.ContentJSONProblemHeaderValue)
+		var problem struct {
+			Status int `json:"status"`
+		}
+		if err := json.Unmarshal([]byte(resp.Body().Raw()), &problem); err != nil {
+			t.Fatal(err)
+		}
+		if problem.Status != tt.status {
+			t.Fatalf("[%d] expected problem status: %d but got: %d", i, tt.status, problem.Status)
+		}
+	}
+
+	if !a.Can(&context.SimpleUser{Roles: []string{"admin"}}, "articles:write") {
+		t.Fatal("expected admin to inherit the editor's permissions")
+	}
+
+	if a.Can(&context.SimpleUser{Roles: []string{"editor"}}, "users:delete") {
+		t.Fatal("expected editor to not have the users permissions")
+	}
+
+	if expected, got := authz.KindUnknown, a.KindOf("billing-owner"); expected != got {
+		t.Fatalf("expected kind: %q but got: %q", expected, got)
+	}
+
+	var buf bytes.Buffer
+	if err := a.Dump(&buf, app.GetRoutes()); err != nil {
+		t.Fatal(err)
+	}
+
+	var audit authz.Audit
+	if err := json.Unmarshal(buf.Bytes(), &audit); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := 2, len(audit.Roles); expected != got {
+		t.Fatalf("expected %d roles but got %d", expected, got)
+	}
+
+	admin := audit.Roles[0]
+	if admin.Name != "admin" || len(admin.EffectivePermissions) != 3 {
+		t.Fatalf("unexpected admin role: %#+v", admin)
+	}
+
+	if expected, got := (authz.AuditSummary{ProtectedRoutes: 7, UnprotectedRoutes: 1}), audit.Summary; expected != got {
+		t.Fatalf("expected summary: %#+v but got: %#+v", expected, got)
+	}
+
+	for _, r := range audit.Routes {
+		if r.Method == iris.MethodPut {
+			expected := []authz.AuditRequirement{{Name: "perm:users:read", Kind: authz.KindPermission}, {Name: "owner", Kind: authz.KindPolicy}}
+			if len(r.Requirements) != len(expected) || r.Requirements[0] != expected[0] || r.Requirements[1] != expected[1] {
+				t.Fatalf("expected requirements: %#+v but got: %#+v", expected, r.Requirements)
+			}
+		}
+	}
+}
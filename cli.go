@@ -85,29 +85,35 @@ func injectLiveReload(r Party) (bool, error) {
     };
 }());</script>`, conf.LiveReload.Port))
 
-	bodyCloseTag := []byte("</body>")
-
 	r.UseRouter(func(ctx Context) {
-		rec := ctx.Recorder() // Record everything and write all in once at the Context release.
-		ctx.Next()            // call the next, so this is a 'done' handler.
-		if strings.HasPrefix(ctx.GetContentType(), "text/html") {
-			// delete(rec.Header(), context.ContentLengthHeaderKey)
-
-			body := rec.Body()
-
-			if idx := bytes.LastIndex(body, bodyCloseTag); idx > 0 {
-				// add the script right before last </body>.
-				body = append(body[:idx], bytes.Replace(body[idx:], bodyCloseTag, append(scriptReloadJS, bodyCloseTag...), 1)...)
-				rec.SetBody(body)
-			} else {
-				// Just append it.
-				rec.Write(scriptReloadJS) // nolint:errcheck
-			}
-
-			if _, has := rec.Header()[context.ContentLengthHeaderKey]; has {
-				rec.Header().Set(context.ContentLengthHeaderKey, fmt.Sprintf("%d", len(rec.Body())))
-			}
-		}
+		injectHTMLScript(ctx, scriptReloadJS)
 	})
 	return true, nil
 }
+
+var bodyCloseTag = []byte("</body>")
+
+// injectHTMLScript records the response of the next handlers
+// and, if it is an HTML one, it adds the "script" right before its last </body>.
+func injectHTMLScript(ctx Context, script []byte) {
+	rec := ctx.Recorder() // Record everything and write all in once at the Context release.
+	ctx.Next()            // call the next, so this is a 'done' handler.
+	if strings.HasPrefix(ctx.GetContentType(), "text/html") {
+		// delete(rec.Header(), context.ContentLengthHeaderKey)
+
+		body := rec.Body()
+
+		if idx := bytes.LastIndex(body, bodyCloseTag); idx > 0 {
+			// add the script right before last </body>.
+			body = append(body[:idx], bytes.Replace(body[idx:], bodyCloseTag, append(script, bodyCloseTag...), 1)...)
+			rec.SetBody(body)
+		} else {
+			// Just append it.
+			rec.Write(script) // nolint:errcheck
+		}
+
+		if _, has := rec.Header()[context.ContentLengthHeaderKey]; has {
+			rec.Header().Set(context.ContentLengthHeaderKey, fmt.Sprintf("%d", len(rec.Body())))
+		}
+	}
+}
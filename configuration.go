@@ -192,6 +192,14 @@ func WithLogLevel(level string) Configurator {
 	}
 }
 
+// WithRouteConflicts sets the `Configuration.RouteConflicts` field,
+// e.g. "warn" to log or "error" to fail on ambiguous, unreachable and shadowed routes.
+func WithRouteConflicts(mode string) Configurator {
+	return func(app *Application) {
+		app.config.RouteConflicts = mode
+	}
+}
+
 // WithSocketSharding sets the `Configuration.SocketSharding` field to true.
 func WithSocketSharding(app *Application) {
 	// Note(@kataras): It could be a host Configurator but it's an application setting in order
@@ -687,6 +695,15 @@ type Configuration struct {
 	// NonBlocking, if set to true then the server will start listening for incoming connections
 	// without blocking the main goroutine. Use the Application.Wait method to block and wait for the server to be up and running.
 	NonBlocking bool `ini:"non_blocking" json:"nonBlocking" yaml:"NonBlocking" toml:"NonBlocking"`
+	// RouteConflicts enables the routes analyzer on Application.Build.
+	// It reports ambiguous, unreachable and shadowed routes, including
+	// subdomains and HandleDir wildcards, with their source file:line.
+	// See the `router.AnalyzeRoutes` function for more.
+	//
+	// Defaults to empty (disabled). Possible values are:
+	// * "warn" logs the conflicts as warnings
+	// * "error" fails the application's startup
+	RouteConflicts string `ini:"route_conflicts" json:"routeConflicts,omitempty" yaml:"RouteConflicts" toml:"RouteConflicts"`
 
 	// Tunneling can be optionally set to enable ngrok http(s) tunneling for this Iris app instance.
 	// See the `WithTunneling` Configurator too.
@@ -1016,6 +1033,11 @@ func (c *Configuration) GetNonBlocking() bool {
 	return c.NonBlocking
 }
 
+// GetRouteConflicts returns the RouteConflicts field.
+func (c *Configuration) GetRouteConflicts() string {
+	return c.RouteConflicts
+}
+
 // GetTimeoutMessage returns the TimeoutMessage field.
 func (c *Configuration) GetTimeoutMessage() string {
 	return c.TimeoutMessage
@@ -1227,6 +1249,10 @@ func WithConfiguration(c Configuration) Configurator {
 			main.NonBlocking = v
 		}
 
+		if v := c.RouteConflicts; v != "" {
+			main.RouteConflicts = v
+		}
+
 		if len(c.Tunneling.Tunnels) > 0 {
 			main.Tunneling = c.Tunneling
 		}
@@ -104,6 +104,18 @@ type Application interface {
 	String() string
 }
 
+// ApplicationViewBlocker is an addition of an Application,
+// if an Application implements that interface
+// then a single block of a template can be rendered through its registered view engine,
+// see `Context.ViewBlock`. The Iris Application implements it.
+type ApplicationViewBlocker interface {
+	// ViewBlock executes and write the result of a single block of a template file to the writer.
+	//
+	// Use context.ViewBlock to render template blocks to the client instead.
+	// Returns an error on failure, otherwise nil.
+	ViewBlock(writer io.Writer, filename string, block string, bindingData interface{}) error
+}
+
 // Notes(@kataras):
 // Alternative places...
 // 1. in apps/store, but it would require an empty `import _ "....apps/store"
@@ -26,6 +26,8 @@ type ConfigurationReadOnly interface {
 	GetTimeoutMessage() string
 	// GetNonBlocking returns the NonBlocking field.
 	GetNonBlocking() bool
+	// GetRouteConflicts returns the RouteConflicts field.
+	GetRouteConflicts() string
 	// GetDisablePathCorrection returns the DisablePathCorrection field
 	GetDisablePathCorrection() bool
 	// GetDisablePathCorrectionRedirection returns the DisablePathCorrectionRedirection field.
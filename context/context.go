@@ -7,6 +7,7 @@ import (
 	"encoding/xml"
 	"errors"
 	"fmt"
+	stdhtml "html"
 	"io"
 	"mime"
 	"mime/multipart"
@@ -820,7 +821,7 @@ func (ctx *Context) StopWithError(statusCode int, err error) {
 		return
 	}
 
-	ctx.StopWithText(statusCode, err.Error())
+	ctx.StopWithText(statusCode, "%s", err.Error())
 }
 
 // StopWithPlainError like `StopWithError` but it does NOT
@@ -3395,6 +3396,18 @@ const (
 	AcceptEncodingHeaderKey = "Accept-Encoding"
 	// VaryHeaderKey is the header key of "Vary".
 	VaryHeaderKey = "Vary"
+
+	// HTMXRequestHeaderKey is the request header key of "HX-Request", sent by htmx.
+	HTMXRequestHeaderKey = "HX-Request"
+	// HTMXTargetHeaderKey is the request header key of "HX-Target",
+	// the id of the target element, sent by htmx.
+	HTMXTargetHeaderKey = "HX-Target"
+	// HTMXHistoryRestoreRequestHeaderKey is the request header key of "HX-History-Restore-Request", sent by htmx.
+	HTMXHistoryRestoreRequestHeaderKey = "HX-History-Restore-Request"
+	// HTMXTriggerHeaderKey is the response header key of "HX-Trigger".
+	HTMXTriggerHeaderKey = "HX-Trigger"
+	// HTMXRedirectHeaderKey is the response header key of "HX-Redirect".
+	HTMXRedirectHeaderKey = "HX-Redirect"
 )
 
 var unixEpochTime = time.Unix(0, 0)
@@ -3946,40 +3959,206 @@ func (ctx *Context) View(filename string, optionalViewModel ...interface{}) erro
 		}
 	}
 
-	if err != nil {
-		if ctx.IsDebug() {
-			// send the error back to the client, when debug mode.
-			ctx.StopWithError(http.StatusInternalServerError, err)
-		} else {
-			ctx.SetErrPrivate(err)
-			ctx.StopWithStatus(http.StatusInternalServerError)
-		}
+	ctx.stopWithViewError(err)
+	return err
+}
+
+func (ctx *Context) stopWithViewError(err error) {
+	if err == nil {
+		return
 	}
 
-	return err
+	if ctx.IsDebug() {
+		// send the error back to the client, when debug mode.
+		ctx.StopWithError(http.StatusInternalServerError, err)
+	} else {
+		ctx.SetErrPrivate(err)
+		ctx.StopWithStatus(http.StatusInternalServerError)
+	}
 }
 
 func (ctx *Context) renderView(filename string, optionalViewModel ...interface{}) error {
 	cfg := ctx.app.ConfigurationReadOnly()
 	layout := ctx.values.GetString(cfg.GetViewLayoutContextKey())
 
-	var bindingData interface{}
+	return ctx.renderViewWithLayout(filename, layout, optionalViewModel...)
+}
+
+func (ctx *Context) renderViewWithLayout(filename, layout string, optionalViewModel ...interface{}) error {
+	bindingData := ctx.viewBindingData(optionalViewModel...)
+
+	if engine := ctx.viewEngine(); engine != nil {
+		return engine.ExecuteWriter(ctx, filename, layout, bindingData)
+	}
+
+	return ctx.app.View(ctx, filename, layout, bindingData)
+}
+
+// renderViewBlock renders the "block" of the "filename" template,
+// or the whole template without a layout if "block" is empty.
+func (ctx *Context) renderViewBlock(filename, block string, optionalViewModel ...interface{}) error {
+	if block == "" {
+		return ctx.renderViewWithLayout(filename, NoLayout, optionalViewModel...)
+	}
+
+	bindingData := ctx.viewBindingData(optionalViewModel...)
+
+	if engine := ctx.viewEngine(); engine != nil {
+		if blockExecutor, ok := engine.(ViewEngineBlockExecutor); ok {
+			return blockExecutor.ExecuteBlock(ctx, filename, block, bindingData)
+		}
+
+		return fmt.Errorf("%s: %w", engine.Name(), ErrViewBlockNotSupported)
+	}
+
+	if app, ok := ctx.app.(ApplicationViewBlocker); ok {
+		return app.ViewBlock(ctx, filename, block, bindingData)
+	}
+
+	return ErrViewBlockNotSupported
+}
+
+func (ctx *Context) viewBindingData(optionalViewModel ...interface{}) interface{} {
 	if len(optionalViewModel) > 0 /* Don't do it: can break a lot of servers: && optionalViewModel[0] != nil */ {
 		// a nil can override the existing data or model sent by `ViewData`.
-		bindingData = optionalViewModel[0]
-	} else {
-		bindingData = ctx.values.Get(cfg.GetViewDataContextKey())
+		return optionalViewModel[0]
 	}
 
-	if key := cfg.GetViewEngineContextKey(); key != "" {
+	return ctx.values.Get(ctx.app.ConfigurationReadOnly().GetViewDataContextKey())
+}
+
+// viewEngine returns the view engine registered through the `ViewEngine` method, if any.
+func (ctx *Context) viewEngine() ViewEngine {
+	if key := ctx.app.ConfigurationReadOnly().GetViewEngineContextKey(); key != "" {
 		if engineV := ctx.values.Get(key); engineV != nil {
 			if engine, ok := engineV.(ViewEngine); ok {
-				return engine.ExecuteWriter(ctx, filename, layout, bindingData)
+				return engine
 			}
 		}
 	}
 
-	return ctx.app.View(ctx, filename, layout, bindingData)
+	return nil
+}
+
+// ViewBlock renders only the "block" of the "filename" template, without a layout,
+// e.g. a {{ define "block" }} of the HTML and Blocks view engines
+// or a {% block block %} of the Django one.
+// If "block" is empty then the whole template is rendered without a layout.
+// The rest of the arguments are the same as the `View` method's ones.
+//
+// Useful for partial page updates, see `ViewHTMX` and `ViewOOB` too.
+func (ctx *Context) ViewBlock(filename, block string, optionalViewModel ...interface{}) error {
+	ctx.ContentType(ContentHTMLHeaderValue)
+
+	err := ctx.renderViewBlock(filename, block, optionalViewModel...)
+	ctx.stopWithViewError(err)
+	return err
+}
+
+// IsHTMX reports whether the request was made by htmx (https://htmx.org),
+// through its "HX-Request" header. History restore requests are not reported
+// as they should be served with the whole page.
+func (ctx *Context) IsHTMX() bool {
+	return ctx.GetHeader(HTMXRequestHeaderKey) == "true" &&
+		ctx.GetHeader(HTMXHistoryRestoreRequestHeaderKey) != "true"
+}
+
+// HTMXTarget returns the id of the target element of an htmx request,
+// through its "HX-Target" header, if any.
+func (ctx *Context) HTMXTarget() string {
+	return ctx.GetHeader(HTMXTargetHeaderKey)
+}
+
+// ViewHTMX renders the whole "filename" page for regular requests and
+// only a part of it for htmx requests (see `IsHTMX`), so the same templates
+// serve both the first page load and the partial updates:
+// the "<file>-<target>" block of the template, named after the file without its extension
+// and the request's target element (see `HTMXTarget`), e.g. "index-results",
+// or the whole template without a layout if the template has no such block.
+// Other templates and blocks are never rendered by the client's target element.
+// The rest of the arguments are the same as the `View` method's ones.
+//
+// Example Code:
+//
+//	// index.html: {{ define "index-results" }}...{{ end }}
+//	// <div id="results" hx-get="/search" hx-trigger="keyup from:#q">{{ template "index-results" . }}</div>
+//	app.Get("/search", func(ctx iris.Context) {
+//		ctx.ViewHTMX("index.html", search(ctx.URLParam("q")))
+//	})
+func (ctx *Context) ViewHTMX(filename string, optionalViewModel ...interface{}) error {
+	// the response depends on the request header, so caches should keep both versions.
+	ctx.ResponseWriter().Header().Add(VaryHeaderKey, HTMXRequestHeaderKey)
+
+	if !ctx.IsHTMX() {
+		return ctx.View(filename, optionalViewModel...)
+	}
+
+	ctx.ContentType(ContentHTMLHeaderValue)
+
+	var err error
+	if target := ctx.HTMXTarget(); target != "" {
+		// The target is sent by the client, so only the blocks of the file's namespace are looked up.
+		block := strings.TrimSuffix(filename, path.Ext(filename)) + "-" + target
+		err = ctx.renderViewBlock(filename, block, optionalViewModel...)
+
+		var errNotExist ErrViewNotExist
+		if (errors.As(err, &errNotExist) && errNotExist.Name == block) || errors.Is(err, ErrViewBlockNotSupported) {
+			err = ctx.renderViewBlock(filename, "", optionalViewModel...)
+		}
+	} else {
+		err = ctx.renderViewBlock(filename, "", optionalViewModel...)
+	}
+
+	ctx.stopWithViewError(err)
+	return err
+}
+
+// ViewOOB renders the "block" of the "filename" template (see `ViewBlock`)
+// as an htmx out-of-band swap, which replaces the contents
+// of the "target" element (a CSS selector, e.g. "#cart-count") of the page.
+// It should be called after the main response, e.g. after `ViewHTMX`,
+// to update more elements of the page with a single response.
+func (ctx *Context) ViewOOB(target, filename, block string, optionalViewModel ...interface{}) error {
+	ctx.ContentType(ContentHTMLHeaderValue)
+
+	_, err := ctx.WriteString(`<div hx-swap-oob="innerHTML:` + stdhtml.EscapeString(target) + `">`)
+	if err == nil {
+		if err = ctx.renderViewBlock(filename, block, optionalViewModel...); err == nil {
+			_, err = ctx.WriteString("</div>")
+		}
+	}
+
+	ctx.stopWithViewError(err)
+	return err
+}
+
+// HTMXTrigger adds a client-side event, with an optional "detail" value,
+// to the "HX-Trigger" response header which htmx triggers after the response is received.
+// It can be called more than once, it should be called before the response body is written.
+func (ctx *Context) HTMXTrigger(event string, detail interface{}) {
+	events, _ := ctx.values.Get(htmxTriggerContextKey).(map[string]interface{})
+	if events == nil {
+		events = make(map[string]interface{})
+		ctx.values.Set(htmxTriggerContextKey, events)
+	}
+	events[event] = detail
+
+	b, err := json.Marshal(events)
+	if err != nil {
+		ctx.app.Logger().Errorf("htmx trigger: %v", err)
+		return
+	}
+
+	ctx.Header(HTMXTriggerHeaderKey, string(b))
+}
+
+const htmxTriggerContextKey = "iris.htmx.trigger"
+
+// HTMXRedirect makes htmx to perform a client-side redirect (a full page load) to the "url",
+// through the "HX-Redirect" response header. Regular redirects are followed by the
+// browser's XMLHttpRequest and their response is swapped into the target element instead.
+func (ctx *Context) HTMXRedirect(url string) {
+	ctx.Header(HTMXRedirectHeaderKey, url)
 }
 
 const (
@@ -1,6 +1,10 @@
 package context
 
-import "golang.org/x/text/language"
+import (
+	"time"
+
+	"golang.org/x/text/language"
+)
 
 // I18nReadOnly is the interface which contains the read-only i18n features.
 // Read the "i18n" package fo details.
@@ -27,3 +31,29 @@ type Locale interface {
 	// GetMessage should return translated text based on the given "key".
 	GetMessage(key string, args ...interface{}) string
 }
+
+// LocaleFormatter is an optional interface which a `Locale` may implement
+// to format values based on its language, e.g. the i18n package's Locale.
+// Note that the i18n package's dates, times and relative times
+// follow the English ones on languages other than English, Greek, German, French and Spanish.
+// Usage:
+//
+//	if f, ok := ctx.GetLocale().(context.LocaleFormatter); ok {
+//		s := f.FormatCurrency(9.99, "EUR")
+//	}
+type LocaleFormatter interface {
+	// FormatNumber should return the "n" number formatted
+	// with the decimal and grouping separators of the locale.
+	FormatNumber(n interface{}) string
+	// FormatPercent should return the "n" number formatted as a percentage, e.g. 0.25 as "25%".
+	FormatPercent(n interface{}) string
+	// FormatCurrency should return the "amount" formatted in the "currencyCode" (ISO 4217) currency,
+	// an empty code means the currency of the locale's region.
+	FormatCurrency(amount interface{}, currencyCode string) string
+	// FormatDate should return the date of "t" in the "short", "medium", "long" or "full" style.
+	FormatDate(t time.Time, style string) string
+	// FormatTime should return the time of "t" in the "short", "medium", "long" or "full" style.
+	FormatTime(t time.Time, style string) string
+	// FormatRelativeTime should return the time between now and "t", e.g. "3 days ago".
+	FormatRelativeTime(t time.Time) string
+}
@@ -5,9 +5,11 @@ import (
 	"reflect"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/kataras/iris/v12/core/memstore"
+	"github.com/kataras/iris/v12/macro"
 )
 
 // RequestParams is a key string - value string storage which
@@ -244,7 +246,8 @@ var ParamResolvers = map[reflect.Type]func(paramIndex int) interface{}{
 			if ctx.Params().Len() <= paramIndex {
 				return ""
 			}
-			return ctx.Params().GetEntryAt(paramIndex).ValueRaw.(string)
+			// Values of non-string parameter types, e.g. {id:uuid}, are converted to their string form.
+			return ctx.Params().GetEntryAt(paramIndex).String()
 		}
 	},
 	reflect.TypeOf(int(1)): func(paramIndex int) interface{} {
@@ -367,6 +370,70 @@ var ParamResolvers = map[reflect.Type]func(paramIndex int) interface{}{
 	},
 }
 
+// ParamResolverFor returns the path parameter resolver for the given Go type.
+// If the type is not part of the ParamResolvers map then it searches
+// the registered macros (see macro.Defaults and Application.Macros().Register)
+// for a parameter type which declares "typ" as its value type,
+// and if found, it returns a resolver for it. It is safe for concurrent use.
+// That way custom macros can be injected to hero handlers and MVC methods
+// without a manual ParamResolvers entry.
+//
+// Returns nil and false if "typ" does not match any parameter type.
+func ParamResolverFor(typ reflect.Type) (func(paramIndex int) interface{}, bool) {
+	if r, ok := ParamResolvers[typ]; ok && r != nil {
+		return r, true
+	}
+
+	if typ == nil {
+		return nil, false
+	}
+
+	// The ParamResolvers map is not modified at serve-time,
+	// the macro resolvers are cached separately instead.
+	if r, ok := macroParamResolvers.Load(typ); ok {
+		return r.(func(paramIndex int) interface{}), true
+	}
+
+	for _, m := range *macro.Defaults {
+		if m == nil || m.GoType() != typ {
+			continue
+		}
+
+		r, _ := macroParamResolvers.LoadOrStore(typ, makeParamResolver(typ))
+		return r.(func(paramIndex int) interface{}), true
+	}
+
+	return nil, false
+}
+
+// macroParamResolvers holds the resolvers of the ParamResolverFor function
+// for the value types of the registered macros.
+var macroParamResolvers sync.Map // map[reflect.Type]func(paramIndex int) interface{}
+
+var contextTyp = reflect.TypeOf((*Context)(nil))
+
+// makeParamResolver returns a ParamResolvers compatible value for any type.
+func makeParamResolver(typ reflect.Type) func(paramIndex int) interface{} {
+	fnTyp := reflect.FuncOf([]reflect.Type{contextTyp}, []reflect.Type{typ}, false)
+	zero := []reflect.Value{reflect.Zero(typ)}
+
+	return func(paramIndex int) interface{} {
+		return reflect.MakeFunc(fnTyp, func(in []reflect.Value) []reflect.Value {
+			ctx := in[0].Interface().(*Context)
+			if ctx.Params().Len() <= paramIndex {
+				return zero
+			}
+
+			v := reflect.ValueOf(ctx.Params().GetEntryAt(paramIndex).ValueRaw)
+			if !v.IsValid() || v.Type() != typ {
+				return zero
+			}
+
+			return []reflect.Value{v}
+		}).Interface()
+	}
+}
+
 // ParamResolverByTypeAndIndex will return a function that can be used to bind path parameter's exact value by its Go std type
 // and the parameter's index based on the registered path.
 // Usage: nameResolver := ParamResolverByKindAndKey(reflect.TypeOf(""), 0)
@@ -398,8 +465,8 @@ func ParamResolverByTypeAndIndex(typ reflect.Type, paramIndex int) (reflect.Valu
 	//
 	*/
 
-	r, ok := ParamResolvers[typ]
-	if !ok || r == nil {
+	r, ok := ParamResolverFor(typ)
+	if !ok {
 		return reflect.Value{}, false
 	}
 
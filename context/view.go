@@ -1,6 +1,7 @@
 package context
 
 import (
+	"errors"
 	"fmt"
 	"io"
 )
@@ -36,6 +37,23 @@ type ViewEngine interface {
 	Ext() string
 }
 
+// NoLayout disables the configuration's layout for a specific execution.
+const NoLayout = "iris.nolayout"
+
+// ErrViewBlockNotSupported is returned when a block of a template is rendered
+// but the view engine does not implement the `ViewEngineBlockExecutor` interface.
+var ErrViewBlockNotSupported = errors.New("view engine does not support template blocks")
+
+// ViewEngineBlockExecutor is an addition of a view engine,
+// if a view engine implements that interface
+// then a single block of a template can be rendered,
+// e.g. for partial page updates, see `Context.ViewBlock` and `Context.ViewHTMX`.
+type ViewEngineBlockExecutor interface {
+	// ExecuteBlock should execute only the "block" of the "filename" template, without a layout.
+	// It should return an ErrViewNotExist if the template or the block does not exist.
+	ExecuteBlock(w io.Writer, filename string, block string, bindingData interface{}) error
+}
+
 // ViewEngineFuncer is an addition of a view engine,
 // if a view engine implements that interface
 // then iris can add some closed-relative iris functions
@@ -283,6 +283,13 @@ type APIBuilder struct {
 	// It defaults to the internal, simple, "defaultPartyMatcher".
 	// It applies when "routerFilters" are used.
 	partyMatcher PartyMatcherFunc
+
+	// authorizer field is shared across all Parties,
+	// see `SetAuthorizer`.
+	authorizer *authorizerRef
+	// the per-party (and its children) authorization requirements,
+	// filled with the `Authorize` method and recorded to the routes.
+	authorization []string
 }
 
 var (
@@ -303,6 +310,7 @@ func NewAPIBuilder(logger *golog.Logger) *APIBuilder {
 		apiBuilderDI:  &APIContainer{Container: hero.New().WithLogger(logger)},
 		routerFilters: make(map[Party]*Filter),
 		partyMatcher:  defaultPartyMatcher,
+		authorizer:    new(authorizerRef),
 	}
 }
 
@@ -809,6 +817,9 @@ func (api *APIBuilder) createRoutes(errorCode int, methods []string, relativePat
 		// route.Done(api.doneGlobalHandlers...)
 
 		route.NoLog = api.routesNoLog
+		if len(api.authorization) > 0 {
+			route.Authorization = append([]string(nil), api.authorization...)
+		}
 		routes[i] = route
 	}
 
@@ -909,6 +920,8 @@ func (api *APIBuilder) Party(relativePath string, handlers ...context.Handler) P
 		allowMethods:          allowMethods,
 		handlerExecutionRules: api.handlerExecutionRules,
 		routeRegisterRule:     api.routeRegisterRule,
+		authorizer:            api.authorizer,
+		authorization:         api.authorization[0:len(api.authorization):len(api.authorization)],
 		apiBuilderDI: &APIContainer{
 			// attach a new Container with correct dynamic path parameter start index for input arguments
 			// based on the fullpath.
@@ -1435,6 +1448,7 @@ func (api *APIBuilder) Reset() Party {
 	api.ResetRouterFilters()
 
 	api.doneHandlers = api.doneHandlers[0:0]
+	api.authorization = nil
 	api.handlerExecutionRules = ExecutionRules{}
 	api.routeRegisterRule = RouteOverride
 
@@ -0,0 +1,157 @@
+package router
+
+import (
+	"net/http"
+	"sync"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// Authorizer evaluates the authorization requirements
+// declared through the Party.Authorize and Party.AuthorizeHandler methods.
+// Set a custom one through the Party.SetAuthorizer method,
+// see the authz package for a role, permission and attribute based implementation.
+//
+// The default one is the RolesAuthorizer.
+type Authorizer interface {
+	// Authorize should call the Context's Next method if the current user
+	// satisfies all the "requirements" or stop the execution with a
+	// 401 (unauthenticated) or 403 (forbidden) response otherwise.
+	Authorize(ctx *context.Context, requirements []string)
+}
+
+// AuthorizerFunc completes the Authorizer interface.
+type AuthorizerFunc func(ctx *context.Context, requirements []string)
+
+// Authorize calls itself.
+func (fn AuthorizerFunc) Authorize(ctx *context.Context, requirements []string) {
+	fn(ctx, requirements)
+}
+
+// RolesAuthorizer is the default Authorizer.
+// Each requirement is a role that the current user (see Context.User) must have.
+// It sends 401 when there is no user and 403 when a role is missing.
+var RolesAuthorizer Authorizer = AuthorizerFunc(func(ctx *context.Context, requirements []string) {
+	user := ctx.User()
+	if user == nil {
+		ctx.StopWithStatus(http.StatusUnauthorized)
+		return
+	}
+
+	roles, _ := user.GetRoles()
+	for _, requirement := range requirements {
+		if !containsString(roles, requirement) {
+			ctx.StopWithStatus(http.StatusForbidden)
+			return
+		}
+	}
+
+	ctx.Next()
+})
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
+
+// authorizerRef holds the Authorizer of the application,
+// it's shared across Parties, so it can be set before or after the routes registration.
+type authorizerRef struct {
+	mu         sync.RWMutex
+	authorizer Authorizer
+}
+
+func (r *authorizerRef) get() Authorizer {
+	r.mu.RLock()
+	authorizer := r.authorizer
+	r.mu.RUnlock()
+
+	if authorizer == nil {
+		return RolesAuthorizer
+	}
+
+	return authorizer
+}
+
+func (r *authorizerRef) set(authorizer Authorizer) {
+	r.mu.Lock()
+	r.authorizer = authorizer
+	r.mu.Unlock()
+}
+
+// SetAuthorizer sets the Authorizer which evaluates the requirements
+// of the Authorize and AuthorizeHandler methods of all Parties.
+// It can be called before or after the routes registration.
+//
+// Returns this Party.
+func (api *APIBuilder) SetAuthorizer(authorizer Authorizer) Party {
+	api.authorizer.set(authorizer)
+	return api
+}
+
+// Authorize registers a middleware to this Party's routes and child routes
+// which allows only the users that satisfy all the given "requirements",
+// e.g. roles or, if the authz package is used, permissions and policies.
+// The requirements are recorded to the Route.Authorization field for audits.
+// Successive calls, including the parent Parties' ones, should all be satisfied.
+//
+// Unlike Use, the call order does not matter for the routes: the routes which
+// are already registered to this Party and its children are protected as well.
+// The middleware runs after the Party's middlewares which were registered before the call,
+// so the authentication one, which sets the Context.User (e.g. auth.VerifyHandler or basicauth),
+// should be registered first.
+//
+// Usage:
+//
+//	admin := app.Party("/admin", verifyMiddleware).Authorize("admin")
+//
+// Returns this Party.
+func (api *APIBuilder) Authorize(requirements ...string) Party {
+	if len(requirements) == 0 {
+		return api
+	}
+
+	handler := api.AuthorizeHandler(requirements...)
+
+	// Protect the already registered routes of this Party and its children.
+	for _, r := range api.routes.routes {
+		if api.registered(r) {
+			r.Authorization = append(r.Authorization[0:len(r.Authorization):len(r.Authorization)], requirements...)
+			r.Use(handler)
+		}
+	}
+
+	api.authorization = append(api.authorization[0:len(api.authorization):len(api.authorization)], requirements...)
+	api.Use(handler)
+	return api
+}
+
+// registered reports whether the route was registered to this Party or to one of its children.
+func (api *APIBuilder) registered(r *Route) bool {
+	p, _ := r.Party.(*APIBuilder)
+	for ; p != nil; p = p.parent {
+		if p == api {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AuthorizeHandler returns a handler which allows only the users
+// that satisfy all the given "requirements", see the Authorize method.
+// It can be used as a route's middleware, note that the requirements
+// are not recorded to the Route.Authorization field automatically.
+func (api *APIBuilder) AuthorizeHandler(requirements ...string) context.Handler {
+	requirements = append([]string(nil), requirements...)
+	ref := api.authorizer
+
+	return func(ctx *context.Context) {
+		ref.get().Authorize(ctx, requirements)
+	}
+}
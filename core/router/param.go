@@ -0,0 +1,102 @@
+package router
+
+import (
+	"encoding"
+	"reflect"
+	"strconv"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// GetParam returns the value of the "name" path parameter as a type of T.
+//
+// The value is the one produced by the route's macro at match time,
+// e.g. uint64 for {id:uint64}, uuid.UUID for {id:uuid}, time.Time for {d:date} or the declared
+// Go type of a custom macro (see macro.NewMacro's "valueType" input argument),
+// so no further parsing is required inside the handler.
+// If the stored value is a plain string (e.g. {name:string})
+// it is converted to T when T implements the encoding.TextUnmarshaler
+// or T is a basic numeric or boolean type.
+//
+// It returns the zero value of T if the parameter is missing or it cannot be
+// represented as T. See LookupParam to check for existence too.
+//
+// Note that the Param name is already reserved for the function
+// which builds a path parameter's name, hence the Get prefix.
+//
+// Example Code:
+//
+//	app.Get("/users/{id:uint64}", func(ctx iris.Context) {
+//		id := router.GetParam[uint64](ctx, "id")
+//	})
+func GetParam[T any](ctx *context.Context, name string) T {
+	v, _ := LookupParam[T](ctx, name)
+	return v
+}
+
+// LookupParam same as GetParam but it reports whether the "name" path parameter
+// exists and its value can be represented as T.
+func LookupParam[T any](ctx *context.Context, name string) (T, bool) {
+	var zero T
+
+	entry, found := ctx.Params().Store.GetEntry(name)
+	if !found {
+		return zero, false
+	}
+
+	switch v := entry.ValueRaw.(type) {
+	case T:
+		return v, true
+	case string:
+		return convertParam[T](v)
+	default:
+		return zero, false
+	}
+}
+
+// convertParam converts a raw string path parameter value to T.
+func convertParam[T any](s string) (T, bool) {
+	var v T
+
+	if u, ok := any(&v).(encoding.TextUnmarshaler); ok {
+		if err := u.UnmarshalText([]byte(s)); err != nil {
+			return v, false
+		}
+
+		return v, true
+	}
+
+	rv := reflect.ValueOf(&v).Elem()
+	switch rv.Kind() {
+	case reflect.String:
+		rv.SetString(s)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(s, 10, rv.Type().Bits())
+		if err != nil {
+			return v, false
+		}
+		rv.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(s, 10, rv.Type().Bits())
+		if err != nil {
+			return v, false
+		}
+		rv.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(s, rv.Type().Bits())
+		if err != nil {
+			return v, false
+		}
+		rv.SetFloat(n)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return v, false
+		}
+		rv.SetBool(b)
+	default:
+		return v, false
+	}
+
+	return v, true
+}
@@ -0,0 +1,77 @@
+// black-box testing
+
+package router_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/core/router"
+	"github.com/kataras/iris/v12/httptest"
+
+	"github.com/google/uuid"
+)
+
+func TestGetParam(t *testing.T) {
+	app := iris.New()
+	app.Get("/users/{id:uint64}", func(ctx iris.Context) {
+		ctx.Writef("%d", router.GetParam[uint64](ctx, "id"))
+	})
+	app.Get("/files/{id:uuid}", func(ctx iris.Context) {
+		id, ok := router.LookupParam[uuid.UUID](ctx, "id")
+		ctx.Writef("%s:%v", id, ok)
+	})
+	app.Get("/posts/{d:date}", func(ctx iris.Context) {
+		ctx.WriteString(router.GetParam[time.Time](ctx, "d").Format(time.DateOnly))
+	})
+	app.Get("/names/{name}", func(ctx iris.Context) {
+		_, ok := router.LookupParam[int](ctx, "name")
+		_, missing := router.LookupParam[string](ctx, "other")
+		ctx.Writef("%v:%v", ok, missing)
+	})
+
+	app.ConfigureContainer(func(api *iris.APIContainer) {
+		api.Get("/hero/{id:uuid}", func(id uuid.UUID) string {
+			return id.String()
+		})
+		api.Get("/hero/string/{id:uuid}", func(id string) string {
+			return id
+		})
+	})
+
+	id := uuid.New()
+
+	e := httptest.New(t, app)
+	e.GET("/hero/" + id.String()).Expect().Status(httptest.StatusOK).Body().IsEqual(id.String())
+	e.GET("/hero/string/" + id.String()).Expect().Status(httptest.StatusOK).Body().IsEqual(id.String())
+	e.GET("/users/42").Expect().Status(httptest.StatusOK).Body().IsEqual("42")
+	e.GET("/files/" + id.String()).Expect().Status(httptest.StatusOK).Body().IsEqual(fmt.Sprintf("%s:true", id))
+	e.GET("/posts/2024/01/02").Expect().Status(httptest.StatusOK).Body().IsEqual("2024-01-02")
+	e.GET("/names/kataras").Expect().Status(httptest.StatusOK).Body().IsEqual("false:false")
+}
+
+type slug string
+
+func TestGetParamCustomMacro(t *testing.T) {
+	app := iris.New()
+	app.Macros().Register("slug", "", slug(""), false, false, func(paramValue string) (interface{}, bool) {
+		return slug(strings.ToLower(paramValue)), true
+	})
+	defer app.Macros().Unregister("slug")
+
+	app.Get("/generic/{s:slug}", func(ctx iris.Context) {
+		ctx.WriteString(string(router.GetParam[slug](ctx, "s")))
+	})
+	app.ConfigureContainer(func(api *iris.APIContainer) {
+		api.Get("/hero/{s:slug}", func(s slug) string {
+			return string(s)
+		})
+	})
+
+	e := httptest.New(t, app)
+	e.GET("/generic/Hello").Expect().Status(httptest.StatusOK).Body().IsEqual("hello")
+	e.GET("/hero/World").Expect().Status(httptest.StatusOK).Body().IsEqual("world")
+}
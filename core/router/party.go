@@ -258,6 +258,23 @@ type Party interface {
 	// Returns this Party.
 	ResetRouterFilters() Party
 
+	// SetAuthorizer sets the Authorizer which evaluates the requirements
+	// of the Authorize and AuthorizeHandler methods of all Parties.
+	// Defaults to the RolesAuthorizer.
+	//
+	// Returns this Party.
+	SetAuthorizer(authorizer Authorizer) Party
+	// Authorize registers a middleware to this Party's routes and child routes
+	// which allows only the users that satisfy all the given "requirements",
+	// e.g. roles or, if the authz package is used, permissions and policies.
+	// The requirements are recorded to the Route.Authorization field for audits.
+	//
+	// Returns this Party.
+	Authorize(requirements ...string) Party
+	// AuthorizeHandler returns a handler which allows only the users
+	// that satisfy all the given "requirements", see Authorize.
+	AuthorizeHandler(requirements ...string) context.Handler
+
 	// AllowMethods will re-register the future routes that will be registered
 	// via `Handle`, `Get`, `Post`, ... to the given "methods" on that Party and its children "Parties",
 	// duplicates are not registered.
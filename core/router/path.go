@@ -1,7 +1,10 @@
 package router
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"net/url"
 	"path"
 	"strconv"
 	"strings"
@@ -367,13 +370,116 @@ func toStringSlice(args []interface{}) (argsString []string) {
 				argsString[i] = arr[0]
 				argsString = append(argsString, arr[1:]...)
 			}
+		} else if v != nil {
+			argsString[i] = fmt.Sprintf("%v", v)
 		}
 	}
 	return
 }
 
-// Remove the URL for now, it complicates things for the whole framework without a specific benefits,
-// developers can just concat the subdomain, (host can be auto-retrieve by browser using the Path).
+var (
+	// ErrRouteNotFound is returned by RoutePathReverser.BuildURL
+	// when a route with the given name does not exist.
+	ErrRouteNotFound = errors.New("route not found")
+	// ErrInvalidURLParam is returned by RoutePathReverser.BuildURL when
+	// a parameter value is missing or does not pass its macro's validation.
+	ErrInvalidURLParam = errors.New("invalid path parameter")
+)
+
+// BuildURL returns the absolute URL of a route based on its name,
+// the base "scheme" (e.g. "https") and "host" (e.g. "mydomain.com").
+//
+// The route's subdomain is prepended to the host, if the route is registered
+// on a wildcard subdomain then the first of the "paramValues" is the subdomain part.
+// The rest "paramValues" are the values of the route's dynamic path parameters,
+// each one is validated against its parameter type and functions (macro),
+// e.g. a "x" value for a {id:uint64 min(1)} returns an ErrInvalidURLParam error.
+// A last url.Values or map[string]string value is encoded as the URL's query string.
+//
+// Example Code:
+//
+//	app.Get("/users/{id:uint64}", handler).Name = "user"
+//	rv := router.NewRoutePathReverser(app)
+//	url, err := rv.BuildURL("https", "mydomain.com", "user", 42, url.Values{"tab": {"posts"}})
+//	// https://mydomain.com/users/42?tab=posts
+func (ps *RoutePathReverser) BuildURL(scheme, host, routeName string, paramValues ...interface{}) (string, error) {
+	r := ps.provider.GetRoute(routeName)
+	if r == nil {
+		return "", fmt.Errorf("%w: %s", ErrRouteNotFound, routeName)
+	}
+
+	if host == "" {
+		return "", fmt.Errorf("url: %s: empty host", routeName)
+	}
+
+	if scheme == "" {
+		scheme = netutil.ResolveSchemeFromVHost(host)
+	}
+
+	var query url.Values
+	if n := len(paramValues); n > 0 {
+		switch q := paramValues[n-1].(type) {
+		case url.Values:
+			query = q
+			paramValues = paramValues[:n-1]
+		case map[string]string:
+			query = make(url.Values, len(q))
+			for k, v := range q {
+				query.Set(k, v)
+			}
+			paramValues = paramValues[:n-1]
+		}
+	}
+
+	args := toStringSlice(paramValues)
+
+	switch r.Subdomain {
+	case "":
+	case SubdomainWildcardIndicator:
+		if len(args) == 0 || args[0] == "" {
+			return "", fmt.Errorf("%w: %s: missing wildcard subdomain value", ErrInvalidURLParam, routeName)
+		}
+
+		host = args[0] + "." + host
+		args = args[1:]
+	default:
+		host = r.Subdomain + host // subdomain contains the dot, e.g. "admin."
+	}
+
+	params := r.tmpl.Params
+	if expected, got := len(params), len(args); expected != got {
+		return "", fmt.Errorf("%w: %s: expected %d values but got %d", ErrInvalidURLParam, routeName, expected, got)
+	}
+
+	for i := range params {
+		p := params[i]
+		value := args[i]
+		if value == "" {
+			return "", fmt.Errorf("%w: %s: empty value for %s", ErrInvalidURLParam, routeName, p.Src)
+		}
+
+		if _, passed := p.Eval(value); !passed {
+			return "", fmt.Errorf("%w: %s: %q does not match %s", ErrInvalidURLParam, routeName, value, p.Src)
+		}
+
+		if ast.IsTrailing(p.Type) {
+			segments := strings.Split(strings.TrimPrefix(value, "/"), "/")
+			for j, segment := range segments {
+				segments[j] = url.PathEscape(segment)
+			}
+			args[i] = strings.Join(segments, "/")
+		} else {
+			args[i] = url.PathEscape(value)
+		}
+	}
+
+	u := scheme + "://" + host + r.ResolvePath(args...)
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+
+	return u, nil
+}
 
 // URL same as Path but returns the full uri, i.e https://mysubdomain.mydomain.com/hello/iris
 func (ps *RoutePathReverser) URL(routeName string, paramValues ...interface{}) (url string) {
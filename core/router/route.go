@@ -69,6 +69,10 @@ type Route struct {
 	// It's used ONLY for logging.
 	overlappedLink *Route
 
+	// Authorization holds the requirements of the Party.Authorize calls
+	// (and of the MVC controllers' authorization tags) which protect this route.
+	Authorization []string `json:"authorization,omitempty"`
+
 	// Sitemap properties: https://www.sitemaps.org/protocol.html
 	NoSitemap  bool      // when this route should be hidden from sitemap.
 	LastMod    time.Time `json:"lastMod,omitempty"`
@@ -0,0 +1,216 @@
+package router
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/kataras/iris/v12/macro"
+)
+
+// RouteConflictKind describes the kind of a RouteConflict.
+type RouteConflictKind uint8
+
+const (
+	// RouteAmbiguous is reported when two routes match the same request paths
+	// and the one which is executed is decided at serve-time,
+	// by the parameter types and the order of registration,
+	// e.g. /users/{id:uint64} and /users/{name:string}.
+	RouteAmbiguous RouteConflictKind = iota + 1
+	// RouteUnreachable is reported when a route can never be executed
+	// because a later one matches exactly the same request paths,
+	// e.g. /users/{id} and /users/{name}.
+	RouteUnreachable
+	// RouteShadowed is reported when a wildcard route (e.g. a HandleDir one)
+	// or a wildcard subdomain's route loses part of its request paths to another route,
+	// e.g. /files/{p:path} and /files/{name}.
+	RouteShadowed
+)
+
+// String returns the text representation of the conflict kind.
+func (k RouteConflictKind) String() string {
+	switch k {
+	case RouteAmbiguous:
+		return "ambiguous"
+	case RouteUnreachable:
+		return "unreachable"
+	case RouteShadowed:
+		return "shadowed"
+	default:
+		return "unknown"
+	}
+}
+
+// RouteConflict holds the information of a route which
+// conflicts with another one. See AnalyzeRoutes.
+type RouteConflict struct {
+	Kind RouteConflictKind `json:"kind"`
+	// Route is the affected route.
+	Route *Route `json:"-"`
+	// Other is the route which Route conflicts with.
+	Other *Route `json:"-"`
+}
+
+// String returns a human-readable representation of the conflict,
+// including the source locations of both routes.
+func (c RouteConflict) String() string {
+	var reason string
+	switch c.Kind {
+	case RouteAmbiguous:
+		reason = "matches the same request paths, resolved by parameter types and registration order"
+	case RouteUnreachable:
+		reason = "is never executed, overridden by"
+	case RouteShadowed:
+		reason = "does not match request paths handled by"
+	}
+
+	return fmt.Sprintf("%s: %s route %s %s %s (%s)",
+		routeSourceLine(c.Route), c.Kind, c.Route.String(), reason, c.Other.String(), routeSourceLine(c.Other))
+}
+
+func routeSourceLine(r *Route) string {
+	return fmt.Sprintf("%s:%d", r.SourceFileName, r.SourceLineNumber)
+}
+
+// AnalyzeRoutes reports ambiguous, unreachable and shadowed routes,
+// the ones that the router resolves silently by priority.
+// Error handlers and offline routes are not checked.
+//
+// The "routes" should be given in order of registration, e.g. APIBuilder.GetRoutes().
+// Application.Build calls it when the Configuration.RouteConflicts field is set.
+func AnalyzeRoutes(routes []*Route) (conflicts []RouteConflict) {
+	type analyzedRoute struct {
+		route    *Route
+		segments []routeSegment
+	}
+
+	candidates := make([]analyzedRoute, 0, len(routes))
+	for _, r := range routes {
+		if r.StatusCode > 0 || !r.IsOnline() {
+			continue
+		}
+
+		candidates = append(candidates, analyzedRoute{route: r, segments: splitRouteSegments(r)})
+	}
+
+	for i, a := range candidates {
+		for _, b := range candidates[i+1:] {
+			if a.route.Method != b.route.Method {
+				continue
+			}
+
+			sameSubdomain := a.route.Subdomain == b.route.Subdomain
+			wildcardSubdomain := !sameSubdomain && a.route.Subdomain != "" && b.route.Subdomain != "" &&
+				(a.route.Subdomain == SubdomainWildcardIndicator || b.route.Subdomain == SubdomainWildcardIndicator)
+			if !sameSubdomain && !wildcardSubdomain {
+				continue
+			}
+
+			kind, shadowedFirst := compareRouteSegments(a.segments, b.segments)
+			if kind == 0 {
+				continue
+			}
+
+			if wildcardSubdomain {
+				// the specific subdomain takes the request paths from the wildcard one.
+				kind = RouteShadowed
+				shadowedFirst = a.route.Subdomain == SubdomainWildcardIndicator
+			}
+
+			conflict := RouteConflict{Kind: kind, Route: b.route, Other: a.route}
+			if kind == RouteUnreachable || (kind == RouteShadowed && shadowedFirst) {
+				// the later registered one wins.
+				conflict.Route, conflict.Other = a.route, b.route
+			}
+
+			conflicts = append(conflicts, conflict)
+		}
+	}
+
+	return
+}
+
+type routeSegment struct {
+	static   string
+	param    *macro.TemplateParam
+	wildcard bool
+}
+
+func (s routeSegment) dynamic() bool {
+	return s.param != nil
+}
+
+// signature returns the parameter type and its functions, without the parameter's name.
+func (s routeSegment) signature() string {
+	src := strings.TrimSuffix(strings.TrimPrefix(s.param.Src, "{"), "}")
+	funcs := ""
+	if idx := strings.IndexByte(src, ' '); idx != -1 {
+		funcs = strings.TrimSpace(src[idx+1:])
+	}
+
+	return s.param.Type.Indent() + " " + funcs
+}
+
+func splitRouteSegments(r *Route) []routeSegment {
+	var (
+		segments   []routeSegment
+		paramIndex int
+	)
+
+	for _, part := range strings.Split(strings.Trim(r.Path, "/"), "/") {
+		if part == "" {
+			continue
+		}
+
+		s := routeSegment{static: part}
+		if strings.HasPrefix(part, ParamStart) || strings.HasPrefix(part, WildcardParamStart) {
+			if paramIndex < len(r.tmpl.Params) {
+				s.param = &r.tmpl.Params[paramIndex]
+				paramIndex++
+			}
+			s.wildcard = strings.HasPrefix(part, WildcardParamStart)
+		}
+
+		segments = append(segments, s)
+	}
+
+	return segments
+}
+
+// compareRouteSegments returns the conflict kind of two routes paths (if any)
+// and, for RouteShadowed, reports whether the first one is the shadowed one.
+func compareRouteSegments(a, b []routeSegment) (RouteConflictKind, bool) {
+	sameSignatures := true
+
+	for i := 0; ; i++ {
+		if i == len(a) || i == len(b) {
+			if len(a) != len(b) {
+				return 0, false
+			}
+
+			if sameSignatures {
+				return RouteUnreachable, false
+			}
+
+			return RouteAmbiguous, false
+		}
+
+		sa, sb := a[i], b[i]
+
+		switch {
+		case !sa.dynamic() && !sb.dynamic():
+			if sa.static != sb.static {
+				return 0, false
+			}
+		case sa.dynamic() != sb.dynamic():
+			// static segments always win, this is by-design.
+			return 0, false
+		case sa.wildcard != sb.wildcard:
+			// wildcard and a named parameter, the parameter wins.
+			return RouteShadowed, sa.wildcard
+		default:
+			if sa.param != nil && sb.param != nil && sa.signature() != sb.signature() {
+				sameSignatures = false
+			}
+		}
+	}
+}
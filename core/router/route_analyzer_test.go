@@ -0,0 +1,84 @@
+// black-box testing
+
+package router_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/core/router"
+)
+
+func TestAnalyzeRoutes(t *testing.T) {
+	noop := func(ctx iris.Context) {}
+
+	app := iris.New()
+	app.Get("/users/{id:uint64}", noop)
+	app.Get("/users/{name:string}", noop)
+	app.Get("/users/me", noop) // static, no conflict.
+	app.Post("/users/{name}", noop)
+	app.Post("/users/{username}", noop)
+	app.Get("/files/{p:path}", noop)
+	app.Get("/files/{name}", noop)
+	app.Get("/files/docs/readme", noop) // static, no conflict.
+	app.Get("/{p:path}", noop)          // root wildcard, no conflict with the static prefixed ones.
+	app.WildcardSubdomain().Get("/about", noop)
+	app.Subdomain("admin").Get("/about", noop)
+
+	conflicts := router.AnalyzeRoutes(app.GetRoutes())
+
+	expected := []struct {
+		kind  router.RouteConflictKind
+		route string
+		other string
+	}{
+		{router.RouteAmbiguous, "GET /users/{name:string}", "GET /users/{id:uint64}"},
+		{router.RouteUnreachable, "POST /users/{name}", "POST /users/{username}"},
+		{router.RouteShadowed, "GET /files/{p:path}", "GET /files/{name}"},
+		{router.RouteShadowed, "GET *./about", "GET admin./about"},
+	}
+
+	if expected, got := len(expected), len(conflicts); expected != got {
+		t.Fatalf("expected %d conflicts but got %d: %v", expected, got, conflicts)
+	}
+
+	for i, tt := range expected {
+		c := conflicts[i]
+		if c.Kind != tt.kind {
+			t.Fatalf("[%d] expected kind: %s but got: %s", i, tt.kind, c.Kind)
+		}
+
+		if got := c.Route.String(); got != tt.route {
+			t.Fatalf("[%d] expected route: %s but got: %s", i, tt.route, got)
+		}
+
+		if got := c.Other.String(); got != tt.other {
+			t.Fatalf("[%d] expected other route: %s but got: %s", i, tt.other, got)
+		}
+
+		if !strings.Contains(c.String(), "route_analyzer_test.go:") {
+			t.Fatalf("[%d] expected source location but got: %s", i, c.String())
+		}
+	}
+}
+
+func TestAnalyzeRoutesOnBuild(t *testing.T) {
+	noop := func(ctx iris.Context) {}
+
+	app := iris.New().Configure(iris.WithRouteConflicts("error"))
+	app.Get("/users/{id}", noop)
+	app.Get("/users/{name}", noop)
+
+	if err := app.Build(); err == nil || !strings.Contains(err.Error(), "unreachable") {
+		t.Fatalf("expected build to fail because of unreachable route but got: %v", err)
+	}
+
+	app = iris.New().Configure(iris.WithRouteConflicts("warn"))
+	app.Get("/users/{id}", noop)
+	app.Get("/users/{name}", noop)
+
+	if err := app.Build(); err != nil {
+		t.Fatalf("expected build to succeed on warn mode but got: %v", err)
+	}
+}
@@ -0,0 +1,137 @@
+package router
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// RouteTableEntry is the machine-readable representation of a Route.
+// See NewRouteTable and WriteRouteTable.
+type RouteTableEntry struct {
+	Method     string   `json:"method"`
+	Subdomain  string   `json:"subdomain,omitempty"`
+	Path       string   `json:"path"` // the path template, e.g. /users/{id:uint64}.
+	Name       string   `json:"name"`
+	StatusCode int      `json:"statusCode,omitempty"` // for HTTP error handlers.
+	Handler    string   `json:"handler"`              // the main handler's name.
+	Middleware []string `json:"middleware"`           // the rest of the handlers chain's names.
+	Handlers   []string `json:"handlers"`             // the full handlers chain's names.
+	Source     string   `json:"source"`               // file:line.
+}
+
+// NewRouteTable returns the RouteTableEntry values of the given routes, order is kept.
+// The routes should be built (e.g. after Application.Build) for their
+// handlers chain to include the global middleware too.
+func NewRouteTable(routes []*Route) []RouteTableEntry {
+	table := make([]RouteTableEntry, 0, len(routes))
+
+	for _, r := range routes {
+		handlers := r.Handlers
+		entry := RouteTableEntry{
+			Method:     r.Method,
+			Subdomain:  r.Subdomain,
+			Path:       r.tmpl.Src,
+			Name:       r.Name,
+			StatusCode: r.StatusCode,
+			Handler:    r.MainHandlerName,
+			Middleware: []string{},
+			Handlers:   make([]string, 0, len(handlers)),
+			Source:     fmt.Sprintf("%s:%d", r.SourceFileName, r.SourceLineNumber),
+		}
+
+		mainFound := false
+		for _, h := range handlers {
+			name := context.HandlerName(h)
+			if name == "" || ignoreRouteTableHandlerName(name) {
+				continue
+			}
+
+			entry.Handlers = append(entry.Handlers, name)
+
+			if !mainFound && name == r.MainHandlerName {
+				mainFound = true
+				continue
+			}
+
+			entry.Middleware = append(entry.Middleware, name)
+		}
+
+		table = append(table, entry)
+	}
+
+	return table
+}
+
+// ignoreRouteTableHandlerName reports whether a handler is an internal one,
+// closures of the ignored handlers (e.g. MakeHandler.func1) are ignored too.
+func ignoreRouteTableHandlerName(name string) bool {
+	if context.IgnoreHandlerName(name) {
+		return true
+	}
+
+	if idx := strings.LastIndex(name, ".func"); idx > 0 {
+		return context.IgnoreHandlerName(name[:idx])
+	}
+
+	return false
+}
+
+// Route table formats, see WriteRouteTable.
+const (
+	RouteTableText = "table"
+	RouteTableJSON = "json"
+	RouteTableCSV  = "csv"
+)
+
+// WriteRouteTable writes the route table of the given "routes" to "w"
+// in the form of "format", which can be "table" (default), "json" or "csv".
+// Order of registration is kept so the output can be compared between releases.
+func WriteRouteTable(w io.Writer, routes []*Route, format string) error {
+	table := NewRouteTable(routes)
+
+	switch format {
+	case RouteTableJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(table)
+	case RouteTableCSV:
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"method", "subdomain", "path", "name", "status_code", "handler", "middleware", "handlers", "source"})
+		for _, e := range table {
+			cw.Write([]string{e.Method, e.Subdomain, e.Path, e.Name, formatRouteTableStatusCode(e.StatusCode),
+				e.Handler, strings.Join(e.Middleware, " "), strings.Join(e.Handlers, " "), e.Source})
+		}
+		cw.Flush()
+		return cw.Error()
+	case RouteTableText, "":
+		tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(tw, "METHOD\tSUBDOMAIN\tPATH\tNAME\tHANDLER\tMIDDLEWARE\tSOURCE")
+		for _, e := range table {
+			method := e.Method
+			if e.StatusCode > 0 {
+				method = strconv.Itoa(e.StatusCode)
+			}
+
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				method, e.Subdomain, e.Path, e.Name, e.Handler, strings.Join(e.Middleware, ", "), e.Source)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("route table: unknown format: %q", format)
+	}
+}
+
+func formatRouteTableStatusCode(statusCode int) string {
+	if statusCode <= 0 {
+		return ""
+	}
+
+	return strconv.Itoa(statusCode)
+}
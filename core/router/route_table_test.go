@@ -0,0 +1,109 @@
+// black-box testing
+
+package router_test
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/core/router"
+)
+
+func routeTableMiddleware(ctx iris.Context) { ctx.Next() }
+
+func routeTableHandler(ctx iris.Context) {}
+
+func TestWriteRouteTable(t *testing.T) {
+	app := iris.New()
+	app.Use(routeTableMiddleware)
+	app.Get("/users/{id:uint64}", routeTableHandler).Name = "user"
+	app.Subdomain("admin").Post("/settings", routeTableHandler)
+
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := router.WriteRouteTable(&buf, app.GetRoutes(), router.RouteTableJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	var table []router.RouteTableEntry
+	if err := json.Unmarshal(buf.Bytes(), &table); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := 2, len(table); expected != got {
+		t.Fatalf("expected %d routes but got %d", expected, got)
+	}
+
+	// routes are sorted on Build, find them by method.
+	user, settings := table[0], table[1]
+	if user.Method != "GET" {
+		user, settings = settings, user
+	}
+
+	if user.Method != "GET" || user.Path != "/users/{id:uint64}" || user.Name != "user" {
+		t.Fatalf("unexpected route entry: %#v", user)
+	}
+
+	if !strings.HasSuffix(user.Handler, "routeTableHandler") {
+		t.Fatalf("expected main handler name but got: %s", user.Handler)
+	}
+
+	if len(user.Middleware) != 1 || !strings.HasSuffix(user.Middleware[0], "routeTableMiddleware") {
+		t.Fatalf("expected the middleware name but got: %v", user.Middleware)
+	}
+
+	if !strings.Contains(user.Source, "route_table_test.go:") {
+		t.Fatalf("expected source location but got: %s", user.Source)
+	}
+
+	if expected, got := "admin.", settings.Subdomain; expected != got {
+		t.Fatalf("expected subdomain: %s but got: %s", expected, got)
+	}
+
+	buf.Reset()
+	if err := router.WriteRouteTable(&buf, app.GetRoutes(), router.RouteTableCSV); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if expected, got := 3, len(lines); expected != got {
+		t.Fatalf("expected %d csv lines but got %d:\n%s", expected, got, buf.String())
+	}
+
+	if !strings.Contains(buf.String(), "\nGET,,/users/{id:uint64},user,,") {
+		t.Fatalf("unexpected csv output:\n%s", buf.String())
+	}
+
+	if header := lines[0]; header != "method,subdomain,path,name,status_code,handler,middleware,handlers,source" {
+		t.Fatalf("unexpected csv header: %s", header)
+	}
+
+	records, err := csv.NewReader(strings.NewReader(buf.String())).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if handlers := strings.Fields(records[1][7]); len(handlers) != 2 || !strings.HasSuffix(handlers[1], "routeTableHandler") {
+		t.Fatalf("expected the full handlers chain in csv but got: %v", handlers)
+	}
+
+	buf.Reset()
+	if err := router.WriteRouteTable(&buf, app.GetRoutes(), router.RouteTableText); err != nil {
+		t.Fatal(err)
+	}
+
+	if !strings.HasPrefix(buf.String(), "METHOD") || !strings.Contains(buf.String(), "admin.") {
+		t.Fatalf("unexpected table output:\n%s", buf.String())
+	}
+
+	if err := router.WriteRouteTable(&buf, app.GetRoutes(), "xml"); err == nil {
+		t.Fatalf("expected an error for unknown format")
+	}
+}
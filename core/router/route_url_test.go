@@ -0,0 +1,75 @@
+// black-box testing
+
+package router_test
+
+import (
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/core/router"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestRouteURL(t *testing.T) {
+	noop := func(ctx iris.Context) {}
+
+	app := iris.New()
+	app.Get("/users/{id:uint64 min(1)}", noop).Name = "user"
+	app.Get("/files/{file:path}", noop).Name = "file"
+	app.Subdomain("admin").Get("/settings", noop).Name = "admin.settings"
+	app.WildcardSubdomain().Get("/profile/{tab:string enum(posts,likes)}", noop).Name = "profile"
+
+	if _, err := app.URL("user", 42); err == nil {
+		t.Fatalf("expected an error when VHost is empty")
+	}
+
+	app.ConfigurationReadOnly().(*iris.Configuration).SetVHost("mydomain.com") // set on Listen.
+
+	tests := []struct {
+		name     string
+		args     []interface{}
+		expected string
+		err      error
+	}{
+		{"user", []interface{}{42}, "http://mydomain.com/users/42", nil},
+		{"user", []interface{}{uint64(42), url.Values{"tab": {"posts"}}}, "http://mydomain.com/users/42?tab=posts", nil},
+		{"user", []interface{}{0}, "", router.ErrInvalidURLParam},
+		{"user", []interface{}{"x"}, "", router.ErrInvalidURLParam},
+		{"user", nil, "", router.ErrInvalidURLParam},
+		{"file", []interface{}{"css/main file.css"}, "http://mydomain.com/files/css/main%20file.css", nil},
+		{"admin.settings", nil, "http://admin.mydomain.com/settings", nil},
+		{"profile", []interface{}{"kataras", "likes", map[string]string{"page": "2"}}, "http://kataras.mydomain.com/profile/likes?page=2", nil},
+		{"profile", []interface{}{"kataras", "followers"}, "", router.ErrInvalidURLParam},
+		{"missing", nil, "", router.ErrRouteNotFound},
+	}
+
+	for i, tt := range tests {
+		got, err := app.URL(tt.name, tt.args...)
+		if !errors.Is(err, tt.err) {
+			t.Fatalf("[%d] %s: expected error: %v but got: %v", i, tt.name, tt.err, err)
+		}
+
+		if got != tt.expected {
+			t.Fatalf("[%d] %s: expected: %s but got: %s", i, tt.name, tt.expected, got)
+		}
+	}
+}
+
+func TestRouteRequestURL(t *testing.T) {
+	app := iris.New()
+	app.Subdomain("admin").Get("/settings", func(ctx iris.Context) {}).Name = "admin.settings"
+	app.Get("/", func(ctx iris.Context) {
+		u, err := app.RequestURL(ctx, "admin.settings")
+		if err != nil {
+			ctx.StopWithError(iris.StatusInternalServerError, err)
+			return
+		}
+
+		ctx.WriteString(u)
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://example.com"))
+	e.GET("/").Expect().Status(httptest.StatusOK).Body().IsEqual("http://admin.example.com/settings")
+}
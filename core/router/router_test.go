@@ -1,6 +1,7 @@
 package router_test
 
 import (
+	"fmt"
 	"io"
 	"net/http"
 	"strings"
@@ -217,3 +218,43 @@ func TestHandleServer(t *testing.T) {
 	e.GET("/api/identity/first/orgs/second/test/me/kataras").Expect().Status(iris.StatusOK).Body().IsEqual("<h1>Other App: kataras</h1>")
 	e.GET("/api/identity/first/orgs/second/test/me").Expect().Status(iris.StatusNotFound)
 }
+
+func TestAuthorizeRegisteredRoutes(t *testing.T) {
+	app := iris.New()
+	app.UseRouter(func(ctx iris.Context) {
+		if role := ctx.GetHeader("X-Role"); role != "" {
+			ctx.SetUser(&context.SimpleUser{ID: "kataras", Roles: []string{role}})
+		}
+		ctx.Next()
+	})
+
+	handler := func(ctx iris.Context) {
+		ctx.WriteString(ctx.Path())
+	}
+
+	app.Get("/public", handler)
+	admin := app.Party("/admin")
+	admin.Get("/before", handler)
+	admin.Party("/users").Get("/", handler)
+	admin.Authorize("admin")
+	admin.Get("/after", handler)
+
+	e := httptest.New(t, app)
+	e.GET("/public").Expect().Status(httptest.StatusOK)
+	for _, path := range []string{"/admin/before", "/admin/users", "/admin/after"} {
+		e.GET(path).Expect().Status(httptest.StatusUnauthorized)
+		e.GET(path).WithHeader("X-Role", "guest").Expect().Status(httptest.StatusForbidden)
+		e.GET(path).WithHeader("X-Role", "admin").Expect().Status(httptest.StatusOK).Body().IsEqual(path)
+	}
+
+	for _, r := range app.GetRoutes() {
+		expected := "[admin]"
+		if r.Path == "/public" {
+			expected = "[]"
+		}
+
+		if got := fmt.Sprint(r.Authorization); got != expected {
+			t.Fatalf("%s: expected authorization: %s but got: %s", r.Path, expected, got)
+		}
+	}
+}
@@ -146,7 +146,7 @@ func getBindingsFor(inputs []reflect.Type, deps []*Dependency, disablePayloadAut
 	totalParamsExpected := 0
 	if paramsCount != -1 {
 		for i, in := range inputs {
-			if _, canBePathParameter := context.ParamResolvers[in]; !canBePathParameter {
+			if _, canBePathParameter := context.ParamResolverFor(in); !canBePathParameter {
 				continue
 			}
 			shouldBindParams[i] = struct{}{}
@@ -388,7 +388,14 @@ func paramDependencyHandler(paramIndex int) DependencyHandler {
 			return emptyValue, ErrSeeOther
 		}
 
-		return reflect.ValueOf(ctx.Params().Store[paramIndex].ValueRaw), nil
+		entry := ctx.Params().Store[paramIndex]
+		v := reflect.ValueOf(entry.ValueRaw)
+		if input != nil && input.Type != nil && input.Type.Kind() == reflect.String && v.Kind() != reflect.String {
+			// E.g. a string input of a {id:uuid} parameter.
+			return reflect.ValueOf(entry.String()).Convert(input.Type), nil
+		}
+
+		return v, nil
 	}
 }
 
@@ -0,0 +1,73 @@
+package i18n
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFormat(t *testing.T) {
+	i := New()
+	err := i.LoadKV(LangMap{
+		"en-US": map[string]interface{}{
+			"total": `Total: {{ formatCurrency .Amount "" }}`,
+		},
+		"el-GR": map[string]interface{}{
+			"total": `Σύνολο: {{ formatCurrency .Amount "" }}`,
+		},
+		"de-DE": map[string]interface{}{},
+		"it-IT": map[string]interface{}{},
+	}, "en-US", "el-GR", "de-DE", "it-IT")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := time.Date(2024, time.March, 5, 14, 7, 0, 0, time.UTC)
+	now := time.Now()
+
+	tests := []struct {
+		lang     string
+		got      string
+		expected string
+	}{
+		{"en-US", i.FormatNumber("en-US", 1234567.5), "1,234,567.5"},
+		{"el-GR", i.FormatNumber("el-GR", 1234567.5), "1.234.567,5"},
+		{"en-US", i.FormatNumber("en-US", "NaN"), "NaN"},
+		{"en-US", i.FormatPercent("en-US", 0.25), "25%"},
+		{"de-DE", i.FormatPercent("de-DE", 0.25), "25\u00a0%"},
+		{"en-US", i.FormatCurrency("en-US", 1234.5, ""), "$1,234.50"},
+		{"en-US", i.FormatCurrency("en-US", -3, "EUR"), "-€3.00"},
+		{"el-GR", i.FormatCurrency("el-GR", 1234.5, ""), "1.234,50\u00a0€"},
+		{"de-DE", i.FormatCurrency("de-DE", 1234, "JPY"), "1.234\u00a0¥"},
+		{"en-US", i.FormatDate("en-US", d, ShortStyle), "3/5/24"},
+		{"en-US", i.FormatDate("en-US", d, ""), "Mar 5, 2024"},
+		{"en-US", i.FormatDate("en-US", d, FullStyle), "Tuesday, March 5, 2024"},
+		{"el-GR", i.FormatDate("el-GR", d, LongStyle), "5 Μαρτίου 2024"},
+		{"de-DE", i.FormatDate("de-DE", d, FullStyle), "Dienstag, 5. März 2024"},
+		{"de-DE", i.FormatDate("de-DE", d, "y-MM-dd"), "2024-03-05"},
+		{"en-US", i.FormatTime("en-US", d, ShortStyle), "2:07 PM"},
+		{"el-GR", i.FormatTime("el-GR", d, ShortStyle), "2:07 μ.μ."},
+		{"de-DE", i.FormatTime("de-DE", d, MediumStyle), "14:07:00"},
+		{"en-US", i.FormatRelativeTime("en-US", now.Add(-3*24*time.Hour-time.Minute)), "3 days ago"},
+		{"en-US", i.FormatRelativeTime("en-US", now.Add(-time.Hour-time.Minute)), "1 hour ago"},
+		{"en-US", i.FormatRelativeTime("en-US", now.Add(2*time.Hour+time.Minute)), "in 2 hours"},
+		{"en-US", i.FormatRelativeTime("en-US", now), "now"},
+		{"el-GR", i.FormatRelativeTime("el-GR", now.Add(-400*24*time.Hour)), "πριν από 1 έτος"},
+		{"de-DE", i.FormatRelativeTime("de-DE", now.Add(15*24*time.Hour)), "in 2 Wochen"},
+		// languages without date names, patterns and relative times
+		// fallback to the English ones, numbers follow the language.
+		{"it-IT", i.FormatNumber("it-IT", 1234567.5), "1.234.567,5"},
+		{"it-IT", i.FormatDate("it-IT", d, LongStyle), "March 5, 2024"},
+		{"it-IT", i.FormatRelativeTime("it-IT", now.Add(-3*24*time.Hour-time.Minute)), "3 days ago"},
+		// fallback to the default language.
+		{"xx", i.FormatNumber("xx", 1000), "1,000"},
+		// locale's template funcs.
+		{"en-US", i.Tr("en-US", "total", map[string]interface{}{"Amount": 10}), "Total: $10.00"},
+		{"el-GR", i.Tr("el-GR", "total", map[string]interface{}{"Amount": 10}), "Σύνολο: 10,00\u00a0€"},
+	}
+
+	for idx, tt := range tests {
+		if tt.got != tt.expected {
+			t.Fatalf("[%d] [%s] expected:\n%q\nbut got:\n%q", idx, tt.lang, tt.expected, tt.got)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package i18n
+
+import (
+	"bytes"
+	"encoding/binary"
+	"sort"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+const testPO = `# Greek translations.
+msgid ""
+msgstr ""
+"Language: el-GR\n"
+"Content-Type: text/plain; charset=UTF-8\n"
+"Plural-Forms: nplurals=2; plural=(n != 1);\n"
+
+# Translator comment.
+#. Extracted comment.
+#: main.go:10
+msgid "Hello"
+msgstr "Γειά"
+
+msgctxt "menu"
+msgid "Open"
+msgstr "Άνοιγμα"
+
+msgid "Open"
+msgstr "Ανοιχτό"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d αρχείο"
+msgstr[1] "%d αρχεία"
+
+#, fuzzy
+msgid "Fuzzy"
+msgstr "Ασαφές"
+
+msgid "Untranslated"
+msgstr ""
+
+msgid "Multiline"
+msgstr ""
+"first\n"
+"second"
+`
+
+const testRussianPO = `msgid ""
+msgstr ""
+"Plural-Forms: nplurals=3; plural=(n%10==1 && n%100!=11 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);\n"
+
+msgid "%d file"
+msgid_plural "%d files"
+msgstr[0] "%d файл"
+msgstr[1] "%d файла"
+msgstr[2] "%d файлов"
+`
+
+const testXLIFF = `<?xml version="1.0" encoding="UTF-8"?>
+<xliff xmlns="urn:oasis:names:tc:xliff:document:2.0" version="2.0" srcLang="en-US" trgLang="de-DE">
+  <file id="f1">
+    <unit id="Hello">
+      <notes><note>Greeting.</note></notes>
+      <segment><source>Hello</source><target>Hallo</target></segment>
+    </unit>
+    <unit id="u2" name="Good morning">
+      <segment><source>Good morning</source><target>Guten Morgen</target></segment>
+    </unit>
+    <group id="menu">
+      <unit id="Open"><segment><source>Open</source><target>Öffnen</target></segment></unit>
+    </group>
+    <group id="files" type="x-gettext-plurals">
+      <unit id="0"><segment><source>%d file</source><target>%d Datei</target></segment></unit>
+      <unit id="1"><segment><source>%d files</source><target>%d Dateien</target></segment></unit>
+    </group>
+    <unit id="untranslated"><segment><source>Untranslated</source></segment></unit>
+  </file>
+</xliff>
+`
+
+// buildMO returns a gettext MO file of the "entries", msgid (with the optional msgctxt) to msgstr.
+func buildMO(entries map[string]string) []byte {
+	ids := make([]string, 0, len(entries))
+	for id := range entries {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	n := len(ids)
+	idsOffset, valuesOffset := 28, 28+n*8
+	dataOffset := valuesOffset + n*8
+
+	var (
+		header = make([]byte, dataOffset)
+		data   bytes.Buffer
+	)
+
+	binary.LittleEndian.PutUint32(header[0:], 0x950412de)
+	binary.LittleEndian.PutUint32(header[8:], uint32(n))
+	binary.LittleEndian.PutUint32(header[12:], uint32(idsOffset))
+	binary.LittleEndian.PutUint32(header[16:], uint32(valuesOffset))
+
+	writeString := func(tableOffset, i int, s string) {
+		binary.LittleEndian.PutUint32(header[tableOffset+i*8:], uint32(len(s)))
+		binary.LittleEndian.PutUint32(header[tableOffset+i*8+4:], uint32(dataOffset+data.Len()))
+		data.WriteString(s)
+		data.WriteByte(0)
+	}
+
+	for i, id := range ids {
+		writeString(idsOffset, i, id)
+	}
+	for i, id := range ids {
+		writeString(valuesOffset, i, entries[id])
+	}
+
+	return append(header, data.Bytes()...)
+}
+
+func TestGettextLoaders(t *testing.T) {
+	fileSystem := fstest.MapFS{
+		"locales/en-US/messages.yml": {Data: []byte("Hello: Hello\n")},
+		"locales/el-GR/messages.po":  {Data: []byte(testPO)},
+		"locales/ru-RU/messages.po":  {Data: []byte(testRussianPO)},
+		"locales/de-DE/messages.xlf": {Data: []byte(testXLIFF)},
+		"locales/fr-FR/messages.mo": {Data: buildMO(map[string]string{
+			"":                    "Plural-Forms: nplurals=2; plural=(n > 1);\n",
+			"Hello":               "Bonjour",
+			"menu\x04Open":        "Ouvrir",
+			"%d file\x00%d files": "%d fichier\x00%d fichiers",
+		})},
+	}
+
+	i := New()
+	if err := i.LoadFS(fileSystem, "./locales/*/*", "en-US", "el-GR", "ru-RU", "de-DE", "fr-FR"); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		lang     string
+		key      string
+		args     []interface{}
+		expected string
+	}{
+		{"el-GR", "Hello", nil, "Γειά"},
+		{"el-GR", "menu.Open", nil, "Άνοιγμα"},
+		{"el-GR", "Open", nil, "Ανοιχτό"},
+		{"el-GR", "%d file", []interface{}{1}, "1 αρχείο"},
+		{"el-GR", "%d file", []interface{}{3}, "3 αρχεία"},
+		{"el-GR", "Multiline", nil, "first\nsecond"},
+		// fuzzy and untranslated entries fallback to the default language.
+		{"el-GR", "Fuzzy", nil, ""},
+		{"el-GR", "Untranslated", nil, ""},
+		{"ru-RU", "%d file", []interface{}{1}, "1 файл"},
+		{"ru-RU", "%d file", []interface{}{3}, "3 файла"},
+		{"ru-RU", "%d file", []interface{}{11}, "11 файлов"},
+		{"ru-RU", "%d file", []interface{}{21}, "21 файл"},
+		{"de-DE", "Hello", nil, "Hallo"},
+		{"de-DE", "Good morning", nil, "Guten Morgen"},
+		{"de-DE", "menu.Open", nil, "Öffnen"},
+		{"de-DE", "files", []interface{}{1}, "1 Datei"},
+		{"de-DE", "files", []interface{}{2}, "2 Dateien"},
+		{"fr-FR", "Hello", nil, "Bonjour"},
+		{"fr-FR", "menu.Open", nil, "Ouvrir"},
+		{"fr-FR", "%d file", []interface{}{0}, "0 fichier"},
+		{"fr-FR", "%d file", []interface{}{2}, "2 fichiers"},
+	}
+
+	for _, tt := range tests {
+		if got := i.Tr(tt.lang, tt.key, tt.args...); got != tt.expected {
+			t.Errorf("[%s] %s: expected: %q but got: %q", tt.lang, tt.key, tt.expected, got)
+		}
+	}
+
+	var po bytes.Buffer
+	if err := i.ExportPO(&po, "el-GR"); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, expected := range []string{
+		"\"Plural-Forms: nplurals=2; plural=(n != 1);\\n\"\n",
+		"# Translator comment.\n#. Extracted comment.\n#: main.go:10\nmsgid \"Hello\"\nmsgstr \"Γειά\"\n",
+		"msgctxt \"menu\"\nmsgid \"Open\"\nmsgstr \"Άνοιγμα\"\n",
+		"msgid \"%d file\"\nmsgid_plural \"%d files\"\nmsgstr[0] \"%d αρχείο\"\nmsgstr[1] \"%d αρχεία\"\n",
+		"msgid \"Multiline\"\nmsgstr \"\"\n\"first\\n\"\n\"second\"\n",
+	} {
+		if !strings.Contains(po.String(), expected) {
+			t.Fatalf("expected exported PO to contain:\n%s\nbut got:\n%s", expected, po.String())
+		}
+	}
+
+	var xliff bytes.Buffer
+	if err := i.ExportXLIFF(&xliff, "de-DE"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Round-trip: load the exported files again.
+	i = New()
+	err := i.LoadFS(fstest.MapFS{
+		"locales/el-GR/messages.po":  {Data: po.Bytes()},
+		"locales/de-DE/messages.xlf": {Data: xliff.Bytes()},
+	}, "./locales/*/*", "el-GR", "de-DE")
+	if err != nil {
+		t.Fatalf("%v:\n%s", err, xliff.String())
+	}
+
+	for _, tt := range tests {
+		if tt.lang != "el-GR" && tt.lang != "de-DE" || tt.expected == "" {
+			continue
+		}
+
+		if got := i.Tr(tt.lang, tt.key, tt.args...); got != tt.expected {
+			t.Errorf("round-trip: [%s] %s: expected: %q but got: %q", tt.lang, tt.key, tt.expected, got)
+		}
+	}
+}
+
+func TestGettextInvalid(t *testing.T) {
+	invalid := map[string]string{
+		"messages.po":  "msgid \"Hello\"\nmsgstr[1] \"Γειά\"\n",
+		"plurals.po":   "msgid \"\"\nmsgstr \"Plural-Forms: nplurals=2; plural=(n != ;\\n\"\n",
+		"forms.po":     "msgid \"%d file\"\nmsgid_plural \"%d files\"\nmsgstr[0] \"%d αρχείο\"\n",
+		"messages.mo":  "invalid",
+		"messages.xlf": `<xliff version="1.2"></xliff>`,
+		// A 28-byte MO header with a huge number of strings.
+		"huge.mo": string([]byte{0xde, 0x12, 0x04, 0x95, 0, 0, 0, 0, 0xff, 0xff, 0xff, 0x7f, 28, 0, 0, 0, 28, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}),
+	}
+
+	for name, contents := range invalid {
+		i := New()
+		err := i.LoadFS(fstest.MapFS{"locales/el-GR/" + name: {Data: []byte(contents)}}, "./locales/*/*", "el-GR")
+		if err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -4,11 +4,13 @@ package i18n
 
 import (
 	"fmt"
+	"io"
 	"io/fs"
 	"net/http"
 	"os"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/kataras/iris/v12/context"
 	"github.com/kataras/iris/v12/core/router"
@@ -51,9 +53,10 @@ type I18n struct {
 	localizer Localizer
 	matcher   *Matcher
 
-	Loader LoaderConfig
-	loader Loader
-	mu     sync.Mutex
+	Loader   LoaderConfig
+	loader   Loader
+	mu       sync.RWMutex // protects the loader, matcher and localizer fields.
+	reloadMu sync.Mutex
 
 	// ExtractFunc is the type signature for declaring custom logic
 	// to extract the language tag name.
@@ -94,6 +97,19 @@ type I18n struct {
 	//
 	// Defaults to true.
 	PathRedirect bool
+
+	// If not nil, it records the keys that were not found per language,
+	// see `NewMissingTranslations` and `Scan` method too.
+	//
+	// Defaults to nil.
+	Missing *MissingTranslations
+
+	// OnReload is called after the locales are reloaded by the `Reload` or `Watch` methods.
+	// The "err" is not nil when the new locales were rejected, the previous ones keep serving.
+	// Useful for logging.
+	//
+	// Defaults to nil.
+	OnReload func(err error)
 }
 
 var _ context.I18nReadOnly = (*I18n)(nil)
@@ -169,39 +185,88 @@ func (i *I18n) LoadKV(langMap LangMap, languages ...string) error {
 func (i *I18n) Reset(loader Loader, languages ...string) error {
 	tags := makeTags(languages...)
 
-	i.loader = loader
-	i.matcher = &Matcher{
+	m := &Matcher{
 		strict:             len(tags) > 0,
 		Languages:          tags,
 		matcher:            language.NewMatcher(tags),
 		defaultMessageFunc: i.DefaultMessageFunc,
 	}
 
-	return i.reload()
+	return i.load(loader, m)
 }
 
-// reload loads the language files from the provided Loader,
-// the `New` package-level function preloads those files already.
-func (i *I18n) reload() error { // May be an exported function, if requested.
-	i.mu.Lock()
-	defer i.mu.Unlock()
+// Reload loads the locales again from the current Loader, e.g. after the locale files were modified.
+// The new catalog replaces the current one atomically, under live traffic, only when it
+// is loaded successfully, otherwise the previous one keeps serving and the error is returned.
+// The `OnReload` hook is called in both cases.
+//
+// See `Watch` method too.
+func (i *I18n) Reload() error {
+	i.mu.RLock()
+	loader := i.loader
+	i.mu.RUnlock()
+
+	return i.reload(loader)
+}
+
+// reload loads the locales from the given loader, the language tags are kept,
+// and calls the OnReload hook.
+func (i *I18n) reload(loader Loader) error {
+	i.reloadMu.Lock()
+	defer i.reloadMu.Unlock()
 
-	if i.loader == nil {
+	err := i.load(loader, i.getMatcher().clone())
+	if i.OnReload != nil {
+		i.OnReload(err)
+	}
+
+	return err
+}
+
+// load loads the language files from the provided Loader and,
+// on success, replaces the current loader, matcher and localizer.
+func (i *I18n) load(loader Loader, m *Matcher) error {
+	if loader == nil {
 		return fmt.Errorf("nil loader")
 	}
 
-	localizer, err := i.loader(i.matcher)
+	localizer, err := loader(m)
 	if err != nil {
 		return err
 	}
 
+	i.mu.Lock()
+	i.loader = loader
+	i.matcher = m
 	i.localizer = localizer
+	i.mu.Unlock()
 	return nil
 }
 
+func (i *I18n) getMatcher() *Matcher {
+	i.mu.RLock()
+	m := i.matcher
+	i.mu.RUnlock()
+	return m
+}
+
+func (i *I18n) getLocalizer() Localizer {
+	i.mu.RLock()
+	l := i.localizer
+	i.mu.RUnlock()
+	return l
+}
+
 // Loaded reports whether `New` or `Load/LoadAssets` called.
 func (i *I18n) Loaded() bool {
-	return i != nil && i.loader != nil && i.localizer != nil && i.matcher != nil
+	if i == nil {
+		return false
+	}
+
+	i.mu.RLock()
+	loaded := i.loader != nil && i.localizer != nil && i.matcher != nil
+	i.mu.RUnlock()
+	return loaded
 }
 
 // Tags returns the registered languages or dynamically resolved by files.
@@ -211,7 +276,7 @@ func (i *I18n) Tags() []language.Tag {
 		return nil
 	}
 
-	return i.matcher.Languages
+	return i.getMatcher().Languages
 }
 
 // SetDefault changes the default language.
@@ -223,18 +288,24 @@ func (i *I18n) SetDefault(langCode string) bool {
 		return false
 	}
 
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
 	if tag, index, conf := i.matcher.Match(t); conf > language.Low {
 		if l, ok := i.localizer.(interface {
 			SetDefault(int) bool
 		}); ok {
 			if l.SetDefault(index) {
-				tags := i.matcher.Languages
+				// The current matcher may be used without a lock (see getMatcher),
+				// replace it with a new one, like the load method does.
+				m := i.matcher.clone()
+				tags := m.Languages
 				// set the order
 				tags[index] = tags[0]
 				tags[0] = tag
 
-				i.matcher.Languages = tags
-				i.matcher.matcher = language.NewMatcher(tags)
+				m.matcher = language.NewMatcher(tags)
+				i.matcher = m
 				return true
 			}
 		}
@@ -243,6 +314,56 @@ func (i *I18n) SetDefault(langCode string) bool {
 	return false
 }
 
+// ExportPO writes the translations of the "lang" language in the gettext PO format.
+// Translator comments, contexts and plural forms of the loaded PO, MO and XLIFF files are kept,
+// plural messages of the template syntax are not exported.
+//
+// See `ExportXLIFF` too.
+func (i *I18n) ExportPO(w io.Writer, lang string) error {
+	loc, err := i.exportLocale(lang)
+	if err != nil {
+		return err
+	}
+
+	return internal.WritePO(w, loc)
+}
+
+// ExportXLIFF writes the translations of the "lang" language in the XLIFF 2.0 format.
+// The source texts are the default language's ones.
+//
+// See `ExportPO` too.
+func (i *I18n) ExportXLIFF(w io.Writer, lang string) error {
+	loc, err := i.exportLocale(lang)
+	if err != nil {
+		return err
+	}
+
+	source, err := i.exportLocale(i.getMatcher().Languages[0].String())
+	if err != nil {
+		return err
+	}
+
+	return internal.WriteXLIFF(w, source, loc)
+}
+
+func (i *I18n) exportLocale(lang string) (*internal.Locale, error) {
+	if !i.Loaded() {
+		return nil, fmt.Errorf("i18n: not loaded")
+	}
+
+	_, index, ok := i.TryMatchString(lang)
+	if !ok {
+		return nil, fmt.Errorf("i18n: language %q not found", lang)
+	}
+
+	loc, ok := i.getLocalizer().GetLocale(index).(*internal.Locale)
+	if !ok {
+		return nil, fmt.Errorf("i18n: export is not supported by the custom localizer of %T", i.getLocalizer())
+	}
+
+	return loc, nil
+}
+
 // Matcher implements the languae.Matcher.
 // It contains the original language Matcher and keeps an ordered
 // list of the registered languages for further use (see `Loader` implementation).
@@ -263,6 +384,20 @@ func (m *Matcher) Match(t ...language.Tag) (language.Tag, int, language.Confiden
 	return m.matcher.Match(t...)
 }
 
+// clone returns a copy of the Matcher, so a Loader can add languages
+// to it without modifying the one which serves the requests.
+func (m *Matcher) clone() *Matcher {
+	languages := make([]language.Tag, len(m.Languages))
+	copy(languages, m.Languages)
+
+	return &Matcher{
+		strict:             m.strict,
+		Languages:          languages,
+		matcher:            language.NewMatcher(languages),
+		defaultMessageFunc: m.defaultMessageFunc,
+	}
+}
+
 // MatchOrAdd acts like Match but it checks and adds a language tag, if not found,
 // when the `Matcher.strict` field is true (when no tags are provided by the caller)
 // and they should be dynamically added to the list.
@@ -353,7 +488,7 @@ func parseLanguage(path string) (language.Tag, bool) {
 // It returns -1 as the language index and false if not found.
 func (i *I18n) TryMatchString(s string) (language.Tag, int, bool) {
 	if tag, err := language.Parse(s); err == nil {
-		if tag, index, conf := i.matcher.Match(tag); conf > language.Low {
+		if tag, index, conf := i.getMatcher().Match(tag); conf > language.Low {
 			return tag, index, true
 		}
 	}
@@ -367,11 +502,7 @@ func (i *I18n) TryMatchString(s string) (language.Tag, int, bool) {
 // It returns an empty string if "lang" not matched, unless DefaultMessageFunc.
 // It returns the default language's translation if "key" not matched, unless DefaultMessageFunc.
 func (i *I18n) Tr(lang, key string, args ...interface{}) string {
-	_, index, ok := i.TryMatchString(lang)
-	if !ok {
-		index = 0
-	}
-	loc := i.localizer.GetLocale(index)
+	loc := i.getLocaleByLang(lang)
 	return i.getLocaleMessage(loc, lang, key, args...)
 }
 
@@ -384,6 +515,92 @@ func (i *I18n) TrContext(ctx *context.Context, key string, args ...interface{})
 	return i.getLocaleMessage(loc, langInput, key, args...)
 }
 
+// The date and time styles of the FormatDate and FormatTime methods.
+const (
+	ShortStyle  = internal.ShortStyle
+	MediumStyle = internal.MediumStyle
+	LongStyle   = internal.LongStyle
+	FullStyle   = internal.FullStyle
+)
+
+// getLocaleByLang returns the Locale of the "lang" language code
+// or the default one if "lang" not matched.
+func (i *I18n) getLocaleByLang(lang string) context.Locale {
+	_, index, ok := i.TryMatchString(lang)
+	if !ok {
+		index = 0
+	}
+
+	return i.getLocalizer().GetLocale(index)
+}
+
+// getLocaleFormatter returns the Locale of the "lang" language code
+// if it implements the context.LocaleFormatter, custom Localizers may not.
+func (i *I18n) getLocaleFormatter(lang string) (context.LocaleFormatter, bool) {
+	f, ok := i.getLocaleByLang(lang).(context.LocaleFormatter)
+	return f, ok
+}
+
+// FormatNumber returns the "n" number formatted based on the "lang" language code,
+// e.g. 1234.5 as "1,234.5" for "en-US" and "1.234,5" for "el-GR".
+// See `context.LocaleFormatter` for the request's locale.
+func (i *I18n) FormatNumber(lang string, n interface{}) string {
+	if f, ok := i.getLocaleFormatter(lang); ok {
+		return f.FormatNumber(n)
+	}
+
+	return fmt.Sprint(n)
+}
+
+// FormatPercent returns the "n" number formatted as a percentage based on the "lang" language code.
+func (i *I18n) FormatPercent(lang string, n interface{}) string {
+	if f, ok := i.getLocaleFormatter(lang); ok {
+		return f.FormatPercent(n)
+	}
+
+	return fmt.Sprint(n)
+}
+
+// FormatCurrency returns the "amount" formatted in the "currencyCode" currency, e.g. "EUR",
+// based on the "lang" language code. An empty "currencyCode" means the currency of the language's region.
+func (i *I18n) FormatCurrency(lang string, amount interface{}, currencyCode string) string {
+	if f, ok := i.getLocaleFormatter(lang); ok {
+		return f.FormatCurrency(amount, currencyCode)
+	}
+
+	return fmt.Sprint(amount)
+}
+
+// FormatDate returns the date of "t" in the "short", "medium", "long" or "full" style
+// based on the "lang" language code.
+func (i *I18n) FormatDate(lang string, t time.Time, style string) string {
+	if f, ok := i.getLocaleFormatter(lang); ok {
+		return f.FormatDate(t, style)
+	}
+
+	return t.String()
+}
+
+// FormatTime returns the time of "t" in the "short", "medium", "long" or "full" style
+// based on the "lang" language code.
+func (i *I18n) FormatTime(lang string, t time.Time, style string) string {
+	if f, ok := i.getLocaleFormatter(lang); ok {
+		return f.FormatTime(t, style)
+	}
+
+	return t.String()
+}
+
+// FormatRelativeTime returns the time between now and "t", e.g. "3 days ago" or "in 2 hours",
+// based on the "lang" language code.
+func (i *I18n) FormatRelativeTime(lang string, t time.Time) string {
+	if f, ok := i.getLocaleFormatter(lang); ok {
+		return f.FormatRelativeTime(t)
+	}
+
+	return t.String()
+}
+
 func (i *I18n) getLocaleMessage(loc context.Locale, langInput string, key string, args ...interface{}) (msg string) {
 	langMatched := ""
 
@@ -391,9 +608,16 @@ func (i *I18n) getLocaleMessage(loc context.Locale, langInput string, key string
 		langMatched = loc.Language()
 
 		msg = loc.GetMessage(key, args...)
-		if msg == "" && i.DefaultMessageFunc == nil && !i.Strict && loc.Index() > 0 {
-			// it's not the default/fallback language and not message found for that lang:key.
-			msg = i.localizer.GetLocale(0).GetMessage(key, args...)
+		if msg == "" {
+			i.Missing.Add(langMatched, key)
+
+			if i.DefaultMessageFunc == nil && !i.Strict && loc.Index() > 0 {
+				// it's not the default/fallback language and not message found for that lang:key.
+				defaultLoc := i.getLocalizer().GetLocale(0)
+				if msg = defaultLoc.GetMessage(key, args...); msg == "" {
+					i.Missing.Add(defaultLoc.Language(), key)
+				}
+			}
 		}
 	}
 
@@ -429,7 +653,7 @@ func (i *I18n) GetLocale(ctx *context.Context) context.Locale {
 				_, index, _ = i.TryMatchString(v)
 			}
 
-			locale := i.localizer.GetLocale(index)
+			locale := i.getLocalizer().GetLocale(index)
 			if locale == nil {
 				return nil
 			}
@@ -471,14 +695,14 @@ func (i *I18n) GetLocale(ctx *context.Context) context.Locale {
 			extractedLang = v // note.
 			desired, _, err := language.ParseAcceptLanguage(v)
 			if err == nil {
-				if _, idx, conf := i.matcher.Match(desired...); conf > language.Low {
+				if _, idx, conf := i.getMatcher().Match(desired...); conf > language.Low {
 					index = idx
 				}
 			}
 		}
 	}
 
-	// locale := i.localizer.GetLocale(index)
+	// locale := i.getLocalizer().GetLocale(index)
 	// ctx.Values().Set(ctx.Application().ConfigurationReadOnly().GetLocaleContextKey(), locale)
 
 	if languageInputKey != "" {
@@ -489,7 +713,7 @@ func (i *I18n) GetLocale(ctx *context.Context) context.Locale {
 	}
 
 	// if index == 0 then it defaults to the first language.
-	locale := i.localizer.GetLocale(index)
+	locale := i.getLocalizer().GetLocale(index)
 	if locale == nil {
 		return nil
 	}
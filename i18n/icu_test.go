@@ -0,0 +1,116 @@
+package i18n
+
+import (
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestICUSyntax(t *testing.T) {
+	i := New()
+	i.Loader.Syntax = ICUSyntax
+
+	err := i.LoadKV(LangMap{
+		"en-US": map[string]interface{}{
+			"inbox":  "You have {count, plural, =0 {no messages} one {# message} other {# messages}}.",
+			"invite": "{host} invited {guests, plural, offset:1 =0 {nobody} =1 {{guest}} one {{guest} and # other} other {{guest} and # others}}.",
+			"gender": "{gender, select, female {She} male {He} other {They}} replied.",
+			"place":  "You finished {place, selectordinal, one {#st} two {#nd} few {#rd} other {#th}}!",
+			"price":  "Total: {amount, number, ::currency/EUR}, discount: {discount, number, percent}.",
+			"big":    "{n, number} {n, number, integer} {n, number, ::.00 group-off}",
+			"date":   "{d, date, short}|{d, date, long}|{d, time, short}|{d, date, ::yMMMd}|{d, date, yyyy-MM-dd 'at' HH:mm}",
+			"quotes": "It''s '{literal}' {0}",
+			"user": map[string]interface{}{
+				"name": "User {Name}",
+			},
+		},
+		"el-GR": map[string]interface{}{
+			"inbox": "Έχετε {count, plural, =0 {κανένα μήνυμα} one {# μήνυμα} other {# μηνύματα}}.",
+			"big":   "{n, number}",
+			"price": "Σύνολο: {amount, number, ::currency/EUR}, {amount, number, ::currency/EUR precision-integer}.",
+		},
+	}, "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	d := time.Date(2024, time.March, 5, 14, 7, 0, 0, time.UTC)
+
+	tests := []struct {
+		lang     string
+		key      string
+		args     []interface{}
+		expected string
+	}{
+		{"en-US", "inbox", []interface{}{map[string]interface{}{"count": 0}}, "You have no messages."},
+		{"en-US", "inbox", []interface{}{map[string]interface{}{"count": 1}}, "You have 1 message."},
+		{"en-US", "inbox", []interface{}{map[string]int{"count": 1500}}, "You have 1,500 messages."},
+		{"el-GR", "inbox", []interface{}{map[string]int{"count": 2}}, "Έχετε 2 μηνύματα."},
+		{"en-US", "invite", []interface{}{map[string]interface{}{"host": "Alice", "guests": 0, "guest": "Bob"}}, "Alice invited nobody."},
+		{"en-US", "invite", []interface{}{map[string]interface{}{"host": "Alice", "guests": 1, "guest": "Bob"}}, "Alice invited Bob."},
+		{"en-US", "invite", []interface{}{map[string]interface{}{"host": "Alice", "guests": 2, "guest": "Bob"}}, "Alice invited Bob and 1 other."},
+		{"en-US", "invite", []interface{}{map[string]interface{}{"host": "Alice", "guests": 3, "guest": "Bob"}}, "Alice invited Bob and 2 others."},
+		{"en-US", "gender", []interface{}{map[string]string{"gender": "female"}}, "She replied."},
+		{"en-US", "gender", []interface{}{map[string]string{"gender": "unknown"}}, "They replied."},
+		{"en-US", "place", []interface{}{map[string]int{"place": 1}}, "You finished 1st!"},
+		{"en-US", "place", []interface{}{map[string]int{"place": 22}}, "You finished 22nd!"},
+		{"en-US", "place", []interface{}{map[string]int{"place": 13}}, "You finished 13th!"},
+		{"en-US", "price", []interface{}{map[string]interface{}{"amount": 1234.5, "discount": 0.25}}, "Total: €1,234.50, discount: 25%."},
+		{"en-US", "big", []interface{}{map[string]interface{}{"n": 1234.5678}}, "1,234.568 1,235 1234.57"},
+		{"el-GR", "big", []interface{}{map[string]interface{}{"n": 1234.5}}, "1.234,5"},
+		{"el-GR", "price", []interface{}{map[string]interface{}{"amount": 1234.56}}, "Σύνολο: 1.234,56\u00a0€, 1.235\u00a0€."},
+		{"en-US", "date", []interface{}{map[string]interface{}{"d": d}}, "3/5/24|March 5, 2024|2:07 PM|Mar 5, 2024|2024-03-05 at 14:07"},
+		{"en-US", "quotes", []interface{}{"positional"}, "It's {literal} positional"},
+		{"en-US", "user.name", []interface{}{struct{ Name string }{"kataras"}}, "User kataras"},
+		// Fallbacks to the default language.
+		{"el-GR", "gender", []interface{}{map[string]string{"gender": "male"}}, "He replied."},
+		// Missing arguments.
+		{"en-US", "gender", nil, `key: "gender": missing argument: "gender"`},
+	}
+
+	for _, tt := range tests {
+		if got := i.Tr(tt.lang, tt.key, tt.args...); got != tt.expected {
+			t.Errorf("[%s] %s: expected:\n%s\nbut got:\n%s", tt.lang, tt.key, tt.expected, got)
+		}
+	}
+}
+
+func TestICUSyntaxInvalid(t *testing.T) {
+	invalid := []string{
+		"{count, plural, one {# message}}",
+		"{count, plural, single {# message} other {# messages}}",
+		"{count, unknown}",
+		"{count, number, ::unknown}",
+		"{name",
+		"name}",
+	}
+
+	for _, value := range invalid {
+		i := New()
+		i.Loader.Syntax = ICUSyntax
+		if err := i.LoadKV(LangMap{"en-US": {"key": value}}, "en-US"); err == nil {
+			t.Errorf("expected an error for: %s", value)
+		}
+	}
+}
+
+func TestICUFiles(t *testing.T) {
+	fileSystem := fstest.MapFS{
+		"locales/en-US/template.yml":     {Data: []byte("hello: Hello {{.Name}}\n")},
+		"locales/en-US/messages.icu.yml": {Data: []byte("items: \"{count, plural, one {# item} other {# items}}\"\n")},
+	}
+
+	i := New()
+	i.Loader.SyntaxFunc = ICUFiles("*.icu.yml")
+	if err := i.LoadFS(fileSystem, "./locales/*/*", "en-US"); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "Hello kataras", i.Tr("en-US", "hello", map[string]string{"Name": "kataras"}); expected != got {
+		t.Fatalf("expected: %s but got: %s", expected, got)
+	}
+
+	if expected, got := "3 items", i.Tr("en-US", "items", map[string]int{"count": 3}); expected != got {
+		t.Fatalf("expected: %s but got: %s", expected, got)
+	}
+}
@@ -44,6 +44,12 @@ type Options struct {
 	DefaultMessageFunc MessageFunc
 	// Customize the overall behavior of the plurazation feature.
 	PluralFormDecoder PluralFormDecoder
+	// The syntax of the translation messages, see `ICUSyntax`.
+	// Defaults to TemplateSyntax.
+	Syntax MessageSyntax
+	// Optional function to select the syntax per locale file,
+	// e.g. ICUSyntax for files ending with ".icu.yml". Overrides the Syntax field.
+	SyntaxFunc func(fileName string) MessageSyntax
 }
 
 // NewCatalog returns a new Catalog based on the registered languages and the loader options.
@@ -107,6 +113,15 @@ func (c *Catalog) Store(langIndex int, kv Map) error {
 	return loc.Load(c, kv)
 }
 
+// StoreICU stores the a map of ICU MessageFormat values to the locale derives from the given "langIndex".
+func (c *Catalog) StoreICU(langIndex int, kv Map) error {
+	loc := c.getLocale(langIndex)
+	if loc == nil {
+		return fmt.Errorf("expected language index to be lower or equal than %d but got %d", len(c.Locales), langIndex)
+	}
+	return loc.LoadICU(kv)
+}
+
 /* Localizer interface. */
 
 // SetDefault changes the default language based on the "index".
@@ -0,0 +1,346 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/language"
+	"golang.org/x/text/number"
+)
+
+// The date and time styles of the FormatDate and FormatTime methods.
+const (
+	ShortStyle  = "short"
+	MediumStyle = "medium"
+	LongStyle   = "long"
+	FullStyle   = "full"
+)
+
+type (
+	// dateField formats a part of a date pattern.
+	dateField func(t time.Time, sym *dateSymbols) string
+
+	dateSymbols struct {
+		months, shortMonths     [12]string
+		weekdays, shortWeekdays [7]string // starting from Sunday.
+		am, pm                  string
+	}
+
+	// relativeTimeUnit holds the past and future patterns of a time unit by plural category.
+	relativeTimeUnit struct {
+		past, future map[string]string
+	}
+
+	// localeFormat holds the CLDR data of a language
+	// which are used to format dates, currencies and relative times.
+	localeFormat struct {
+		symbols dateSymbols
+		// the date and time styles, e.g. "date:short" and "time:long".
+		styles map[string][]dateField
+		// the position of the currency symbol, "¤#" or "# ¤".
+		currencyPattern string
+		now             string
+		relative        [len(relativeTimeUnits)]relativeTimeUnit
+	}
+)
+
+// relativeTimeUnits are the units of the relative times, from the largest to the smallest.
+var relativeTimeUnits = [...]struct {
+	name     string
+	duration time.Duration
+}{
+	{"year", 365 * 24 * time.Hour},
+	{"month", 30 * 24 * time.Hour},
+	{"week", 7 * 24 * time.Hour},
+	{"day", 24 * time.Hour},
+	{"hour", time.Hour},
+	{"minute", time.Minute},
+	{"second", time.Second},
+}
+
+// localeFormats holds the formatting data of the supported base languages:
+// English, Greek, German, French and Spanish.
+// The golang.org/x/text module does not provide the CLDR date names, date patterns
+// and relative times, so the rest of the languages fallback to the English ones
+// for dates, times, relative times and the currency symbol's position.
+// Numbers, percentages and currency amounts are formatted based on
+// the golang.org/x/text data of the locale's language on all languages.
+var localeFormats = map[string]*localeFormat{
+	"en": newLocaleFormat(dateSymbols{
+		months:        [12]string{"January", "February", "March", "April", "May", "June", "July", "August", "September", "October", "November", "December"},
+		shortMonths:   [12]string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+		weekdays:      [7]string{"Sunday", "Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday"},
+		shortWeekdays: [7]string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+		am:            "AM",
+		pm:            "PM",
+	}, [8]string{
+		"M/d/yy", "MMM d, y", "MMMM d, y", "EEEE, MMMM d, y",
+		"h:mm a", "h:mm:ss a", "h:mm:ss a z", "h:mm:ss a zzzz",
+	}, "¤#", "now", [len(relativeTimeUnits)][2]string{
+		{"{0} year ago|{0} years ago", "in {0} year|in {0} years"},
+		{"{0} month ago|{0} months ago", "in {0} month|in {0} months"},
+		{"{0} week ago|{0} weeks ago", "in {0} week|in {0} weeks"},
+		{"{0} day ago|{0} days ago", "in {0} day|in {0} days"},
+		{"{0} hour ago|{0} hours ago", "in {0} hour|in {0} hours"},
+		{"{0} minute ago|{0} minutes ago", "in {0} minute|in {0} minutes"},
+		{"{0} second ago|{0} seconds ago", "in {0} second|in {0} seconds"},
+	}),
+	"el": newLocaleFormat(dateSymbols{
+		months:        [12]string{"Ιανουαρίου", "Φεβρουαρίου", "Μαρτίου", "Απριλίου", "Μαΐου", "Ιουνίου", "Ιουλίου", "Αυγούστου", "Σεπτεμβρίου", "Οκτωβρίου", "Νοεμβρίου", "Δεκεμβρίου"},
+		shortMonths:   [12]string{"Ιαν", "Φεβ", "Μαρ", "Απρ", "Μαΐ", "Ιουν", "Ιουλ", "Αυγ", "Σεπ", "Οκτ", "Νοε", "Δεκ"},
+		weekdays:      [7]string{"Κυριακή", "Δευτέρα", "Τρίτη", "Τετάρτη", "Πέμπτη", "Παρασκευή", "Σάββατο"},
+		shortWeekdays: [7]string{"Κυρ", "Δευ", "Τρί", "Τετ", "Πέμ", "Παρ", "Σάβ"},
+		am:            "π.μ.",
+		pm:            "μ.μ.",
+	}, [8]string{
+		"d/M/yy", "d MMM y", "d MMMM y", "EEEE d MMMM y",
+		"h:mm a", "h:mm:ss a", "h:mm:ss a z", "h:mm:ss a zzzz",
+	}, "#\u00a0¤", "τώρα", [len(relativeTimeUnits)][2]string{
+		{"πριν από {0} έτος|πριν από {0} έτη", "σε {0} έτος|σε {0} έτη"},
+		{"πριν από {0} μήνα|πριν από {0} μήνες", "σε {0} μήνα|σε {0} μήνες"},
+		{"πριν από {0} εβδομάδα|πριν από {0} εβδομάδες", "σε {0} εβδομάδα|σε {0} εβδομάδες"},
+		{"πριν από {0} ημέρα|πριν από {0} ημέρες", "σε {0} ημέρα|σε {0} ημέρες"},
+		{"πριν από {0} ώρα|πριν από {0} ώρες", "σε {0} ώρα|σε {0} ώρες"},
+		{"πριν από {0} λεπτό|πριν από {0} λεπτά", "σε {0} λεπτό|σε {0} λεπτά"},
+		{"πριν από {0} δευτερόλεπτο|πριν από {0} δευτερόλεπτα", "σε {0} δευτερόλεπτο|σε {0} δευτερόλεπτα"},
+	}),
+	"de": newLocaleFormat(dateSymbols{
+		months:        [12]string{"Januar", "Februar", "März", "April", "Mai", "Juni", "Juli", "August", "September", "Oktober", "November", "Dezember"},
+		shortMonths:   [12]string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		weekdays:      [7]string{"Sonntag", "Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag"},
+		shortWeekdays: [7]string{"So.", "Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa."},
+		am:            "AM",
+		pm:            "PM",
+	}, [8]string{
+		"dd.MM.yy", "dd.MM.y", "d. MMMM y", "EEEE, d. MMMM y",
+		"HH:mm", "HH:mm:ss", "HH:mm:ss z", "HH:mm:ss zzzz",
+	}, "#\u00a0¤", "jetzt", [len(relativeTimeUnits)][2]string{
+		{"vor {0} Jahr|vor {0} Jahren", "in {0} Jahr|in {0} Jahren"},
+		{"vor {0} Monat|vor {0} Monaten", "in {0} Monat|in {0} Monaten"},
+		{"vor {0} Woche|vor {0} Wochen", "in {0} Woche|in {0} Wochen"},
+		{"vor {0} Tag|vor {0} Tagen", "in {0} Tag|in {0} Tagen"},
+		{"vor {0} Stunde|vor {0} Stunden", "in {0} Stunde|in {0} Stunden"},
+		{"vor {0} Minute|vor {0} Minuten", "in {0} Minute|in {0} Minuten"},
+		{"vor {0} Sekunde|vor {0} Sekunden", "in {0} Sekunde|in {0} Sekunden"},
+	}),
+	"fr": newLocaleFormat(dateSymbols{
+		months:        [12]string{"janvier", "février", "mars", "avril", "mai", "juin", "juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		shortMonths:   [12]string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		weekdays:      [7]string{"dimanche", "lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi"},
+		shortWeekdays: [7]string{"dim.", "lun.", "mar.", "mer.", "jeu.", "ven.", "sam."},
+		am:            "AM",
+		pm:            "PM",
+	}, [8]string{
+		"dd/MM/y", "d MMM y", "d MMMM y", "EEEE d MMMM y",
+		"HH:mm", "HH:mm:ss", "HH:mm:ss z", "HH:mm:ss zzzz",
+	}, "#\u00a0¤", "maintenant", [len(relativeTimeUnits)][2]string{
+		{"il y a {0} an|il y a {0} ans", "dans {0} an|dans {0} ans"},
+		{"il y a {0} mois|il y a {0} mois", "dans {0} mois|dans {0} mois"},
+		{"il y a {0} semaine|il y a {0} semaines", "dans {0} semaine|dans {0} semaines"},
+		{"il y a {0} jour|il y a {0} jours", "dans {0} jour|dans {0} jours"},
+		{"il y a {0} heure|il y a {0} heures", "dans {0} heure|dans {0} heures"},
+		{"il y a {0} minute|il y a {0} minutes", "dans {0} minute|dans {0} minutes"},
+		{"il y a {0} seconde|il y a {0} secondes", "dans {0} seconde|dans {0} secondes"},
+	}),
+	"es": newLocaleFormat(dateSymbols{
+		months:        [12]string{"enero", "febrero", "marzo", "abril", "mayo", "junio", "julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		shortMonths:   [12]string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		weekdays:      [7]string{"domingo", "lunes", "martes", "miércoles", "jueves", "viernes", "sábado"},
+		shortWeekdays: [7]string{"dom", "lun", "mar", "mié", "jue", "vie", "sáb"},
+		am:            "a.\u00a0m.",
+		pm:            "p.\u00a0m.",
+	}, [8]string{
+		"d/M/yy", "d MMM y", "d 'de' MMMM 'de' y", "EEEE, d 'de' MMMM 'de' y",
+		"H:mm", "H:mm:ss", "H:mm:ss z", "H:mm:ss (zzzz)",
+	}, "#\u00a0¤", "ahora", [len(relativeTimeUnits)][2]string{
+		{"hace {0} año|hace {0} años", "dentro de {0} año|dentro de {0} años"},
+		{"hace {0} mes|hace {0} meses", "dentro de {0} mes|dentro de {0} meses"},
+		{"hace {0} semana|hace {0} semanas", "dentro de {0} semana|dentro de {0} semanas"},
+		{"hace {0} día|hace {0} días", "dentro de {0} día|dentro de {0} días"},
+		{"hace {0} hora|hace {0} horas", "dentro de {0} hora|dentro de {0} horas"},
+		{"hace {0} minuto|hace {0} minutos", "dentro de {0} minuto|dentro de {0} minutos"},
+		{"hace {0} segundo|hace {0} segundos", "dentro de {0} segundo|dentro de {0} segundos"},
+	}),
+}
+
+// newLocaleFormat returns a localeFormat of the date symbols,
+// the short, medium, long and full date and then time patterns,
+// and the "one|other" past and future relative time patterns of each unit.
+func newLocaleFormat(sym dateSymbols, patterns [8]string, currencyPattern, now string, relative [len(relativeTimeUnits)][2]string) *localeFormat {
+	f := &localeFormat{
+		symbols:         sym,
+		styles:          make(map[string][]dateField),
+		currencyPattern: currencyPattern,
+		now:             now,
+	}
+
+	for i, style := range []string{ShortStyle, MediumStyle, LongStyle, FullStyle} {
+		for j, typ := range []string{"date", "time"} {
+			key := typ + ":" + style
+			f.styles[key] = mustParseICUDatePattern(patterns[j*4+i])
+		}
+	}
+
+	for i, unit := range relative {
+		past, future := strings.Split(unit[0], "|"), strings.Split(unit[1], "|")
+		f.relative[i] = relativeTimeUnit{
+			past:   map[string]string{"one": past[0], "other": past[1]},
+			future: map[string]string{"one": future[0], "other": future[1]},
+		}
+	}
+
+	return f
+}
+
+// localeFormatOf returns the formatting data of the "tag"'s base language.
+func localeFormatOf(tag language.Tag) *localeFormat {
+	base, _ := tag.Base()
+	if f, ok := localeFormats[base.String()]; ok {
+		return f
+	}
+
+	return localeFormats["en"]
+}
+
+func (loc *Locale) format() *localeFormat {
+	return localeFormatOf(loc.tag)
+}
+
+// FormatNumber returns the "n" number formatted with the locale's decimal and grouping separators,
+// e.g. 1234.5 as "1,234.5" for English and "1.234,5" for Greek.
+// Non-number values are printed as they are.
+func (loc *Locale) FormatNumber(n interface{}) string {
+	v, ok := toICUNumber(n)
+	if !ok {
+		return fmt.Sprint(n)
+	}
+
+	return loc.Printer.Sprint(number.Decimal(v))
+}
+
+// FormatPercent returns the "n" number formatted as a percentage, e.g. 0.25 as "25%".
+func (loc *Locale) FormatPercent(n interface{}) string {
+	v, ok := toICUNumber(n)
+	if !ok {
+		return fmt.Sprint(n)
+	}
+
+	return loc.Printer.Sprint(number.Percent(v))
+}
+
+// FormatCurrency returns the "amount" formatted with the symbol and the digits
+// of the "currencyCode" ISO 4217 currency, e.g. "EUR".
+// An empty "currencyCode" means the currency of the locale's region.
+func (loc *Locale) FormatCurrency(amount interface{}, currencyCode string) string {
+	v, ok := toICUNumber(amount)
+	if !ok {
+		return fmt.Sprint(amount)
+	}
+
+	var (
+		unit currency.Unit
+		err  error
+	)
+
+	if currencyCode == "" {
+		unit, _ = currency.FromTag(loc.tag)
+	} else if unit, err = currency.ParseISO(currencyCode); err != nil {
+		return currencyCode + " " + loc.FormatNumber(v)
+	}
+
+	return loc.formatCurrency(unit, v)
+}
+
+// formatCurrency formats "n" with the symbol and the pattern of the locale.
+// The "options", e.g. of an ICU number skeleton, override the digits of the currency.
+func (loc *Locale) formatCurrency(unit currency.Unit, n float64, options ...number.Option) string {
+	digits, _ := currency.Standard.Rounding(unit)
+	options = append([]number.Option{number.MinFractionDigits(digits), number.MaxFractionDigits(digits)}, options...)
+	amount := loc.Printer.Sprint(number.Decimal(n, options...))
+	symbol := loc.Printer.Sprint(currency.Symbol(unit))
+
+	negative := strings.HasPrefix(amount, "-")
+	if negative {
+		amount = amount[1:]
+	}
+
+	s := strings.Replace(strings.Replace(loc.format().currencyPattern, "#", amount, 1), "¤", symbol, 1)
+	if negative {
+		s = "-" + s
+	}
+
+	return s
+}
+
+// FormatDate returns the date of "t" in the given style: "short", "medium" (the default), "long" or "full",
+// e.g. "3/5/24", "Mar 5, 2024", "March 5, 2024" and "Tuesday, March 5, 2024" for English.
+// The style can be an ICU date pattern too, e.g. "d MMM y".
+func (loc *Locale) FormatDate(t time.Time, style string) string {
+	return loc.formatDate("date", t, style)
+}
+
+// FormatTime returns the time of "t" in the given style: "short", "medium" (the default), "long" or "full",
+// e.g. "2:07 PM" for English and "14:07" for German.
+// The style can be an ICU date pattern too, e.g. "HH:mm".
+func (loc *Locale) FormatTime(t time.Time, style string) string {
+	return loc.formatDate("time", t, style)
+}
+
+func (loc *Locale) formatDate(typ string, t time.Time, style string) string {
+	if style == "" {
+		style = MediumStyle
+	}
+
+	f := loc.format()
+
+	fields, ok := f.styles[typ+":"+style]
+	if !ok {
+		var err error
+		if fields, err = parseICUDatePattern(style); err != nil {
+			return err.Error()
+		}
+	}
+
+	return formatICUDate(fields, t, &f.symbols)
+}
+
+// FormatRelativeTime returns the time between now and "t" in the locale's language,
+// e.g. "3 days ago" or "in 2 hours" for English.
+func (loc *Locale) FormatRelativeTime(t time.Time) string {
+	return loc.formatRelativeTime(t, time.Now())
+}
+
+func (loc *Locale) formatRelativeTime(t, now time.Time) string {
+	f := loc.format()
+
+	d := t.Sub(now)
+	past := d < 0
+	if past {
+		d = -d
+	}
+
+	for i, unit := range relativeTimeUnits {
+		if d < unit.duration {
+			continue
+		}
+
+		n := math.Floor(float64(d) / float64(unit.duration))
+
+		patterns := f.relative[i].future
+		if past {
+			patterns = f.relative[i].past
+		}
+
+		pattern, ok := patterns[icuPluralCategory(loc.tag, n, false)]
+		if !ok {
+			pattern = patterns["other"]
+		}
+
+		return strings.Replace(pattern, "{0}", loc.FormatNumber(n), 1)
+	}
+
+	return f.now
+}
@@ -0,0 +1,489 @@
+package internal
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/message"
+)
+
+// Translation is a translation entry of a gettext PO/MO or a XLIFF file.
+// The Locale keeps the loaded entries in order to export them back, see `WritePO` and `WriteXLIFF`.
+type Translation struct {
+	Context  string // msgctxt.
+	ID       string // msgid, the source text.
+	PluralID string // msgid_plural.
+	// Values holds the translated text (msgstr) or,
+	// for plural entries, the translated plural forms (msgstr[n]).
+	Values []string
+
+	Comments          []string // translator comments (# or XLIFF notes).
+	ExtractedComments []string // #.
+	References        []string // #:
+	Flags             []string // #,
+}
+
+// Key returns the catalog key of the translation entry,
+// which is the "msgid" or "msgctxt.msgid" when it has a context.
+func (t *Translation) Key() string {
+	if t.Context != "" {
+		return t.Context + "." + t.ID
+	}
+
+	return t.ID
+}
+
+// IsPlural reports whether the translation entry has plural forms.
+func (t *Translation) IsPlural() bool {
+	return t.PluralID != ""
+}
+
+// TranslationFile holds the translation entries of a PO, MO or XLIFF file.
+type TranslationFile struct {
+	// Language header, optional.
+	Language string
+	// PluralForms header, e.g. "nplurals=2; plural=(n != 1);".
+	// Defaults to the English plural forms.
+	PluralForms  string
+	Translations []*Translation
+}
+
+// DefaultPluralForms is the plural forms expression
+// of the translation files which do not declare one.
+const DefaultPluralForms = "nplurals=2; plural=(n != 1);"
+
+// StoreTranslations stores the translation entries of a PO, MO or XLIFF file
+// to the locale derives from the given "langIndex".
+func (c *Catalog) StoreTranslations(langIndex int, file *TranslationFile, syntax MessageSyntax) error {
+	loc := c.getLocale(langIndex)
+	if loc == nil {
+		return fmt.Errorf("expected language index to be lower or equal than %d but got %d", len(c.Locales), langIndex)
+	}
+	return loc.LoadTranslations(c, file, syntax)
+}
+
+// LoadTranslations sets the translation messages based on the entries of a PO, MO or XLIFF file.
+// Untranslated entries are skipped.
+func (loc *Locale) LoadTranslations(c *Catalog, file *TranslationFile, syntax MessageSyntax) error {
+	pluralForms := file.PluralForms
+	if pluralForms == "" {
+		pluralForms = DefaultPluralForms
+	}
+
+	nplurals, pluralFunc, err := ParsePluralForms(pluralForms)
+	if err != nil {
+		return fmt.Errorf("%s: %w", loc.ID, err)
+	}
+
+	if loc.Translations == nil {
+		loc.Translations = make(map[string]*Translation)
+	}
+	loc.PluralForms = pluralForms
+
+	for _, t := range file.Translations {
+		if !isTranslated(t) {
+			continue
+		}
+
+		key := t.Key()
+
+		if !t.IsPlural() {
+			r, err := loc.newRenderer(c, key, t.Values[0], syntax)
+			if err != nil {
+				return fmt.Errorf("%s:%s parse translation: %w", loc.ID, key, err)
+			}
+
+			loc.Messages[key] = r
+			loc.Translations[key] = t
+			continue
+		}
+
+		if len(t.Values) != nplurals {
+			return fmt.Errorf("%s:%s expected %d plural forms but got %d", loc.ID, key, nplurals, len(t.Values))
+		}
+
+		p := &gettextPlural{
+			key:    key,
+			plural: pluralFunc,
+			forms:  make([]Renderer, 0, len(t.Values)),
+		}
+
+		for _, value := range t.Values {
+			r, err := loc.newRenderer(c, key, value, syntax)
+			if err != nil {
+				return fmt.Errorf("%s:%s parse plural translation: %w", loc.ID, key, err)
+			}
+
+			p.forms = append(p.forms, r)
+		}
+
+		loc.Messages[key] = p
+		loc.Translations[key] = t
+	}
+
+	return nil
+}
+
+func isTranslated(t *Translation) bool {
+	if t.ID == "" || len(t.Values) == 0 { // header or empty.
+		return false
+	}
+
+	for _, flag := range t.Flags {
+		if flag == "fuzzy" {
+			return false
+		}
+	}
+
+	for _, value := range t.Values {
+		if value == "" {
+			return false
+		}
+	}
+
+	return true
+}
+
+// newRenderer returns a Renderer of a single translation text.
+func (loc *Locale) newRenderer(c *Catalog, key, value string, syntax MessageSyntax) (Renderer, error) {
+	if syntax == ICUSyntax {
+		return NewICUMessage(loc, key, value)
+	}
+
+	if stringIsTemplateValue(value, loc.Options.Left, loc.Options.Right) {
+		return NewTemplate(c, &Message{Locale: loc, Key: key, Value: value})
+	}
+
+	return &printfRenderer{value: value, printer: loc.Printer}, nil
+}
+
+// printfRenderer renders gettext messages, which use the printf verbs, e.g. "%d files".
+type printfRenderer struct {
+	value   string
+	printer *message.Printer
+}
+
+func (r *printfRenderer) Render(args ...interface{}) (string, error) {
+	if len(args) == 0 {
+		return r.value, nil
+	}
+
+	return r.printer.Sprintf(r.value, args...), nil
+}
+
+// gettextPlural renders the plural form that the Plural-Forms expression
+// of the translation file resolves, the first argument is the plural count.
+type gettextPlural struct {
+	key    string
+	plural func(n int) int
+	forms  []Renderer
+}
+
+func (p *gettextPlural) Render(args ...interface{}) (string, error) {
+	if len(args) > 0 {
+		if pluralCount, ok := findPluralCount(args[0]); ok {
+			idx := p.plural(pluralCount)
+			if idx < 0 || idx >= len(p.forms) {
+				idx = len(p.forms) - 1
+			}
+
+			return p.forms[idx].Render(args...)
+		}
+	}
+
+	return "", fmt.Errorf("key: %q: missing plural count argument", p.key)
+}
+
+// translations returns the translation entries of the locale, sorted by key.
+// The entries that were not loaded from a translation file are created from their messages,
+// plural messages of the template syntax are skipped.
+func (loc *Locale) translations() []*Translation {
+	keys := make([]string, 0, len(loc.Messages))
+	for key := range loc.Messages {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	translations := make([]*Translation, 0, len(keys))
+	for _, key := range keys {
+		if t, ok := loc.Translations[key]; ok {
+			translations = append(translations, t)
+			continue
+		}
+
+		if value, ok := rendererValue(loc.Messages[key]); ok {
+			translations = append(translations, &Translation{ID: key, Values: []string{value}})
+		}
+	}
+
+	return translations
+}
+
+func rendererValue(r Renderer) (string, bool) {
+	switch m := r.(type) {
+	case *Message:
+		return m.Value, !m.Plural
+	case *Template:
+		return m.Value, !m.Plural
+	case *ICUMessage:
+		return m.Value, true
+	case *printfRenderer:
+		return m.value, true
+	default:
+		return "", false
+	}
+}
+
+// ParsePluralForms parses a gettext Plural-Forms header,
+// e.g. "nplurals=3; plural=(n==1 ? 0 : n%10>=2 && n%10<=4 && (n%100<10 || n%100>=20) ? 1 : 2);"
+// and returns the number of the plural forms and a function which
+// returns the plural form's index of a number.
+func ParsePluralForms(header string) (int, func(n int) int, error) {
+	var (
+		nplurals = -1
+		expr     string
+	)
+
+	for _, part := range strings.Split(header, ";") {
+		name, value, ok := strings.Cut(part, "=")
+		if !ok {
+			continue
+		}
+
+		switch strings.TrimSpace(name) {
+		case "nplurals":
+			n, err := strconv.Atoi(strings.TrimSpace(value))
+			if err != nil || n < 1 {
+				return 0, nil, fmt.Errorf("plural forms: invalid nplurals: %q", value)
+			}
+			nplurals = n
+		case "plural":
+			expr = strings.TrimSpace(value)
+		}
+	}
+
+	if nplurals == -1 || expr == "" {
+		return 0, nil, fmt.Errorf("plural forms: missing nplurals or plural: %q", header)
+	}
+
+	p := &pluralExprParser{src: expr}
+	eval, err := p.parseTernary()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	if p.skipSpace(); p.pos < len(p.src) {
+		return 0, nil, p.errorf("unexpected %q", p.src[p.pos:])
+	}
+
+	return nplurals, eval, nil
+}
+
+// pluralExprParser parses the C expression of a Plural-Forms header.
+type pluralExprParser struct {
+	src string
+	pos int
+}
+
+type pluralExpr = func(n int) int
+
+func (p *pluralExprParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("plural forms: %q: position %d: %s", p.src, p.pos, fmt.Sprintf(format, args...))
+}
+
+func (p *pluralExprParser) skipSpace() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t') {
+		p.pos++
+	}
+}
+
+// consume reports whether the next token is "op" and moves forward.
+func (p *pluralExprParser) consume(op string) bool {
+	p.skipSpace()
+	if !strings.HasPrefix(p.src[p.pos:], op) {
+		return false
+	}
+
+	// do not match a prefix of a two-characters operator, e.g. "<" of "<=" or "!" of "!=".
+	if len(op) == 1 && p.pos+1 < len(p.src) && p.src[p.pos+1] == '=' && op != "=" && strings.ContainsAny(op, "<>!") {
+		return false
+	}
+
+	p.pos += len(op)
+	return true
+}
+
+func (p *pluralExprParser) parseTernary() (pluralExpr, error) {
+	cond, err := p.parseBinary(0)
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.consume("?") {
+		return cond, nil
+	}
+
+	then, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+
+	if !p.consume(":") {
+		return nil, p.errorf("expected ':'")
+	}
+
+	otherwise, err := p.parseTernary()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(n int) int {
+		if cond(n) != 0 {
+			return then(n)
+		}
+		return otherwise(n)
+	}, nil
+}
+
+// The binary operators by precedence, lowest first.
+var pluralExprOperators = [][]string{
+	{"||"},
+	{"&&"},
+	{"==", "!="},
+	{"<=", ">=", "<", ">"},
+	{"+", "-"},
+	{"*", "/", "%"},
+}
+
+func (p *pluralExprParser) parseBinary(level int) (pluralExpr, error) {
+	if level == len(pluralExprOperators) {
+		return p.parseUnary()
+	}
+
+	left, err := p.parseBinary(level + 1)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		op := ""
+		for _, candidate := range pluralExprOperators[level] {
+			if p.consume(candidate) {
+				op = candidate
+				break
+			}
+		}
+
+		if op == "" {
+			return left, nil
+		}
+
+		right, err := p.parseBinary(level + 1)
+		if err != nil {
+			return nil, err
+		}
+
+		left = pluralBinaryExpr(op, left, right)
+	}
+}
+
+func pluralBinaryExpr(op string, left, right pluralExpr) pluralExpr {
+	boolToInt := func(b bool) int {
+		if b {
+			return 1
+		}
+		return 0
+	}
+
+	return func(n int) int {
+		a := left(n)
+
+		switch op {
+		case "||":
+			return boolToInt(a != 0 || right(n) != 0)
+		case "&&":
+			return boolToInt(a != 0 && right(n) != 0)
+		}
+
+		b := right(n)
+		switch op {
+		case "==":
+			return boolToInt(a == b)
+		case "!=":
+			return boolToInt(a != b)
+		case "<":
+			return boolToInt(a < b)
+		case ">":
+			return boolToInt(a > b)
+		case "<=":
+			return boolToInt(a <= b)
+		case ">=":
+			return boolToInt(a >= b)
+		case "+":
+			return a + b
+		case "-":
+			return a - b
+		case "*":
+			return a * b
+		case "/", "%":
+			if b == 0 {
+				return 0
+			}
+
+			if op == "/" {
+				return a / b
+			}
+			return a % b
+		default:
+			return 0
+		}
+	}
+}
+
+func (p *pluralExprParser) parseUnary() (pluralExpr, error) {
+	if p.consume("!") {
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		return func(n int) int {
+			if operand(n) == 0 {
+				return 1
+			}
+			return 0
+		}, nil
+	}
+
+	if p.consume("(") {
+		expr, err := p.parseTernary()
+		if err != nil {
+			return nil, err
+		}
+
+		if !p.consume(")") {
+			return nil, p.errorf("expected ')'")
+		}
+
+		return expr, nil
+	}
+
+	if p.consume("n") {
+		return func(n int) int { return n }, nil
+	}
+
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.src) && isDigit(p.src[p.pos]) {
+		p.pos++
+	}
+
+	if start == p.pos {
+		return nil, p.errorf("expected a number, 'n' or '('")
+	}
+
+	value, _ := strconv.Atoi(p.src[start:p.pos])
+	return func(int) int { return value }, nil
+}
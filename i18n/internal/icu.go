@@ -0,0 +1,1073 @@
+package internal
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/text/currency"
+	"golang.org/x/text/feature/plural"
+	"golang.org/x/text/language"
+	"golang.org/x/text/number"
+)
+
+// MessageSyntax is the syntax of the translation messages of a locale file.
+// See `Options.Syntax` and `Options.SyntaxFunc`.
+type MessageSyntax uint8
+
+const (
+	// TemplateSyntax is the default message syntax:
+	// Go templates, ${Vars} and plural forms as keys.
+	TemplateSyntax MessageSyntax = iota
+	// ICUSyntax is the ICU MessageFormat syntax, the one most of the frontend libraries use, e.g.
+	// "You have {count, plural, =0 {no messages} one {# message} other {# messages}}".
+	//
+	// Supported arguments: {name}, {name, number[, integer|percent|currency|::skeleton]},
+	// {name, date|time[, short|medium|long|full|pattern|::skeleton]},
+	// {name, plural|selectordinal, [offset:n] =n {...} zero|one|two|few|many {...} other {...}}
+	// and {name, select, case {...} other {...}}.
+	//
+	// Number skeletons support the "percent", "currency/CODE", "precision-integer",
+	// ".00" (fraction digits), "scale/n" and "group-off" stems.
+	// Date and time styles and names follow the locale, see `Locale.FormatDate`,
+	// skeletons are converted to patterns of the en locale's layout.
+	//
+	// The arguments are resolved by name from a map or a struct passed as the first
+	// translation argument or by position, e.g. {0} for the first translation argument.
+	ICUSyntax
+)
+
+// ICUMessage is the Renderer for translation messages
+// written in the ICU MessageFormat syntax, see `ICUSyntax`.
+type ICUMessage struct {
+	Locale *Locale
+
+	Key   string
+	Value string
+
+	nodes icuNodes
+}
+
+// NewICUMessage parses the "value" ICU message of the "key"
+// and returns a new ICUMessage, ready to be rendered.
+func NewICUMessage(loc *Locale, key, value string) (*ICUMessage, error) {
+	nodes, err := parseICU(value)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &ICUMessage{
+		Locale: loc,
+		Key:    key,
+		Value:  value,
+		nodes:  nodes,
+	}
+
+	return m, nil
+}
+
+// Render completes the Renderer interface.
+// It accepts the arguments of the message, named (a map or a struct as the first argument)
+// or positional ones.
+func (m *ICUMessage) Render(args ...interface{}) (string, error) {
+	s := &icuState{loc: m.Locale, args: args}
+
+	var b strings.Builder
+	if err := m.nodes.format(&b, s, nil); err != nil {
+		return "", fmt.Errorf("key: %q: %w", m.Key, err)
+	}
+
+	return b.String(), nil
+}
+
+// LoadICU sets the ICU translation messages based on the key values,
+// nested maps are flatten to dot-separated keys.
+func (loc *Locale) LoadICU(keyValues Map) error {
+	return loc.setICUMap("", keyValues)
+}
+
+func (loc *Locale) setICUMap(key string, keyValues Map) error {
+	for k, v := range keyValues {
+		if key != "" {
+			k = key + "." + k
+		}
+
+		switch value := v.(type) {
+		case string:
+			m, err := NewICUMessage(loc, k, value)
+			if err != nil {
+				return fmt.Errorf("%s:%s parse icu message: %w", loc.ID, k, err)
+			}
+
+			loc.Messages[k] = m
+		case Map:
+			if err := loc.setICUMap(k, value); err != nil {
+				return err
+			}
+		default:
+			return fmt.Errorf("%s:%s unexpected type of %T as value", loc.ID, k, value)
+		}
+	}
+
+	return nil
+}
+
+type (
+	icuNode interface {
+		// pound is the plural value of the closest plural argument, nil outside of a plural.
+		format(b *strings.Builder, s *icuState, pound *float64) error
+	}
+
+	icuNodes []icuNode
+
+	icuText string
+
+	// icuPound is the # inside a plural message.
+	icuPound struct{}
+
+	icuArg struct {
+		name string
+	}
+
+	icuNumberArg struct {
+		name         string
+		numberFormat icuNumberFormat
+	}
+
+	icuDateArg struct {
+		name string
+		// style is the locale's date or time style, e.g. "date:short",
+		// or empty when the fields of a custom pattern or skeleton are set instead.
+		style  string
+		fields []dateField
+	}
+
+	icuPluralArg struct {
+		name    string
+		offset  float64
+		ordinal bool
+		cases   []icuCase
+	}
+
+	icuSelectArg struct {
+		name  string
+		cases []icuCase
+	}
+
+	icuCase struct {
+		selector string
+		exact    *float64 // =n selectors.
+		nodes    icuNodes
+	}
+)
+
+type icuState struct {
+	loc  *Locale
+	args []interface{}
+}
+
+// arg returns the value of the "name" argument,
+// a field of a map or a struct argument or a positional one.
+func (s *icuState) arg(name string) (interface{}, error) {
+	if len(s.args) > 0 {
+		if v, ok := lookupICUArg(s.args[0], name); ok {
+			return v, nil
+		}
+
+		if idx, err := strconv.Atoi(name); err == nil && idx >= 0 && idx < len(s.args) {
+			return s.args[idx], nil
+		}
+	}
+
+	return nil, fmt.Errorf("missing argument: %q", name)
+}
+
+func lookupICUArg(data interface{}, name string) (interface{}, bool) {
+	switch values := data.(type) {
+	case Map:
+		v, ok := values[name]
+		return v, ok
+	case map[string]string:
+		v, ok := values[name]
+		return v, ok
+	case map[string]int:
+		v, ok := values[name]
+		return v, ok
+	}
+
+	rv := reflect.Indirect(reflect.ValueOf(data))
+	switch rv.Kind() {
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			return nil, false
+		}
+
+		v := rv.MapIndex(reflect.ValueOf(name).Convert(rv.Type().Key()))
+		if !v.IsValid() {
+			return nil, false
+		}
+
+		return v.Interface(), true
+	case reflect.Struct:
+		f := rv.FieldByName(name)
+		if !f.IsValid() || !f.CanInterface() {
+			return nil, false
+		}
+
+		return f.Interface(), true
+	default:
+		return nil, false
+	}
+}
+
+func (nodes icuNodes) format(b *strings.Builder, s *icuState, pound *float64) error {
+	for _, n := range nodes {
+		if err := n.format(b, s, pound); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (t icuText) format(b *strings.Builder, _ *icuState, _ *float64) error {
+	b.WriteString(string(t))
+	return nil
+}
+
+func (icuPound) format(b *strings.Builder, s *icuState, pound *float64) error {
+	b.WriteString(icuNumberFormat{}.format(s.loc, *pound))
+	return nil
+}
+
+func (a icuArg) format(b *strings.Builder, s *icuState, _ *float64) error {
+	v, err := s.arg(a.name)
+	if err != nil {
+		return err
+	}
+
+	switch value := v.(type) {
+	case string:
+		b.WriteString(value)
+	case time.Time:
+		b.WriteString(s.loc.FormatDate(value, ShortStyle) + ", " + s.loc.FormatTime(value, ShortStyle))
+	default:
+		if n, ok := toICUNumber(v); ok {
+			b.WriteString(icuNumberFormat{}.format(s.loc, n))
+			return nil
+		}
+
+		b.WriteString(fmt.Sprint(v))
+	}
+
+	return nil
+}
+
+func (a icuNumberArg) format(b *strings.Builder, s *icuState, _ *float64) error {
+	v, err := s.arg(a.name)
+	if err != nil {
+		return err
+	}
+
+	n, ok := toICUNumber(v)
+	if !ok {
+		return fmt.Errorf("argument: %q: expected a number but got %T", a.name, v)
+	}
+
+	b.WriteString(a.numberFormat.format(s.loc, n))
+	return nil
+}
+
+func (a icuDateArg) format(b *strings.Builder, s *icuState, _ *float64) error {
+	v, err := s.arg(a.name)
+	if err != nil {
+		return err
+	}
+
+	var t time.Time
+	switch value := v.(type) {
+	case time.Time:
+		t = value
+	case *time.Time:
+		t = *value
+	default:
+		return fmt.Errorf("argument: %q: expected a time.Time but got %T", a.name, v)
+	}
+
+	f := s.loc.format()
+
+	fields := a.fields
+	if a.style != "" {
+		fields = f.styles[a.style]
+	}
+
+	b.WriteString(formatICUDate(fields, t, &f.symbols))
+	return nil
+}
+
+func (a icuPluralArg) format(b *strings.Builder, s *icuState, _ *float64) error {
+	v, err := s.arg(a.name)
+	if err != nil {
+		return err
+	}
+
+	n, ok := toICUNumber(v)
+	if !ok {
+		return fmt.Errorf("argument: %q: expected a number but got %T", a.name, v)
+	}
+
+	// exact matches are checked against the value before the offset.
+	for _, c := range a.cases {
+		if c.exact != nil && *c.exact == n {
+			return c.nodes.format(b, s, &n)
+		}
+	}
+
+	n -= a.offset
+	category := icuPluralCategory(*s.loc.Tag(), n, a.ordinal)
+
+	var other *icuCase
+	for i, c := range a.cases {
+		if c.selector == category {
+			return c.nodes.format(b, s, &n)
+		}
+
+		if c.selector == "other" {
+			other = &a.cases[i]
+		}
+	}
+
+	return other.nodes.format(b, s, &n)
+}
+
+func (a icuSelectArg) format(b *strings.Builder, s *icuState, pound *float64) error {
+	v, err := s.arg(a.name)
+	if err != nil {
+		return err
+	}
+
+	value := fmt.Sprint(v)
+
+	var other *icuCase
+	for i, c := range a.cases {
+		if c.selector == value {
+			return c.nodes.format(b, s, pound)
+		}
+
+		if c.selector == "other" {
+			other = &a.cases[i]
+		}
+	}
+
+	return other.nodes.format(b, s, pound)
+}
+
+func toICUNumber(v interface{}) (float64, bool) {
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	case reflect.String: // e.g. json.Number and map[string]string values.
+		n, err := strconv.ParseFloat(rv.String(), 64)
+		return n, err == nil
+	default:
+		return 0, false
+	}
+}
+
+func icuPluralCategory(tag language.Tag, n float64, ordinal bool) string {
+	rules := plural.Cardinal
+	if ordinal {
+		rules = plural.Ordinal
+	}
+
+	// The CLDR plural operands.
+	var i, v, w, f, t int
+	intPart, fracPart, _ := strings.Cut(strconv.FormatFloat(math.Abs(n), 'f', -1, 64), ".")
+	i, _ = strconv.Atoi(intPart)
+	if fracPart != "" {
+		v, w = len(fracPart), len(fracPart)
+		f, _ = strconv.Atoi(fracPart)
+		t = f
+	}
+
+	switch rules.MatchPlural(tag, i, v, w, f, t) {
+	case plural.Zero:
+		return "zero"
+	case plural.One:
+		return "one"
+	case plural.Two:
+		return "two"
+	case plural.Few:
+		return "few"
+	case plural.Many:
+		return "many"
+	default:
+		return "other"
+	}
+}
+
+const (
+	icuDecimal uint8 = iota
+	icuPercent
+	icuCurrency
+)
+
+type icuNumberFormat struct {
+	style    uint8
+	currency *currency.Unit // nil for the locale's currency.
+	scale    float64
+	// the percent skeleton stem does not multiply by 100.
+	percentSkeleton bool
+	options         []number.Option
+}
+
+func parseICUNumberStyle(style string) (f icuNumberFormat, err error) {
+	switch style {
+	case "":
+		return
+	case "integer":
+		f.options = append(f.options, number.MaxFractionDigits(0))
+		return
+	case "percent":
+		f.style = icuPercent
+		return
+	case "currency":
+		f.style = icuCurrency
+		return
+	}
+
+	skeleton, ok := strings.CutPrefix(style, "::")
+	if !ok {
+		return f, fmt.Errorf("unknown number style: %q", style)
+	}
+
+	for _, stem := range strings.Fields(skeleton) {
+		switch {
+		case stem == "percent" || stem == "%":
+			f.style = icuPercent
+			f.percentSkeleton = true
+		case stem == "currency":
+			f.style = icuCurrency
+		case strings.HasPrefix(stem, "currency/"):
+			unit, err := currency.ParseISO(strings.TrimPrefix(stem, "currency/"))
+			if err != nil {
+				return f, fmt.Errorf("number skeleton: %q: %w", stem, err)
+			}
+
+			f.style = icuCurrency
+			f.currency = &unit
+		case stem == "precision-integer" || stem == "integer":
+			f.options = append(f.options, number.MinFractionDigits(0), number.MaxFractionDigits(0))
+		case strings.HasPrefix(stem, "."):
+			digits := stem[1:]
+			minDigits := strings.Count(digits, "0")
+			if strings.Trim(digits, "0#") != "" || strings.Contains(strings.TrimLeft(digits, "0"), "0") {
+				return f, fmt.Errorf("number skeleton: invalid precision: %q", stem)
+			}
+
+			f.options = append(f.options, number.MinFractionDigits(minDigits), number.MaxFractionDigits(len(digits)))
+		case strings.HasPrefix(stem, "scale/"):
+			scale, err := strconv.ParseFloat(strings.TrimPrefix(stem, "scale/"), 64)
+			if err != nil {
+				return f, fmt.Errorf("number skeleton: %q: %w", stem, err)
+			}
+
+			f.scale = scale
+		case stem == "group-off" || stem == ",_":
+			f.options = append(f.options, number.NoSeparator())
+		default:
+			return f, fmt.Errorf("number skeleton: unknown stem: %q", stem)
+		}
+	}
+
+	return
+}
+
+func (f icuNumberFormat) format(loc *Locale, n float64) string {
+	if f.scale != 0 {
+		n *= f.scale
+	}
+
+	if f.percentSkeleton {
+		n /= 100
+	}
+
+	switch f.style {
+	case icuPercent:
+		return loc.Printer.Sprint(number.Percent(n, f.options...))
+	case icuCurrency:
+		unit := f.currency
+		if unit == nil {
+			u, _ := currency.FromTag(loc.tag)
+			unit = &u
+		}
+
+		return loc.formatCurrency(*unit, n, f.options...)
+	default:
+		return loc.Printer.Sprint(number.Decimal(n, f.options...))
+	}
+}
+
+func formatICUDate(fields []dateField, t time.Time, sym *dateSymbols) string {
+	var b strings.Builder
+	for _, field := range fields {
+		b.WriteString(field(t, sym))
+	}
+
+	return b.String()
+}
+
+// parseICUDateStyle returns the locale's style key, e.g. "date:short",
+// or the fields of a custom pattern or skeleton.
+func parseICUDateStyle(typ, style string) (string, []dateField, error) {
+	switch style {
+	case "":
+		return typ + ":" + MediumStyle, nil, nil
+	case ShortStyle, MediumStyle, LongStyle, FullStyle:
+		return typ + ":" + style, nil, nil
+	}
+
+	if skeleton, ok := strings.CutPrefix(style, "::"); ok {
+		style = icuDateSkeletonPattern(skeleton)
+	}
+
+	fields, err := parseICUDatePattern(style)
+	return "", fields, err
+}
+
+func mustParseICUDatePattern(pattern string) []dateField {
+	fields, err := parseICUDatePattern(pattern)
+	if err != nil {
+		panic(err)
+	}
+
+	return fields
+}
+
+// parseICUDatePattern converts an ICU date pattern, e.g. "yyyy-MM-dd 'at' HH:mm",
+// to a list of functions, each one formats a part of the pattern.
+func parseICUDatePattern(pattern string) ([]dateField, error) {
+	var fields []dateField
+
+	literal := func(s string) {
+		fields = append(fields, func(time.Time, *dateSymbols) string { return s })
+	}
+
+	for i := 0; i < len(pattern); {
+		ch := pattern[i]
+
+		switch {
+		case ch == '\'':
+			// quoted text, '' is a single quote.
+			end := i + 1
+			var text strings.Builder
+			for end < len(pattern) {
+				if pattern[end] == '\'' {
+					if end+1 < len(pattern) && pattern[end+1] == '\'' {
+						text.WriteByte('\'')
+						end += 2
+						continue
+					}
+					break
+				}
+				text.WriteByte(pattern[end])
+				end++
+			}
+
+			if end == i+1 && end < len(pattern) {
+				text.WriteByte('\'') // ''
+			}
+
+			literal(text.String())
+			i = end + 1
+		case 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z':
+			count := 1
+			for i+count < len(pattern) && pattern[i+count] == ch {
+				count++
+			}
+
+			field, err := icuDateField(ch, count)
+			if err != nil {
+				return nil, err
+			}
+
+			fields = append(fields, field)
+			i += count
+		default:
+			end := i + 1
+			for end < len(pattern) && !isICUDatePatternSpecial(pattern[end]) {
+				end++
+			}
+
+			literal(pattern[i:end])
+			i = end
+		}
+	}
+
+	return fields, nil
+}
+
+func isICUDatePatternSpecial(ch byte) bool {
+	return ch == '\'' || 'a' <= ch && ch <= 'z' || 'A' <= ch && ch <= 'Z'
+}
+
+func icuDateField(ch byte, count int) (dateField, error) {
+	layout := ""
+
+	switch ch {
+	case 'y':
+		layout = "2006"
+		if count == 2 {
+			layout = "06"
+		}
+	case 'M', 'L':
+		switch count {
+		case 1:
+			layout = "1"
+		case 2:
+			layout = "01"
+		case 3:
+			return func(t time.Time, sym *dateSymbols) string { return sym.shortMonths[t.Month()-1] }, nil
+		default:
+			return func(t time.Time, sym *dateSymbols) string { return sym.months[t.Month()-1] }, nil
+		}
+	case 'd':
+		layout = "2"
+		if count > 1 {
+			layout = "02"
+		}
+	case 'E':
+		if count > 3 {
+			return func(t time.Time, sym *dateSymbols) string { return sym.weekdays[t.Weekday()] }, nil
+		}
+
+		return func(t time.Time, sym *dateSymbols) string { return sym.shortWeekdays[t.Weekday()] }, nil
+	case 'a':
+		return func(t time.Time, sym *dateSymbols) string {
+			if t.Hour() < 12 {
+				return sym.am
+			}
+
+			return sym.pm
+		}, nil
+	case 'h':
+		layout = "3"
+		if count > 1 {
+			layout = "03"
+		}
+	case 'H':
+		if count == 1 {
+			return func(t time.Time, _ *dateSymbols) string { return strconv.Itoa(t.Hour()) }, nil
+		}
+		layout = "15"
+	case 'm':
+		layout = "4"
+		if count > 1 {
+			layout = "04"
+		}
+	case 's':
+		layout = "5"
+		if count > 1 {
+			layout = "05"
+		}
+	case 'S':
+		if count > 9 {
+			count = 9
+		}
+
+		div := int(math.Pow10(9 - count))
+		return func(t time.Time, _ *dateSymbols) string { return fmt.Sprintf("%0*d", count, t.Nanosecond()/div) }, nil
+	case 'D':
+		return func(t time.Time, _ *dateSymbols) string { return strconv.Itoa(t.YearDay()) }, nil
+	case 'z':
+		layout = "MST"
+	case 'Z', 'x', 'X':
+		layout = "-0700"
+	default:
+		return nil, fmt.Errorf("date pattern: unsupported field: %q", strings.Repeat(string(ch), count))
+	}
+
+	return func(t time.Time, _ *dateSymbols) string { return t.Format(layout) }, nil
+}
+
+// icuDateSkeletonPattern converts a date skeleton, e.g. "yMMMd" or "EEEEjms",
+// to a date pattern of the en locale.
+func icuDateSkeletonPattern(skeleton string) string {
+	counts := make(map[byte]int)
+	for i := 0; i < len(skeleton); i++ {
+		counts[skeleton[i]]++
+	}
+
+	repeat := func(ch byte) string {
+		return strings.Repeat(string(ch), counts[ch])
+	}
+
+	var date string
+	if month := counts['M'] + counts['L']; month >= 3 {
+		date = strings.Repeat("M", month)
+		if counts['d'] > 0 {
+			date += " " + repeat('d')
+		}
+		if counts['y'] > 0 {
+			if counts['d'] > 0 {
+				date += ","
+			}
+			date += " " + repeat('y')
+		}
+	} else {
+		var parts []string
+		if month > 0 {
+			parts = append(parts, strings.Repeat("M", month))
+		}
+		if counts['d'] > 0 {
+			parts = append(parts, repeat('d'))
+		}
+		if counts['y'] > 0 {
+			parts = append(parts, repeat('y'))
+		}
+		date = strings.Join(parts, "/")
+	}
+
+	if counts['E'] > 0 {
+		if date != "" {
+			date = ", " + date
+		}
+		date = repeat('E') + date
+	}
+
+	var timeParts []string
+	hour := byte('h')
+	if counts['H'] > 0 {
+		hour = 'H'
+	}
+	if hours := counts['h'] + counts['H'] + counts['j']; hours > 0 {
+		timeParts = append(timeParts, strings.Repeat(string(hour), hours))
+	}
+	if counts['m'] > 0 {
+		timeParts = append(timeParts, "mm")
+	}
+	if counts['s'] > 0 {
+		timeParts = append(timeParts, "ss")
+	}
+
+	t := strings.Join(timeParts, ":")
+	if t != "" && hour == 'h' {
+		t += " a"
+	}
+	if counts['z'] > 0 {
+		t += " z"
+	}
+
+	t = strings.TrimSpace(t)
+	if date != "" && t != "" {
+		return date + ", " + t
+	}
+
+	return date + t
+}
+
+// parseICU parses an ICU MessageFormat message.
+func parseICU(src string) (icuNodes, error) {
+	p := &icuParser{src: src}
+	return p.parseMessage(false, false)
+}
+
+type icuParser struct {
+	src string
+	pos int
+}
+
+func (p *icuParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("icu: position %d: %s", p.pos, fmt.Sprintf(format, args...))
+}
+
+// parseMessage parses a message until the end of the source
+// or, for nested ones, until the closing brace which is not consumed.
+func (p *icuParser) parseMessage(nested, inPlural bool) (icuNodes, error) {
+	var (
+		nodes icuNodes
+		text  strings.Builder
+	)
+
+	flush := func() {
+		if text.Len() > 0 {
+			nodes = append(nodes, icuText(text.String()))
+			text.Reset()
+		}
+	}
+
+	for p.pos < len(p.src) {
+		switch ch := p.src[p.pos]; {
+		case ch == '\'':
+			p.readQuoted(&text, inPlural)
+		case ch == '{':
+			flush()
+			node, err := p.parseArgument(inPlural)
+			if err != nil {
+				return nil, err
+			}
+			nodes = append(nodes, node)
+		case ch == '}':
+			if !nested {
+				return nil, p.errorf("unexpected '}'")
+			}
+
+			flush()
+			return nodes, nil
+		case ch == '#' && inPlural:
+			flush()
+			nodes = append(nodes, icuPound{})
+			p.pos++
+		default:
+			text.WriteByte(ch)
+			p.pos++
+		}
+	}
+
+	if nested {
+		return nil, p.errorf("expected '}'")
+	}
+
+	flush()
+	return nodes, nil
+}
+
+// readQuoted reads an apostrophe: ” is a single quote and
+// a quote before a syntax character starts a quoted literal text.
+func (p *icuParser) readQuoted(text *strings.Builder, inPlural bool) {
+	p.pos++
+
+	if p.pos < len(p.src) && p.src[p.pos] == '\'' {
+		text.WriteByte('\'')
+		p.pos++
+		return
+	}
+
+	if p.pos == len(p.src) || !isICUSyntaxChar(p.src[p.pos], inPlural) {
+		text.WriteByte('\'')
+		return
+	}
+
+	for p.pos < len(p.src) {
+		ch := p.src[p.pos]
+		p.pos++
+
+		if ch == '\'' {
+			if p.pos < len(p.src) && p.src[p.pos] == '\'' {
+				text.WriteByte('\'')
+				p.pos++
+				continue
+			}
+
+			return
+		}
+
+		text.WriteByte(ch)
+	}
+}
+
+func isICUSyntaxChar(ch byte, inPlural bool) bool {
+	return ch == '{' || ch == '}' || ch == '|' || (ch == '#' && inPlural)
+}
+
+func (p *icuParser) skipSpace() {
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case ' ', '\t', '\n', '\r':
+			p.pos++
+		default:
+			return
+		}
+	}
+}
+
+func (p *icuParser) consume(ch byte) bool {
+	p.skipSpace()
+	if p.pos < len(p.src) && p.src[p.pos] == ch {
+		p.pos++
+		return true
+	}
+
+	return false
+}
+
+func (p *icuParser) expect(ch byte) error {
+	if !p.consume(ch) {
+		return p.errorf("expected '%c'", ch)
+	}
+
+	return nil
+}
+
+// readWord reads an argument name, type or case selector.
+func (p *icuParser) readWord() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case ' ', '\t', '\n', '\r', '{', '}', ',', '\'', '#':
+			return p.src[start:p.pos]
+		}
+		p.pos++
+	}
+
+	return p.src[start:p.pos]
+}
+
+// readStyle reads the style of a number, date or time argument until the closing brace.
+func (p *icuParser) readStyle() string {
+	start, quoted := p.pos, false
+	for p.pos < len(p.src) {
+		switch p.src[p.pos] {
+		case '\'':
+			quoted = !quoted
+		case '}':
+			if !quoted {
+				return strings.TrimSpace(p.src[start:p.pos])
+			}
+		}
+		p.pos++
+	}
+
+	return strings.TrimSpace(p.src[start:p.pos])
+}
+
+func (p *icuParser) parseArgument(inPlural bool) (icuNode, error) {
+	p.pos++ // {
+
+	name := p.readWord()
+	if name == "" {
+		return nil, p.errorf("expected argument name")
+	}
+
+	if p.consume('}') {
+		return icuArg{name: name}, nil
+	}
+
+	if err := p.expect(','); err != nil {
+		return nil, err
+	}
+
+	switch typ := p.readWord(); typ {
+	case "number", "date", "time":
+		style := ""
+		if p.consume(',') {
+			style = p.readStyle()
+		}
+
+		if err := p.expect('}'); err != nil {
+			return nil, err
+		}
+
+		if typ == "number" {
+			format, err := parseICUNumberStyle(style)
+			if err != nil {
+				return nil, p.errorf("%s: %v", name, err)
+			}
+
+			return icuNumberArg{name: name, numberFormat: format}, nil
+		}
+
+		dateStyle, fields, err := parseICUDateStyle(typ, style)
+		if err != nil {
+			return nil, p.errorf("%s: %v", name, err)
+		}
+
+		return icuDateArg{name: name, style: dateStyle, fields: fields}, nil
+	case "plural", "selectordinal":
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+
+		arg := icuPluralArg{name: name, ordinal: typ == "selectordinal"}
+
+		p.skipSpace()
+		if rest, ok := strings.CutPrefix(p.src[p.pos:], "offset:"); ok {
+			p.pos = len(p.src) - len(rest)
+			offset, err := strconv.ParseFloat(p.readWord(), 64)
+			if err != nil {
+				return nil, p.errorf("%s: invalid offset: %v", name, err)
+			}
+			arg.offset = offset
+		}
+
+		cases, err := p.parseCases(name, true, true)
+		if err != nil {
+			return nil, err
+		}
+		arg.cases = cases
+
+		return arg, nil
+	case "select":
+		if err := p.expect(','); err != nil {
+			return nil, err
+		}
+
+		cases, err := p.parseCases(name, false, inPlural)
+		if err != nil {
+			return nil, err
+		}
+
+		return icuSelectArg{name: name, cases: cases}, nil
+	default:
+		return nil, p.errorf("%s: unknown argument type: %q", name, typ)
+	}
+}
+
+// parseCases parses the cases of a plural or a select argument,
+// "inPlural" reports whether the # should be replaced by the plural value.
+func (p *icuParser) parseCases(name string, isPlural, inPlural bool) ([]icuCase, error) {
+	var (
+		cases    []icuCase
+		hasOther bool
+	)
+
+	for !p.consume('}') {
+		selector := p.readWord()
+		if selector == "" {
+			return nil, p.errorf("%s: expected case selector", name)
+		}
+
+		c := icuCase{selector: selector}
+
+		if isPlural {
+			switch selector {
+			case "zero", "one", "two", "few", "many", "other":
+			default:
+				n, err := strconv.ParseFloat(strings.TrimPrefix(selector, "="), 64)
+				if err != nil || selector[0] != '=' {
+					return nil, p.errorf("%s: invalid plural case: %q", name, selector)
+				}
+				c.exact = &n
+			}
+		}
+
+		if err := p.expect('{'); err != nil {
+			return nil, err
+		}
+
+		nodes, err := p.parseMessage(true, inPlural)
+		if err != nil {
+			return nil, err
+		}
+		p.pos++ // }
+
+		c.nodes = nodes
+		cases = append(cases, c)
+		hasOther = hasOther || selector == "other"
+	}
+
+	if !hasOther {
+		return nil, p.errorf("%s: missing the 'other' case", name)
+	}
+
+	return cases, nil
+}
@@ -32,11 +32,18 @@ type Locale struct {
 	// Fields set by this Load method.
 	Messages map[string]Renderer
 	Vars     []Var // shared per-locale variables.
+
+	// Fields set by the LoadTranslations method.
+	Translations map[string]*Translation // the PO, MO and XLIFF entries by key.
+	PluralForms  string                  // the Plural-Forms header of the last loaded translation file.
 }
 
 // Ensures that the Locale completes the context.Locale interface.
 var _ context.Locale = (*Locale)(nil)
 
+// Ensures that the Locale completes the optional context.LocaleFormatter interface.
+var _ context.LocaleFormatter = (*Locale)(nil)
+
 // Load sets the translation messages based on the Catalog's key values.
 func (loc *Locale) Load(c *Catalog, keyValues Map) error {
 	return loc.setMap(c, "", keyValues)
@@ -0,0 +1,321 @@
+package internal
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// ParsePO parses a gettext PO file.
+// Obsolete (#~) entries and previous (#|) strings are ignored.
+func ParsePO(b []byte) (*TranslationFile, error) {
+	var (
+		file    = new(TranslationFile)
+		t       = new(Translation)
+		field   *string // the last msg* string, for continuation lines.
+		hasData bool    // t has a msg* keyword.
+	)
+
+	flush := func() {
+		if hasData {
+			file.Translations = append(file.Translations, t)
+		}
+
+		t, field, hasData = new(Translation), nil, false
+	}
+
+	scanner := bufio.NewScanner(bytes.NewReader(b))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(b)+1)
+
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := strings.TrimSpace(scanner.Text())
+
+		switch {
+		case line == "":
+			flush()
+			continue
+		case strings.HasPrefix(line, "#"):
+			if hasData {
+				flush() // comments start a new entry.
+			}
+
+			switch {
+			case strings.HasPrefix(line, "#."):
+				t.ExtractedComments = append(t.ExtractedComments, strings.TrimSpace(line[2:]))
+			case strings.HasPrefix(line, "#:"):
+				t.References = append(t.References, strings.Fields(line[2:])...)
+			case strings.HasPrefix(line, "#,"):
+				for _, flag := range strings.Split(line[2:], ",") {
+					t.Flags = append(t.Flags, strings.TrimSpace(flag))
+				}
+			case strings.HasPrefix(line, "#~"), strings.HasPrefix(line, "#|"):
+			default:
+				t.Comments = append(t.Comments, strings.TrimSpace(line[1:]))
+			}
+
+			continue
+		case strings.HasPrefix(line, `"`):
+			if field == nil {
+				return nil, fmt.Errorf("po: line %d: unexpected string", lineNumber)
+			}
+
+			s, err := strconv.Unquote(line)
+			if err != nil {
+				return nil, fmt.Errorf("po: line %d: %w", lineNumber, err)
+			}
+
+			*field += s
+			continue
+		}
+
+		keyword, value, _ := strings.Cut(line, " ")
+		s, err := strconv.Unquote(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("po: line %d: %s: %w", lineNumber, keyword, err)
+		}
+
+		switch {
+		case keyword == "msgctxt":
+			if len(t.Values) > 0 {
+				flush()
+			}
+
+			t.Context = s
+			field = &t.Context
+		case keyword == "msgid":
+			if len(t.Values) > 0 {
+				flush()
+			}
+
+			t.ID = s
+			field = &t.ID
+		case keyword == "msgid_plural":
+			t.PluralID = s
+			field = &t.PluralID
+		case keyword == "msgstr":
+			t.Values = append(t.Values, s)
+			field = &t.Values[len(t.Values)-1]
+		case strings.HasPrefix(keyword, "msgstr[") && strings.HasSuffix(keyword, "]"):
+			idx, err := strconv.Atoi(keyword[7 : len(keyword)-1])
+			if err != nil || idx != len(t.Values) {
+				return nil, fmt.Errorf("po: line %d: unexpected plural form index: %s", lineNumber, keyword)
+			}
+
+			t.Values = append(t.Values, s)
+			field = &t.Values[len(t.Values)-1]
+		default:
+			return nil, fmt.Errorf("po: line %d: unknown keyword: %q", lineNumber, keyword)
+		}
+
+		hasData = true
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	flush()
+	file.setHeader()
+	return file, nil
+}
+
+// setHeader sets the Language and PluralForms fields
+// from the header entry, the one with an empty msgid.
+func (f *TranslationFile) setHeader() {
+	for _, t := range f.Translations {
+		if t.ID != "" || t.Context != "" || len(t.Values) == 0 {
+			continue
+		}
+
+		for _, line := range strings.Split(t.Values[0], "\n") {
+			name, value, ok := strings.Cut(line, ":")
+			if !ok {
+				continue
+			}
+
+			switch strings.TrimSpace(name) {
+			case "Language":
+				f.Language = strings.TrimSpace(value)
+			case "Plural-Forms":
+				f.PluralForms = strings.TrimSpace(value)
+			}
+		}
+
+		return
+	}
+}
+
+const (
+	moMagicLittleEndian = 0x950412de
+	moMagicBigEndian    = 0xde120495
+	// moContextSeparator separates the msgctxt from the msgid.
+	moContextSeparator = "\x04"
+)
+
+// ParseMO parses a gettext MO (compiled) file.
+func ParseMO(b []byte) (*TranslationFile, error) {
+	if len(b) < 28 {
+		return nil, fmt.Errorf("mo: invalid file size")
+	}
+
+	var order binary.ByteOrder
+	switch binary.LittleEndian.Uint32(b) {
+	case moMagicLittleEndian:
+		order = binary.LittleEndian
+	case moMagicBigEndian:
+		order = binary.BigEndian
+	default:
+		return nil, fmt.Errorf("mo: invalid magic number")
+	}
+
+	var (
+		count       = int(order.Uint32(b[8:]))
+		idsOffset   = int(order.Uint32(b[12:]))
+		valueOffset = int(order.Uint32(b[16:]))
+	)
+
+	// Both string tables (8 bytes per entry) must fit in the file,
+	// the count is not trusted before allocating the translations.
+	for _, tableOffset := range []int{idsOffset, valueOffset} {
+		if uint64(tableOffset)+uint64(count)*8 > uint64(len(b)) {
+			return nil, fmt.Errorf("mo: string table out of range")
+		}
+	}
+
+	readString := func(tableOffset, i int) (string, error) {
+		entry := tableOffset + i*8
+		if entry+8 > len(b) {
+			return "", fmt.Errorf("mo: string table out of range")
+		}
+
+		length, offset := int(order.Uint32(b[entry:])), int(order.Uint32(b[entry+4:]))
+		if offset+length > len(b) {
+			return "", fmt.Errorf("mo: string out of range")
+		}
+
+		return string(b[offset : offset+length]), nil
+	}
+
+	file := &TranslationFile{Translations: make([]*Translation, 0, count)}
+	for i := 0; i < count; i++ {
+		id, err := readString(idsOffset, i)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := readString(valueOffset, i)
+		if err != nil {
+			return nil, err
+		}
+
+		t := new(Translation)
+		if ctx, msgid, ok := strings.Cut(id, moContextSeparator); ok {
+			t.Context, id = ctx, msgid
+		}
+
+		t.ID, t.PluralID, _ = strings.Cut(id, "\x00")
+		if t.IsPlural() {
+			t.Values = strings.Split(value, "\x00")
+		} else {
+			t.Values = []string{value}
+		}
+
+		file.Translations = append(file.Translations, t)
+	}
+
+	file.setHeader()
+	return file, nil
+}
+
+// WritePO writes the translations of the "loc" Locale in the gettext PO format.
+func WritePO(w io.Writer, loc *Locale) error {
+	bw := bufio.NewWriter(w)
+
+	header := "Language: " + loc.ID + "\nMIME-Version: 1.0\nContent-Type: text/plain; charset=UTF-8\nContent-Transfer-Encoding: 8bit\n"
+	if loc.PluralForms != "" {
+		header += "Plural-Forms: " + loc.PluralForms + "\n"
+	}
+
+	writePOString(bw, "msgid", "")
+	writePOString(bw, "msgstr", header)
+
+	for _, t := range loc.translations() {
+		bw.WriteByte('\n')
+
+		for _, c := range t.Comments {
+			bw.WriteString(strings.TrimSpace("# "+c) + "\n")
+		}
+		for _, c := range t.ExtractedComments {
+			bw.WriteString("#. " + c + "\n")
+		}
+		if len(t.References) > 0 {
+			bw.WriteString("#: " + strings.Join(t.References, " ") + "\n")
+		}
+		if len(t.Flags) > 0 {
+			bw.WriteString("#, " + strings.Join(t.Flags, ", ") + "\n")
+		}
+
+		if t.Context != "" {
+			writePOString(bw, "msgctxt", t.Context)
+		}
+		writePOString(bw, "msgid", t.ID)
+
+		if !t.IsPlural() {
+			writePOString(bw, "msgstr", t.Values[0])
+			continue
+		}
+
+		writePOString(bw, "msgid_plural", t.PluralID)
+		for i, value := range t.Values {
+			writePOString(bw, "msgstr["+strconv.Itoa(i)+"]", value)
+		}
+	}
+
+	return bw.Flush()
+}
+
+// writePOString writes a keyword and its quoted value,
+// multiline values are written one line per string.
+func writePOString(w *bufio.Writer, keyword, value string) {
+	lines := strings.SplitAfter(value, "\n")
+	if len(lines) > 1 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	if len(lines) == 1 {
+		w.WriteString(keyword + " " + quotePO(value) + "\n")
+		return
+	}
+
+	w.WriteString(keyword + " \"\"\n")
+	for _, line := range lines {
+		w.WriteString(quotePO(line) + "\n")
+	}
+}
+
+func quotePO(s string) string {
+	var b strings.Builder
+	b.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			b.WriteString(`\"`)
+		case '\\':
+			b.WriteString(`\\`)
+		case '\n':
+			b.WriteString(`\n`)
+		case '\t':
+			b.WriteString(`\t`)
+		case '\r':
+			b.WriteString(`\r`)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	b.WriteByte('"')
+	return b.String()
+}
@@ -228,7 +228,13 @@ func stringIsTemplateValue(value, left, right string) bool {
 func getFuncs(loc *Locale) template.FuncMap {
 	// set the template funcs for this locale.
 	funcs := template.FuncMap{
-		"tr": loc.GetMessage,
+		"tr":                 loc.GetMessage,
+		"formatNumber":       loc.FormatNumber,
+		"formatPercent":      loc.FormatPercent,
+		"formatCurrency":     loc.FormatCurrency,
+		"formatDate":         loc.FormatDate,
+		"formatTime":         loc.FormatTime,
+		"formatRelativeTime": loc.FormatRelativeTime,
 	}
 
 	if getFuncs := loc.Options.Funcs; getFuncs != nil {
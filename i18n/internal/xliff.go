@@ -0,0 +1,328 @@
+package internal
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const (
+	xliffNamespace = "urn:oasis:names:tc:xliff:document:2.0"
+	// xliffPluralGroupType is the type of the XLIFF groups
+	// which hold the plural forms of a gettext plural entry, one unit per plural form.
+	xliffPluralGroupType = "x-gettext-plurals"
+)
+
+type (
+	xliffDocument struct {
+		XMLName xml.Name    `xml:"xliff"`
+		Xmlns   string      `xml:"xmlns,attr,omitempty"`
+		Version string      `xml:"version,attr"`
+		SrcLang string      `xml:"srcLang,attr"`
+		TrgLang string      `xml:"trgLang,attr,omitempty"`
+		Files   []xliffFile `xml:"file"`
+	}
+
+	xliffFile struct {
+		ID     string       `xml:"id,attr"`
+		Groups []xliffGroup `xml:"group"`
+		Units  []xliffUnit  `xml:"unit"`
+	}
+
+	xliffGroup struct {
+		ID     string       `xml:"id,attr"`
+		Name   string       `xml:"name,attr,omitempty"`
+		Type   string       `xml:"type,attr,omitempty"`
+		Notes  *xliffNotes  `xml:"notes,omitempty"`
+		Groups []xliffGroup `xml:"group"`
+		Units  []xliffUnit  `xml:"unit"`
+	}
+
+	xliffUnit struct {
+		ID       string         `xml:"id,attr"`
+		Name     string         `xml:"name,attr,omitempty"`
+		Notes    *xliffNotes    `xml:"notes,omitempty"`
+		Segments []xliffSegment `xml:"segment"`
+	}
+
+	xliffNotes struct {
+		Notes []xliffNote `xml:"note"`
+	}
+
+	xliffNote struct {
+		Category string `xml:"category,attr,omitempty"`
+		Text     string `xml:",chardata"`
+	}
+
+	xliffSegment struct {
+		Source string  `xml:"source"`
+		Target *string `xml:"target"`
+	}
+)
+
+func (g xliffGroup) key() string {
+	if g.Name != "" {
+		return g.Name
+	}
+
+	return g.ID
+}
+
+func (u xliffUnit) key() string {
+	if u.Name != "" {
+		return u.Name
+	}
+
+	return u.ID
+}
+
+func (u xliffUnit) text() (source string, target string, translated bool) {
+	for _, s := range u.Segments {
+		source += s.Source
+		if s.Target != nil {
+			target += *s.Target
+			translated = true
+		}
+	}
+
+	return
+}
+
+func (n *xliffNotes) comments() (comments []string) {
+	if n == nil {
+		return nil
+	}
+
+	for _, note := range n.Notes {
+		comments = append(comments, note.Text)
+	}
+
+	return
+}
+
+// ParseXLIFF parses a XLIFF 2.0 file.
+// The unit's name (or its id) is the translation key and the unit's groups are its context,
+// except the groups of the "x-gettext-plurals" type which hold the plural forms of a key.
+// When the document has no target language the source text is used instead.
+// Inline elements of the source and target texts are not supported.
+func ParseXLIFF(b []byte) (*TranslationFile, error) {
+	var doc xliffDocument
+	if err := xml.Unmarshal(b, &doc); err != nil {
+		return nil, fmt.Errorf("xliff: %w", err)
+	}
+
+	if !strings.HasPrefix(doc.Version, "2.") {
+		return nil, fmt.Errorf("xliff: unsupported version: %q", doc.Version)
+	}
+
+	file := &TranslationFile{Language: doc.TrgLang}
+	if file.Language == "" {
+		file.Language = doc.SrcLang
+	}
+
+	useSource := doc.TrgLang == ""
+
+	var walk func(context string, groups []xliffGroup, units []xliffUnit)
+	walk = func(context string, groups []xliffGroup, units []xliffUnit) {
+		for _, u := range units {
+			source, target, translated := u.text()
+			if useSource {
+				target, translated = source, true
+			}
+
+			t := &Translation{Context: context, ID: u.key(), Comments: u.Notes.comments()}
+			if translated {
+				t.Values = []string{target}
+			}
+
+			file.Translations = append(file.Translations, t)
+		}
+
+		for _, g := range groups {
+			if g.Type != xliffPluralGroupType {
+				childContext := g.key()
+				if context != "" {
+					childContext = context + "." + childContext
+				}
+
+				walk(childContext, g.Groups, g.Units)
+				continue
+			}
+
+			t := &Translation{Context: context, ID: g.key(), Comments: g.Notes.comments()}
+			for i, u := range g.Units {
+				source, target, translated := u.text()
+				if useSource {
+					target, translated = source, true
+				}
+
+				if i == 1 {
+					t.PluralID = source
+				}
+
+				if !translated {
+					t.Values = nil
+					break
+				}
+
+				t.Values = append(t.Values, target)
+			}
+
+			if t.PluralID == "" {
+				t.PluralID = t.ID // a single plural form.
+			}
+
+			file.Translations = append(file.Translations, t)
+		}
+	}
+
+	for _, f := range doc.Files {
+		walk("", f.Groups, f.Units)
+	}
+
+	return file, nil
+}
+
+// WriteXLIFF writes the translations of the "target" Locale in the XLIFF 2.0 format.
+// The source texts are the translations of the "source" Locale (e.g. the default language),
+// or the translation keys when the source does not have them.
+func WriteXLIFF(w io.Writer, source, target *Locale) error {
+	sourceTexts := make(map[string]string)
+	for _, t := range source.translations() {
+		if len(t.Values) > 0 {
+			sourceTexts[t.Key()] = t.Values[0]
+		}
+	}
+
+	file := xliffFile{ID: target.ID}
+	groups := make(map[string]int) // group index by context, to group the units of the same context.
+
+	for _, t := range target.translations() {
+		var (
+			units  = &file.Units
+			parent = &file.Groups
+		)
+
+		if t.Context != "" {
+			idx, ok := groups[t.Context]
+			if !ok {
+				idx = len(file.Groups)
+				file.Groups = append(file.Groups, xliffGroup{ID: xliffID(t.Context, idx), Name: xliffName(t.Context)})
+				groups[t.Context] = idx
+			}
+
+			units, parent = &file.Groups[idx].Units, &file.Groups[idx].Groups
+		}
+
+		notes := newXLIFFNotes(t.Comments)
+
+		if !t.IsPlural() {
+			src, ok := sourceTexts[t.Key()]
+			if !ok {
+				src = t.ID
+			}
+
+			*units = append(*units, xliffUnit{
+				ID:       xliffID(t.ID, len(*units)),
+				Name:     xliffName(t.ID),
+				Notes:    notes,
+				Segments: []xliffSegment{{Source: src, Target: &t.Values[0]}},
+			})
+			continue
+		}
+
+		g := xliffGroup{ID: xliffID(t.ID, len(*parent)), Name: xliffName(t.ID), Type: xliffPluralGroupType, Notes: notes}
+		for i := range t.Values {
+			src := t.ID
+			if i > 0 {
+				src = t.PluralID
+			}
+
+			g.Units = append(g.Units, xliffUnit{
+				ID:       strconv.Itoa(i),
+				Segments: []xliffSegment{{Source: src, Target: &t.Values[i]}},
+			})
+		}
+
+		if t.Context == "" {
+			// keep the context groups' indexes.
+			file.Groups = append(file.Groups, g)
+			continue
+		}
+
+		*parent = append(*parent, g)
+	}
+
+	doc := xliffDocument{
+		Xmlns:   xliffNamespace,
+		Version: "2.0",
+		SrcLang: source.ID,
+		TrgLang: target.ID,
+		Files:   []xliffFile{file},
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+func newXLIFFNotes(comments []string) *xliffNotes {
+	if len(comments) == 0 {
+		return nil
+	}
+
+	notes := new(xliffNotes)
+	for _, c := range comments {
+		notes.Notes = append(notes.Notes, xliffNote{Text: c})
+	}
+
+	return notes
+}
+
+// xliffID returns the "key" itself when it is a valid XLIFF id (NMTOKEN)
+// or an id generated by its position.
+func xliffID(key string, position int) string {
+	if isXLIFFNMToken(key) {
+		return key
+	}
+
+	return "u" + strconv.Itoa(position+1)
+}
+
+// xliffName returns the "key" when it is not a valid XLIFF id,
+// so the key is kept as the unit's or group's name.
+func xliffName(key string) string {
+	if isXLIFFNMToken(key) {
+		return ""
+	}
+
+	return key
+}
+
+func isXLIFFNMToken(s string) bool {
+	if s == "" {
+		return false
+	}
+
+	for _, r := range s {
+		switch {
+		case 'a' <= r && r <= 'z', 'A' <= r && r <= 'Z', '0' <= r && r <= '9':
+		case r == '.', r == '-', r == '_', r == ':':
+		default:
+			return false
+		}
+	}
+
+	return true
+}
@@ -113,15 +113,20 @@ func KV(langMap LangMap, opts ...LoaderConfig) Loader {
 			return nil, err
 		}
 
-		for _, langIndex := range languageIndexes {
+		for i, langIndex := range languageIndexes {
 			if langIndex == -1 {
 				// If loader has more languages than defined for use in New function,
 				// e.g. when New(KV(m), "en-US") contains el-GR and en-US but only "en-US" passed.
 				continue
 			}
 
-			kv := keyValuesMulti[langIndex]
-			err := cat.Store(langIndex, kv)
+			kv := keyValuesMulti[i]
+			store := cat.Store
+			if options.Syntax == ICUSyntax {
+				store = cat.StoreICU
+			}
+
+			err := store(langIndex, kv)
 			if err != nil {
 				return nil, err
 			}
@@ -137,6 +142,39 @@ func KV(langMap LangMap, opts ...LoaderConfig) Loader {
 	}
 }
 
+type (
+	// MessageSyntax is the syntax of the translation messages of a locale file.
+	// See `LoaderConfig.Syntax` and `LoaderConfig.SyntaxFunc` fields.
+	MessageSyntax = internal.MessageSyntax
+)
+
+const (
+	// TemplateSyntax is the default message syntax:
+	// Go templates, ${Vars} and plural forms as keys.
+	TemplateSyntax = internal.TemplateSyntax
+	// ICUSyntax is the ICU MessageFormat syntax, e.g.
+	// "You have {count, plural, =0 {no messages} one {# message} other {# messages}}".
+	// Useful to share the same translation files between the server and the client.
+	ICUSyntax = internal.ICUSyntax
+)
+
+// ICUFiles returns a `LoaderConfig.SyntaxFunc` which
+// selects the ICUSyntax for the locale files that their base name
+// match any of the given "patterns", e.g. ICUFiles("*.icu.yml", "*.icu.json").
+// The rest of the files use the TemplateSyntax.
+func ICUFiles(patterns ...string) func(fileName string) MessageSyntax {
+	return func(fileName string) MessageSyntax {
+		base := filepath.Base(fileName)
+		for _, pattern := range patterns {
+			if ok, _ := filepath.Match(pattern, base); ok {
+				return ICUSyntax
+			}
+		}
+
+		return TemplateSyntax
+	}
+}
+
 // DefaultLoaderConfig represents the default loader configuration.
 var DefaultLoaderConfig = LoaderConfig{
 	Left:               "{{",
@@ -171,9 +209,22 @@ func load(assetNames []string, asset func(string) ([]byte, error), options Loade
 
 		for langIndex, langFiles := range languageFiles {
 			keyValues := make(map[string]interface{})
+			icuKeyValues := make(map[string]interface{})
+
+			type translationFile struct {
+				*internal.TranslationFile
+				syntax MessageSyntax
+			}
+			var translationFiles []translationFile
 
 			for _, fileName := range langFiles {
+				syntax := options.Syntax
+				if options.SyntaxFunc != nil {
+					syntax = options.SyntaxFunc(fileName)
+				}
+
 				unmarshal := yaml.Unmarshal
+				var parse func([]byte) (*internal.TranslationFile, error)
 				if idx := strings.LastIndexByte(fileName, '.'); idx > 1 {
 					switch fileName[idx:] {
 					case ".toml", ".tml":
@@ -182,6 +233,12 @@ func load(assetNames []string, asset func(string) ([]byte, error), options Loade
 						unmarshal = json.Unmarshal
 					case ".ini":
 						unmarshal = unmarshalINI
+					case ".po":
+						parse = internal.ParsePO
+					case ".mo":
+						parse = internal.ParseMO
+					case ".xlf", ".xliff":
+						parse = internal.ParseXLIFF
 					}
 				}
 
@@ -190,7 +247,22 @@ func load(assetNames []string, asset func(string) ([]byte, error), options Loade
 					return nil, err
 				}
 
-				if err = unmarshal(b, &keyValues); err != nil {
+				if parse != nil {
+					f, err := parse(b)
+					if err != nil {
+						return nil, fmt.Errorf("%s: %w", fileName, err)
+					}
+
+					translationFiles = append(translationFiles, translationFile{f, syntax})
+					continue
+				}
+
+				dest := &keyValues
+				if syntax == ICUSyntax {
+					dest = &icuKeyValues
+				}
+
+				if err = unmarshal(b, dest); err != nil {
 					return nil, err
 				}
 			}
@@ -199,6 +271,18 @@ func load(assetNames []string, asset func(string) ([]byte, error), options Loade
 			if err != nil {
 				return nil, err
 			}
+
+			if len(icuKeyValues) > 0 {
+				if err = cat.StoreICU(langIndex, icuKeyValues); err != nil {
+					return nil, err
+				}
+			}
+
+			for _, f := range translationFiles {
+				if err = cat.StoreTranslations(langIndex, f.TranslationFile, f.syntax); err != nil {
+					return nil, err
+				}
+			}
 		}
 
 		if n := len(cat.Locales); n == 0 {
@@ -0,0 +1,27 @@
+package i18n
+
+import (
+	"testing"
+)
+
+func TestKV(t *testing.T) {
+	m := LangMap{
+		"en-US": map[string]interface{}{"hello": "Hello"},
+		"el-GR": map[string]interface{}{"hello": "Γειά"},
+		"de-DE": map[string]interface{}{"hello": "Hallo"}, // not registered.
+	}
+
+	// The map's iteration order is random, load it a few times.
+	for n := 0; n < 20; n++ {
+		i := New()
+		if err := i.LoadKV(m, "el-GR", "en-US"); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, lang := range []string{"en-US", "el-GR"} {
+			if expected, got := m[lang]["hello"], i.Tr(lang, "hello"); got != expected {
+				t.Fatalf("[%d] %s: expected: %q but got: %q", n, lang, expected, got)
+			}
+		}
+	}
+}
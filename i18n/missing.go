@@ -0,0 +1,125 @@
+package i18n
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// MissingTranslation holds the information of a key
+// which was requested but it was not found for a language.
+type MissingTranslation struct {
+	Lang     string    `json:"lang"`
+	Key      string    `json:"key"`
+	Hits     uint64    `json:"hits"`
+	LastSeen time.Time `json:"lastSeen"`
+}
+
+// MissingTranslations records the missing keys per language at serve-time.
+// Set it to the `I18n.Missing` field to enable it.
+// It is safe for concurrent use.
+type MissingTranslations struct {
+	mu      sync.RWMutex
+	entries map[string]map[string]*MissingTranslation // by language and key.
+}
+
+// NewMissingTranslations returns a new, empty, MissingTranslations collector.
+//
+// Example Code:
+//
+//	app.I18n.Missing = i18n.NewMissingTranslations()
+//	app.Get("/debug/i18n/missing", app.I18n.Missing.Handler)
+func NewMissingTranslations() *MissingTranslations {
+	return &MissingTranslations{
+		entries: make(map[string]map[string]*MissingTranslation),
+	}
+}
+
+// Add records a missing "key" of the "lang" language.
+// It does nothing on a nil MissingTranslations.
+func (m *MissingTranslations) Add(lang, key string) {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	keys, ok := m.entries[lang]
+	if !ok {
+		keys = make(map[string]*MissingTranslation)
+		m.entries[lang] = keys
+	}
+
+	entry, ok := keys[key]
+	if !ok {
+		entry = &MissingTranslation{Lang: lang, Key: key}
+		keys[key] = entry
+	}
+
+	entry.Hits++
+	entry.LastSeen = time.Now()
+	m.mu.Unlock()
+}
+
+// List returns a copy of the recorded missing translations of the given languages
+// (or all of them if "langs" is empty), ordered by language and then by hits, most requested first.
+func (m *MissingTranslations) List(langs ...string) []MissingTranslation {
+	if m == nil {
+		return nil
+	}
+
+	m.mu.RLock()
+	list := make([]MissingTranslation, 0)
+	for lang, keys := range m.entries {
+		if len(langs) > 0 && !containsString(langs, lang) {
+			continue
+		}
+
+		for _, entry := range keys {
+			list = append(list, *entry)
+		}
+	}
+	m.mu.RUnlock()
+
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].Lang != list[j].Lang {
+			return list[i].Lang < list[j].Lang
+		}
+
+		if list[i].Hits != list[j].Hits {
+			return list[i].Hits > list[j].Hits
+		}
+
+		return list[i].Key < list[j].Key
+	})
+
+	return list
+}
+
+// Reset clears the recorded missing translations.
+func (m *MissingTranslations) Reset() {
+	if m == nil {
+		return
+	}
+
+	m.mu.Lock()
+	m.entries = make(map[string]map[string]*MissingTranslation)
+	m.mu.Unlock()
+}
+
+// Handler is an Iris Handler which writes the recorded missing translations as JSON.
+// The "lang" URL query parameter can be used to filter them by language, e.g. ?lang=el-GR.
+func (m *MissingTranslations) Handler(ctx *context.Context) {
+	ctx.JSON(m.List(ctx.URLParamSlice("lang")...))
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,58 @@
+package i18n
+
+import (
+	"testing"
+)
+
+func TestMissingTranslations(t *testing.T) {
+	i := New()
+	i.Missing = NewMissingTranslations()
+
+	err := i.LoadKV(LangMap{
+		"en-US": map[string]interface{}{"hello": "Hello", "onlyDefault": "Default"},
+		"el-GR": map[string]interface{}{"hello": "Γειά"},
+	}, "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	i.Tr("el-GR", "hello")
+	i.Tr("el-GR", "onlyDefault")
+	i.Tr("el-GR", "onlyDefault")
+	i.Tr("el-GR", "notFound")
+	i.Tr("en-US", "notFound")
+
+	list := i.Missing.List()
+	expected := []MissingTranslation{
+		{Lang: "el-GR", Key: "onlyDefault", Hits: 2},
+		{Lang: "el-GR", Key: "notFound", Hits: 1},
+		{Lang: "en-US", Key: "notFound", Hits: 2}, // el-GR fallback and en-US.
+	}
+
+	if len(list) != len(expected) {
+		t.Fatalf("expected %d missing translations but got %d: %#+v", len(expected), len(list), list)
+	}
+
+	for idx, e := range expected {
+		got := list[idx]
+		if got.Lang != e.Lang || got.Key != e.Key || got.Hits != e.Hits || got.LastSeen.IsZero() {
+			t.Fatalf("[%d] expected: %#+v but got: %#+v", idx, e, got)
+		}
+	}
+
+	if got := i.Missing.List("en-US"); len(got) != 1 || got[0].Key != "notFound" {
+		t.Fatalf("expected a single en-US missing translation but got: %#+v", got)
+	}
+
+	i.Missing.Reset()
+	if got := i.Missing.List(); len(got) != 0 {
+		t.Fatalf("expected no missing translations after reset but got: %#+v", got)
+	}
+
+	// Disabled by default.
+	var disabled *MissingTranslations
+	disabled.Add("en-US", "key")
+	if got := disabled.List(); got != nil {
+		t.Fatalf("expected nil list but got: %#+v", got)
+	}
+}
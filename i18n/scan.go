@@ -0,0 +1,255 @@
+package i18n
+
+import (
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kataras/iris/v12/i18n/internal"
+)
+
+// ScanTemplateExtensions is the list of the view file extensions
+// that the `I18n.Scan` method searches for `tr` template function calls.
+var ScanTemplateExtensions = []string{".html", ".tmpl", ".gohtml", ".tpl", ".jet", ".pug", ".ace", ".amber", ".hbs", ".handlebars"}
+
+// ScanReport is the result of the `I18n.Scan` method.
+type ScanReport struct {
+	// Used holds the translation keys found in the source files
+	// and their locations (file:line).
+	Used map[string][]string `json:"used"`
+	// Unused holds the keys of the loaded locales which are not used by the source files.
+	// Keys used dynamically (e.g. ctx.Tr(key)) are reported as unused too.
+	Unused []string `json:"unused"`
+	// Untranslated holds the used keys which are missing, per language.
+	Untranslated map[string][]string `json:"untranslated"`
+	// Errors holds the errors of the files that could not be read or parsed,
+	// e.g. Go files with syntax errors. The rest of the files are still scanned.
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Scan walks the "fileSystem" (e.g. os.DirFS(".")) and searches for translation keys in Go source files,
+// ctx.Tr("key"), i18n.Tr("lang", "key"), i18n.TrContext(ctx, "key") and locale.GetMessage("key") calls,
+// and in view files (see `ScanTemplateExtensions`), {{ tr "key" }}, {{ tr "lang" "key" }} and {{ call .tr "key" }}.
+// The "vendor", "node_modules" and hidden directories are skipped.
+//
+// It reports the used, the unused and the untranslated keys of the loaded locales.
+// Only the string literal keys can be found. A file which cannot be read or parsed
+// does not stop the scan, its error is reported through the ScanReport.Errors field.
+func (i *I18n) Scan(fileSystem fs.FS) (*ScanReport, error) {
+	if !i.Loaded() {
+		return nil, fmt.Errorf("i18n: not loaded")
+	}
+
+	s := &keyScanner{
+		i:    i,
+		used: make(map[string][]string),
+		fset: token.NewFileSet(),
+	}
+
+	err := fs.WalkDir(fileSystem, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() {
+			base := d.Name()
+			if name != "." && (base == "vendor" || base == "node_modules" || strings.HasPrefix(base, ".")) {
+				return fs.SkipDir
+			}
+
+			return nil
+		}
+
+		ext := path.Ext(name)
+		if ext != ".go" && !containsString(ScanTemplateExtensions, ext) {
+			return nil
+		}
+
+		b, err := fs.ReadFile(fileSystem, name)
+		if err != nil {
+			s.errors = append(s.errors, err.Error())
+			return nil
+		}
+
+		if ext == ".go" {
+			if err = s.scanGo(name, b); err != nil {
+				s.errors = append(s.errors, err.Error())
+			}
+			return nil
+		}
+
+		s.scanTemplate(name, string(b))
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ScanReport{
+		Used:         s.used,
+		Unused:       make([]string, 0),
+		Untranslated: make(map[string][]string),
+		Errors:       s.errors,
+	}
+
+	localizer := i.getLocalizer()
+	allKeys := make(map[string]struct{})
+	for index, tag := range i.getMatcher().Languages {
+		loc, ok := localizer.GetLocale(index).(*internal.Locale)
+		if !ok {
+			return nil, fmt.Errorf("i18n: scan is not supported by the custom localizer of %T", localizer)
+		}
+
+		for key := range loc.Messages {
+			allKeys[key] = struct{}{}
+		}
+
+		var untranslated []string
+		for key := range s.used {
+			if _, ok := loc.Messages[key]; !ok {
+				untranslated = append(untranslated, key)
+			}
+		}
+
+		if len(untranslated) > 0 {
+			sort.Strings(untranslated)
+			report.Untranslated[tag.String()] = untranslated
+		}
+	}
+
+	for key := range allKeys {
+		if _, ok := s.used[key]; !ok {
+			report.Unused = append(report.Unused, key)
+		}
+	}
+	sort.Strings(report.Unused)
+
+	return report, nil
+}
+
+type keyScanner struct {
+	i      *I18n
+	used   map[string][]string
+	fset   *token.FileSet
+	errors []string
+}
+
+func (s *keyScanner) add(key, source string) {
+	s.used[key] = append(s.used[key], source)
+}
+
+// isLanguage reports whether "s" is one of the registered languages,
+// to separate the Tr("lang", "key") from the Tr("key", args...) calls.
+func (s *keyScanner) isLanguage(str string) bool {
+	for _, tag := range s.i.getMatcher().Languages {
+		if strings.EqualFold(tag.String(), str) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// key returns the translation key of the "values" arguments, nil for non-literal ones.
+func (s *keyScanner) key(values []*string) (string, bool) {
+	switch {
+	case len(values) > 1 && values[1] != nil && (values[0] == nil || s.isLanguage(*values[0])):
+		return *values[1], true
+	case len(values) > 0 && values[0] != nil:
+		return *values[0], true
+	default:
+		return "", false
+	}
+}
+
+func (s *keyScanner) scanGo(name string, src []byte) error {
+	f, err := parser.ParseFile(s.fset, name, src, parser.SkipObjectResolution)
+	if err != nil {
+		return err
+	}
+
+	ast.Inspect(f, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+
+		var args []ast.Expr
+		switch sel.Sel.Name {
+		case "Tr":
+			args = call.Args
+		case "GetMessage":
+			if len(call.Args) > 0 {
+				args = call.Args[:1]
+			}
+		case "TrContext":
+			if len(call.Args) > 1 {
+				args = call.Args[1:2]
+			}
+		default:
+			return true
+		}
+
+		values := make([]*string, 0, 2)
+		for _, arg := range args {
+			if len(values) == 2 {
+				break
+			}
+
+			var value *string
+			if lit, ok := arg.(*ast.BasicLit); ok && lit.Kind == token.STRING {
+				if v, err := strconv.Unquote(lit.Value); err == nil {
+					value = &v
+				}
+			}
+
+			values = append(values, value)
+		}
+
+		if key, ok := s.key(values); ok {
+			pos := s.fset.Position(call.Pos())
+			s.add(key, pos.Filename+":"+strconv.Itoa(pos.Line))
+		}
+
+		return true
+	})
+
+	return nil
+}
+
+const templateStringPattern = `("(?:[^"\\]|\\.)*"|` + "`[^`]*`" + `)`
+
+var templateTrRegex = regexp.MustCompile(`(?:\btr|\.tr)\s+` + templateStringPattern + `(?:\s+` + templateStringPattern + `)?`)
+
+func (s *keyScanner) scanTemplate(name, src string) {
+	for _, match := range templateTrRegex.FindAllStringSubmatchIndex(src, -1) {
+		values := make([]*string, 0, 2)
+		for g := 1; g <= 2; g++ {
+			start, end := match[g*2], match[g*2+1]
+			if start == -1 {
+				continue
+			}
+
+			if v, err := strconv.Unquote(src[start:end]); err == nil {
+				values = append(values, &v)
+			}
+		}
+
+		if key, ok := s.key(values); ok {
+			line := strings.Count(src[:match[0]], "\n") + 1
+			s.add(key, name+":"+strconv.Itoa(line))
+		}
+	}
+}
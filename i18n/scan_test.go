@@ -0,0 +1,69 @@
+package i18n
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestScan(t *testing.T) {
+	i := New()
+	err := i.LoadKV(LangMap{
+		"en-US": map[string]interface{}{"hello": "Hello", "title": "Title", "unused": "Unused", "nav": map[string]interface{}{"home": "Home"}},
+		"el-GR": map[string]interface{}{"hello": "Γειά", "unusedGreek": "Αχρησιμοποίητο"},
+	}, "en-US", "el-GR")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fileSystem := fstest.MapFS{
+		"main.go": {Data: []byte(`package main
+
+func handler(ctx iris.Context) {
+	ctx.Tr("hello")
+	ctx.Tr(dynamicKey)
+	app.I18n.Tr("el-GR", "title", "arg")
+	app.I18n.TrContext(ctx, "nav.home")
+	ctx.Tr("missing", "arg")
+}
+`)},
+		"views/index.html":         {Data: []byte("<h1>{{ tr \"title\" }}</h1>\n<p>{{call .tr \"hello\" \"iris\"}}</p>\n<p>{{ tr \"en-US\" `nav.home` }}</p>")},
+		"broken.go":                {Data: []byte(`package main; func broken() { ctx.Tr("broken"`)},
+		"views/ignored.txt":        {Data: []byte(`{{ tr "ignored" }}`)},
+		"node_modules/lib/lib.go":  {Data: []byte(`package lib; func f() { ctx.Tr("ignored") }`)},
+		".git/hooks/pre-commit.go": {Data: []byte(`package hooks; func f() { ctx.Tr("ignored") }`)},
+	}
+
+	report, err := i.Scan(fileSystem)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedUsed := map[string][]string{
+		"hello":    {"main.go:4", "views/index.html:2"},
+		"title":    {"main.go:6", "views/index.html:1"},
+		"nav.home": {"main.go:7", "views/index.html:3"},
+		"missing":  {"main.go:8"},
+	}
+	if !reflect.DeepEqual(report.Used, expectedUsed) {
+		t.Fatalf("expected used keys:\n%v\nbut got:\n%v", expectedUsed, report.Used)
+	}
+
+	if expected := []string{"unused", "unusedGreek"}; !reflect.DeepEqual(report.Unused, expected) {
+		t.Fatalf("expected unused keys: %v but got: %v", expected, report.Unused)
+	}
+
+	expectedUntranslated := map[string][]string{
+		"en-US": {"missing"},
+		"el-GR": {"missing", "nav.home", "title"},
+	}
+	if !reflect.DeepEqual(report.Untranslated, expectedUntranslated) {
+		t.Fatalf("expected untranslated keys:\n%v\nbut got:\n%v", expectedUntranslated, report.Untranslated)
+	}
+
+	// The files with syntax errors do not stop the scan.
+	if len(report.Errors) != 1 || !strings.HasPrefix(report.Errors[0], "broken.go:") {
+		t.Fatalf("expected a single error of broken.go but got: %v", report.Errors)
+	}
+}
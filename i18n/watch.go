@@ -0,0 +1,164 @@
+package i18n
+
+import (
+	stdContext "context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Source is a location of locale files, local or remote, which can be watched for changes.
+// See `GlobSource`, `FSSource` and the `I18n.Watch` method.
+//
+// A remote Source (e.g. a translation management service)
+// can return a Loader created by the `Assets` or `KV` package-level functions.
+type Source interface {
+	// Version should return a value which changes
+	// whenever the locale files change, e.g. a checksum or an ETag.
+	Version() (string, error)
+	// Loader should return a Loader of the current locale files.
+	Loader() (Loader, error)
+}
+
+type fileSource struct {
+	names   func() ([]string, error)
+	read    func(string) ([]byte, error)
+	options LoaderConfig
+}
+
+// GlobSource returns a Source of the locale files that match the "globPattern".
+// Its version is the checksum of the files, new and removed files are detected too.
+//
+// See `I18n.Watch` method.
+func GlobSource(globPattern string, options LoaderConfig) Source {
+	return &fileSource{
+		names:   func() ([]string, error) { return filepath.Glob(globPattern) },
+		read:    os.ReadFile,
+		options: options,
+	}
+}
+
+// FSSource returns a Source of the locale files of the "fileSystem"
+// that match the "pattern". Its version is the checksum of the files.
+//
+// See `I18n.Watch` method.
+func FSSource(fileSystem fs.FS, pattern string, options LoaderConfig) Source {
+	pattern = strings.TrimPrefix(pattern, "./")
+
+	return &fileSource{
+		names: func() ([]string, error) { return fs.Glob(fileSystem, pattern) },
+		read: func(name string) ([]byte, error) {
+			return fs.ReadFile(fileSystem, name)
+		},
+		options: options,
+	}
+}
+
+func (s *fileSource) Version() (string, error) {
+	names, err := s.names()
+	if err != nil {
+		return "", err
+	}
+	sort.Strings(names)
+
+	h := sha256.New()
+	for _, name := range names {
+		b, err := s.read(name)
+		if err != nil {
+			return "", err
+		}
+
+		h.Write([]byte(name))
+		h.Write([]byte{0})
+		h.Write(b)
+		h.Write([]byte{0})
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func (s *fileSource) Loader() (Loader, error) {
+	names, err := s.names()
+	if err != nil {
+		return nil, err
+	}
+
+	return load(names, s.read, s.options), nil
+}
+
+// Watch loads the locales of the "source" and then checks the source for changes
+// every "interval" (defaults to 2 seconds) until the "ctx" is canceled.
+// On changes, the new locales replace the current ones atomically, under live traffic.
+// Broken locale files are rejected and the previous locales keep serving,
+// see the `OnReload` field to log the reload results.
+//
+// Example Code:
+//
+//	app.I18n.OnReload = func(err error) {
+//		if err != nil {
+//			app.Logger().Errorf("i18n: reload: %v", err)
+//		}
+//	}
+//	err := app.I18n.Watch(ctx, i18n.GlobSource("./locales/*/*", app.I18n.Loader), 0, "en-US", "el-GR")
+func (i *I18n) Watch(ctx stdContext.Context, source Source, interval time.Duration, languages ...string) error {
+	if interval <= 0 {
+		interval = 2 * time.Second
+	}
+
+	version, err := source.Version()
+	if err != nil {
+		return err
+	}
+
+	loader, err := source.Loader()
+	if err != nil {
+		return err
+	}
+
+	if err = i.Reset(loader, languages...); err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				newVersion, err := source.Version()
+				if err != nil {
+					if i.OnReload != nil {
+						i.OnReload(err)
+					}
+					continue
+				}
+
+				if newVersion == version {
+					continue
+				}
+				// Do not retry a rejected version, wait for the next change.
+				version = newVersion
+
+				loader, err := source.Loader()
+				if err != nil {
+					if i.OnReload != nil {
+						i.OnReload(err)
+					}
+					continue
+				}
+
+				i.reload(loader)
+			}
+		}
+	}()
+
+	return nil
+}
@@ -0,0 +1,119 @@
+package i18n
+
+import (
+	stdContext "context"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	localeFile := filepath.Join(dir, "en-US", "messages.yml")
+	if err := os.MkdirAll(filepath.Dir(localeFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	writeLocale := func(contents string) {
+		t.Helper()
+		if err := os.WriteFile(localeFile, []byte(contents), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	writeLocale("hello: Hello\n")
+
+	reloaded := make(chan error, 1)
+
+	i := New()
+	i.OnReload = func(err error) {
+		reloaded <- err
+	}
+
+	ctx, cancel := stdContext.WithCancel(stdContext.Background())
+	defer cancel()
+
+	err := i.Watch(ctx, GlobSource(filepath.Join(dir, "*", "*"), i.Loader), 10*time.Millisecond, "en-US")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "Hello", i.Tr("en-US", "hello"); expected != got {
+		t.Fatalf("expected: %s but got: %s", expected, got)
+	}
+
+	// Serve translations while reloading.
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for ctx.Err() == nil {
+			i.Tr("en-US", "hello")
+		}
+	}()
+	defer wg.Wait()
+	defer cancel()
+
+	waitReload := func() error {
+		t.Helper()
+		select {
+		case err := <-reloaded:
+			return err
+		case <-time.After(5 * time.Second):
+			t.Fatal("reload timed out")
+			return nil
+		}
+	}
+
+	writeLocale("hello: Hello v2\n")
+	if err := waitReload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "Hello v2", i.Tr("en-US", "hello"); expected != got {
+		t.Fatalf("expected: %s but got: %s", expected, got)
+	}
+
+	// Broken files are rejected and the previous catalog keeps serving.
+	writeLocale("hello: [Hello\n")
+	if err := waitReload(); err == nil {
+		t.Fatal("expected a reload error")
+	}
+
+	if expected, got := "Hello v2", i.Tr("en-US", "hello"); expected != got {
+		t.Fatalf("expected: %s but got: %s", expected, got)
+	}
+
+	writeLocale("hello: Hello v3\n")
+	if err := waitReload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "Hello v3", i.Tr("en-US", "hello"); expected != got {
+		t.Fatalf("expected: %s but got: %s", expected, got)
+	}
+}
+
+func TestReload(t *testing.T) {
+	contents := "hello: Hello\n"
+
+	i := New()
+	loader := Assets(func() []string { return []string{"en-US.yml"} }, func(string) ([]byte, error) {
+		return []byte(contents), nil
+	}, i.Loader)
+
+	if err := i.Reset(loader, "en-US"); err != nil {
+		t.Fatal(err)
+	}
+
+	contents = "hello: Hello v2\n"
+	if err := i.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "Hello v2", i.Tr("en-US", "hello"); expected != got {
+		t.Fatalf("expected: %s but got: %s", expected, got)
+	}
+}
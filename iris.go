@@ -5,6 +5,7 @@ import (
 	stdContext "context"
 	"errors"
 	"fmt"
+	"html/template"
 	"io"
 	"log"
 	"math"
@@ -83,6 +84,10 @@ type Application struct {
 
 	// view engine
 	view *view.View
+	// see WatchViews.
+	viewWatch         bool
+	viewWatchInterval time.Duration
+	viewWatchCancel   stdContext.CancelFunc
 	// used for build
 	builded     bool
 	defaultMode bool
@@ -412,6 +417,64 @@ func (app *Application) RegisterView(viewEngine view.Engine) {
 	app.view.Register(viewEngine)
 }
 
+// WatchViews makes the registered view engine to check its template files for changes every "interval"
+// (defaults to one second) and reload only the changed templates and the ones that depend on them,
+// e.g. layouts, partials and includes, instead of re-parsing all of them on each request
+// like the view engines' Reload(true) option does. It starts on `Build` and stops on `Shutdown`.
+//
+// When the application is in debug mode (see `IsDebug`) the rendered HTML pages
+// get a script which refreshes the browser page after each reload.
+//
+// See `ReloadViews` to reload templates manually.
+func (app *Application) WatchViews(interval time.Duration) *Application {
+	app.viewWatch = true
+	app.viewWatchInterval = interval
+	return app
+}
+
+// ReloadViews reloads the "names" templates of the registered view engine
+// and the templates that depend on them. On failure the previous templates keep serving.
+// The browsers are refreshed on success, see `WatchViews`.
+func (app *Application) ReloadViews(names ...string) error {
+	return app.view.ReloadTemplates(names...)
+}
+
+// CompileViews validates and parses all the templates of the registered view engine,
+// it is designed to run at "go generate" time, see the `view.Compile` function.
+// The framework's template functions, e.g. tr, url and urlpath, are registered first,
+// so the templates that use them can be parsed.
+//
+// Example Code:
+//
+//	report, err := app.CompileViews()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	// Write a views.go file which embeds exactly the verified templates,
+//	// fails with all the template errors and their lines otherwise.
+//	if err = report.WriteEmbedFile("./views/views.go", "views"); err != nil {
+//		log.Fatal(err)
+//	}
+func (app *Application) CompileViews() (*view.CompileReport, error) {
+	if !app.view.Registered() {
+		return nil, fmt.Errorf("no view engine was registered")
+	}
+
+	app.view.Funcs(template.FuncMap{
+		"tr":                 app.I18n.Tr,
+		"formatNumber":       app.I18n.FormatNumber,
+		"formatPercent":      app.I18n.FormatPercent,
+		"formatCurrency":     app.I18n.FormatCurrency,
+		"formatDate":         app.I18n.FormatDate,
+		"formatTime":         app.I18n.FormatTime,
+		"formatRelativeTime": app.I18n.FormatRelativeTime,
+		"urlpath":            router.NewRoutePathReverser(app.APIBuilder).Path,
+		"url":                app.viewURL,
+	})
+
+	return view.Compile(app.view.Engine)
+}
+
 // View executes and writes the result of a template file to the writer.
 //
 // First parameter is the writer to write the parsed template.
@@ -431,6 +494,24 @@ func (app *Application) View(writer io.Writer, filename string, layout string, b
 	return app.view.ExecuteWriter(writer, filename, layout, bindingData)
 }
 
+// ViewBlock executes and writes the result of a single block of a template file to the writer,
+// without a layout. The registered view engine should support blocks,
+// see `view.EngineBlockExecutor`.
+//
+// Use context.ViewBlock to render template blocks to the client instead.
+// Returns an error on failure, otherwise nil.
+func (app *Application) ViewBlock(writer io.Writer, filename string, block string, bindingData interface{}) error {
+	if !app.view.Registered() {
+		err := errors.New("view engine is missing, use `RegisterView`")
+		app.logger.Error(err)
+		return err
+	}
+
+	return app.view.ExecuteBlock(writer, filename, block, bindingData)
+}
+
+var _ context.ApplicationViewBlocker = (*Application)(nil)
+
 // GetContextPool returns the Iris sync.Pool which holds the contexts values.
 // Iris automatically releases the request context, so you don't have to use it.
 // It's only useful to manually release the context on cases that connection
@@ -644,6 +725,10 @@ func (app *Application) Shutdown(ctx stdContext.Context) error {
 	defer app.mu.Unlock()
 	defer app.setRunError(ErrServerClosed) // make sure to set the error so any .Wait calls return.
 
+	if app.viewWatchCancel != nil {
+		app.viewWatchCancel()
+	}
+
 	for i, su := range app.Hosts {
 		app.logger.Debugf("Host[%d]: Shutdown now", i)
 		if err := su.Shutdown(ctx); err != nil {
@@ -728,6 +813,14 @@ func (app *Application) Build() error {
 	if app.I18n.Loaded() {
 		// {{ tr "lang" "key" arg1 arg2 }}
 		app.view.AddFunc("tr", app.I18n.Tr)
+		// {{ formatNumber "lang" 1234.5 }}, {{ formatCurrency "lang" 9.99 "EUR" }},
+		// {{ formatDate "lang" .Time "long" }}, {{ formatRelativeTime "lang" .Time }}...
+		app.view.AddFunc("formatNumber", app.I18n.FormatNumber)
+		app.view.AddFunc("formatPercent", app.I18n.FormatPercent)
+		app.view.AddFunc("formatCurrency", app.I18n.FormatCurrency)
+		app.view.AddFunc("formatDate", app.I18n.FormatDate)
+		app.view.AddFunc("formatTime", app.I18n.FormatTime)
+		app.view.AddFunc("formatRelativeTime", app.I18n.FormatRelativeTime)
 		app.Router.PrependRouterWrapper(app.I18n.Wrapper())
 	}
 
@@ -738,10 +831,37 @@ func (app *Application) Build() error {
 		// Each engine has their defaults, i.e yield,render,render_r,partial, params...
 		rv := router.NewRoutePathReverser(app.APIBuilder)
 		app.view.AddFunc("urlpath", rv.Path)
-		// app.view.AddFunc("url", rv.URL)
+		// {{ url "routename" "path" "values" "here" }}
+		app.view.AddFunc("url", app.viewURL)
 		if err := app.view.Load(); err != nil {
 			return fmt.Errorf("build: view engine: %v", err)
 		}
+
+		if app.viewWatch {
+			if app.view.OnReload == nil {
+				app.view.OnReload = func(names []string, err error) {
+					if err != nil {
+						app.logger.Errorf("view engine: reload: %v", err)
+						return
+					}
+
+					app.logger.Debugf("View engine: reloaded: %s", strings.Join(names, ", "))
+				}
+			}
+
+			ctx, cancel := stdContext.WithCancel(stdContext.Background())
+			if err := app.view.Watch(ctx, app.viewWatchInterval); err != nil {
+				cancel()
+				return fmt.Errorf("build: view engine: %w", err)
+			}
+			app.viewWatchCancel = cancel
+		}
+	}
+
+	if mode := app.config.RouteConflicts; mode != "" {
+		if err := app.analyzeRoutes(mode); err != nil {
+			return fmt.Errorf("build: %w", err)
+		}
 	}
 
 	if !app.Router.Downgraded() {
@@ -750,6 +870,10 @@ func (app *Application) Build() error {
 			return fmt.Errorf("build: inject live reload: failed: %v", err)
 		}
 
+		if app.viewWatch && app.view.Registered() && app.IsDebug() {
+			app.injectViewLiveReload()
+		}
+
 		if app.config.ForceLowercaseRouting {
 			// This should always be executed first.
 			app.Router.PrependRouterWrapper(func(w http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
@@ -800,6 +924,172 @@ func (app *Application) Build() error {
 	return nil
 }
 
+// injectViewLiveReload registers the view.LiveReloadPath route
+// and injects the view.LiveReloadScript on every HTML response,
+// so the browsers are refreshed after the templates are reloaded.
+func (app *Application) injectViewLiveReload() {
+	app.Get(view.LiveReloadPath, app.view.LiveReloadHandler)
+
+	app.UseRouter(func(ctx Context) {
+		if ctx.Path() == view.LiveReloadPath { // do not touch the event stream.
+			ctx.Next()
+			return
+		}
+
+		if _, recording := ctx.IsRecording(); recording { // the response is buffered anyway.
+			injectHTMLScript(ctx, view.LiveReloadScript)
+			return
+		}
+
+		// Do not record the whole response, so streamed pages
+		// (see view.HTML.Stream) are still flushed to the client while they are rendered.
+		ctx.ResetResponseWriter(&scriptResponseWriter{
+			ResponseWriter: ctx.ResponseWriter(),
+			script:         view.LiveReloadScript,
+		})
+		ctx.Next()
+	})
+}
+
+// scriptResponseWriter writes its script right before the first </body>
+// of an HTML response, as the response is written.
+// Unlike injectHTMLScript, it does not add the script to pages without a </body> tag.
+type scriptResponseWriter struct {
+	context.ResponseWriter
+	script []byte
+
+	checked bool // true after the first write.
+	done    bool // true when the script is written or the response is not an HTML one.
+}
+
+func (w *scriptResponseWriter) Write(p []byte) (int, error) {
+	if !w.checked {
+		w.checked = true
+
+		// The Content-Length cannot be changed and a compressed body cannot be modified.
+		h := w.Header()
+		w.done = !strings.HasPrefix(h.Get(context.ContentTypeHeaderKey), "text/html") ||
+			h.Get(context.ContentLengthHeaderKey) != "" || h.Get(context.ContentEncodingHeaderKey) != ""
+	}
+
+	if w.done {
+		return w.ResponseWriter.Write(p)
+	}
+
+	idx := bytes.Index(p, bodyCloseTag)
+	if idx == -1 {
+		return w.ResponseWriter.Write(p)
+	}
+
+	w.done = true
+
+	n, err := w.ResponseWriter.Write(p[:idx])
+	if err != nil {
+		return n, err
+	}
+
+	if _, err = w.ResponseWriter.Write(w.script); err != nil {
+		return n, err
+	}
+
+	m, err := w.ResponseWriter.Write(p[idx:])
+	return n + m, err
+}
+
+// URL returns the absolute URL of a named route, e.g. https://admin.mydomain.com/users/42.
+// The scheme and the host are resolved by the Configuration.VHost field
+// which is automatically set on Listen, see RequestURL to resolve them
+// through the current request instead.
+//
+// The "paramValues" are validated against the route's path parameter types (macros),
+// if the route is registered on a wildcard subdomain then the first value is the subdomain part.
+// A last url.Values or map[string]string value is encoded as the URL's query string.
+// See router.RoutePathReverser.BuildURL for more.
+//
+// The same function is registered as the "url" template function
+// on the registered view engine, e.g. {{ url "user" 42 }}.
+func (app *Application) URL(routeName string, paramValues ...interface{}) (string, error) {
+	vhost := app.config.GetVHost()
+	if vhost == "" {
+		return "", fmt.Errorf("url: %s: empty VHost configuration field, use the RequestURL method instead", routeName)
+	}
+
+	return router.NewRoutePathReverser(app.APIBuilder).
+		BuildURL(netutil.ResolveSchemeFromVHost(vhost), vhost, routeName, paramValues...)
+}
+
+// RequestURL same as URL but it resolves the scheme and the host
+// through the current request, respecting the Configuration.HostProxyHeaders.
+// The current request's subdomain, if any, is replaced by the route's one.
+func (app *Application) RequestURL(ctx *context.Context, routeName string, paramValues ...interface{}) (string, error) {
+	scheme := strings.TrimSuffix(ctx.Scheme(), "://")
+	host := strings.TrimPrefix(ctx.Host(), ctx.SubdomainFull())
+
+	return router.NewRoutePathReverser(app.APIBuilder).BuildURL(scheme, host, routeName, paramValues...)
+}
+
+// viewURL is the "url" template function, see URL.
+func (app *Application) viewURL(routeName string, paramValues ...interface{}) string {
+	u, err := app.URL(routeName, paramValues...)
+	if err != nil {
+		app.logger.Errorf("view: url: %v", err)
+		return ""
+	}
+
+	return u
+}
+
+// analyzeRoutes logs or returns the ambiguous, unreachable and shadowed routes,
+// see the Configuration.RouteConflicts field.
+func (app *Application) analyzeRoutes(mode string) error {
+	conflicts := router.AnalyzeRoutes(app.APIBuilder.GetRoutes())
+	if len(conflicts) == 0 {
+		return nil
+	}
+
+	switch mode {
+	case "warn":
+		for _, c := range conflicts {
+			app.logger.Warn(c.String())
+		}
+		return nil
+	case "error":
+		var b strings.Builder
+		fmt.Fprintf(&b, "routes: %d conflicts found", len(conflicts))
+		for _, c := range conflicts {
+			b.WriteString("\n\t")
+			b.WriteString(c.String())
+		}
+		return errors.New(b.String())
+	default:
+		return fmt.Errorf("routes: unknown route conflicts mode: %q", mode)
+	}
+}
+
+// RoutesCommandFlag is the command-line flag which, when the binary
+// is executed with it, prints the application's routes after Build and exits,
+// instead of starting the server. Its value is the output format:
+// "table" (default), "json" or "csv", e.g. --iris-routes=json.
+//
+// See router.WriteRouteTable too.
+const RoutesCommandFlag = "--iris-routes"
+
+// routesCommand reports whether the RoutesCommandFlag exists
+// in the command-line "args" and returns its format.
+func routesCommand(args []string) (string, bool) {
+	for _, arg := range args {
+		if arg == RoutesCommandFlag {
+			return router.RouteTableText, true
+		}
+
+		if format, ok := strings.CutPrefix(arg, RoutesCommandFlag+"="); ok {
+			return format, true
+		}
+	}
+
+	return "", false
+}
+
 // Runner is just an interface which accepts the framework instance
 // and returns an error.
 //
@@ -1055,6 +1345,16 @@ func (app *Application) Run(serve Runner, withOrWithout ...Configurator) error {
 		return err
 	}
 
+	if format, ok := routesCommand(os.Args[1:]); ok {
+		// Print the routes and exit, e.g. ./myapp --iris-routes=json > routes.json.
+		if err := router.WriteRouteTable(os.Stdout, app.GetRoutes(), format); err != nil {
+			app.logger.Error(err)
+			return err
+		}
+
+		os.Exit(0)
+	}
+
 	app.ConfigureHost(func(host *Supervisor) {
 		host.SocketSharding = app.config.SocketSharding
 		host.KeepAlive = app.config.KeepAlive
@@ -0,0 +1,109 @@
+package iris
+
+import (
+	"encoding/json"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/kataras/iris/v12/core/router"
+	"github.com/kataras/iris/v12/view"
+)
+
+func TestRoutesCommand(t *testing.T) {
+	tests := []struct {
+		args   []string
+		format string
+		ok     bool
+	}{
+		{[]string{"--port=8080"}, "", false},
+		{[]string{RoutesCommandFlag}, router.RouteTableText, true},
+		{[]string{"--port=8080", RoutesCommandFlag + "=json"}, router.RouteTableJSON, true},
+		{[]string{RoutesCommandFlag + "=csv"}, router.RouteTableCSV, true},
+	}
+
+	for i, tt := range tests {
+		format, ok := routesCommand(tt.args)
+		if format != tt.format || ok != tt.ok {
+			t.Fatalf("[%d] expected: %q, %v but got: %q, %v", i, tt.format, tt.ok, format, ok)
+		}
+	}
+}
+
+// TestRunRoutesCommand runs the test binary itself with the RoutesCommandFlag
+// because the Run method exits the process after printing the routes.
+func TestRunRoutesCommand(t *testing.T) {
+	if os.Getenv("IRIS_TEST_ROUTES_COMMAND") == "1" {
+		app := New()
+		app.Get("/users/{id:uint64}", func(ctx Context) {}).Name = "user"
+		app.Listen(":0", WithoutStartupLog)
+		os.Exit(1) // should never reach here.
+	}
+
+	run := func(arg string) string {
+		t.Helper()
+
+		cmd := exec.Command(os.Args[0], "-test.run=^TestRunRoutesCommand$", "--", arg)
+		cmd.Env = append(os.Environ(), "IRIS_TEST_ROUTES_COMMAND=1")
+		out, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("%s: %v: %s", arg, err, out)
+		}
+
+		return string(out)
+	}
+
+	if out := run(RoutesCommandFlag); !strings.HasPrefix(out, "METHOD") || !strings.Contains(out, "/users/{id:uint64}") {
+		t.Fatalf("unexpected table output:\n%s", out)
+	}
+
+	var table []router.RouteTableEntry
+	if out := run(RoutesCommandFlag + "=json"); json.Unmarshal([]byte(out), &table) != nil ||
+		len(table) != 1 || table[0].Path != "/users/{id:uint64}" || table[0].Name != "user" {
+		t.Fatalf("unexpected json output:\n%s", out)
+	}
+
+	out := run(RoutesCommandFlag + "=csv")
+	if lines := strings.Split(strings.TrimSpace(out), "\n"); len(lines) != 2 ||
+		!strings.HasPrefix(lines[0], "method,") || !strings.HasPrefix(lines[1], "GET,,/users/{id:uint64},user,") {
+		t.Fatalf("unexpected csv output:\n%s", out)
+	}
+}
+
+func TestViewLiveReloadStream(t *testing.T) {
+	app := New()
+	app.RegisterView(view.HTML(fstest.MapFS{
+		"index.html": {Data: []byte(`<html><head></head><body>{{ async "slow.html" . }}</body></html>`)},
+		"slow.html":  {Data: []byte(`slow`)},
+	}, ".html").Stream(true))
+	app.injectViewLiveReload()
+
+	recording := false
+	app.Get("/", func(ctx Context) {
+		_, recording = ctx.IsRecording()
+		ctx.View("index.html")
+	})
+
+	if err := app.Build(); err != nil {
+		t.Fatal(err)
+	}
+
+	w := httptest.NewRecorder()
+	app.ServeHTTP(w, httptest.NewRequest("GET", "/", nil))
+
+	if recording || !w.Flushed {
+		t.Fatalf("expected a streamed response but got recording: %v, flushed: %v", recording, w.Flushed)
+	}
+
+	body := w.Body.String()
+	if idx := strings.Index(body, string(view.LiveReloadScript)); idx == -1 || idx != strings.Index(body, "</body>")-len(view.LiveReloadScript) {
+		t.Fatalf("expected the live reload script right before </body> but got:\n%s", body)
+	}
+
+	if !strings.Contains(body, "slow") {
+		t.Fatalf("expected the async block to be streamed but got:\n%s", body)
+	}
+}
@@ -117,9 +117,17 @@ func (l *Lexer) NextDynamicToken() (t token.Token) {
 	// calculate anything, even spaces.
 
 	// numbers
+	pos, readPos, ch := l.pos, l.readPos, l.ch
 	lit := l.readNumber()
 	if lit != "" {
-		return l.newToken(token.INT, lit)
+		switch resolveTokenType(l.ch) {
+		case token.COMMA, token.RPAREN, token.EOF:
+			return l.newToken(token.INT, lit)
+		}
+
+		// not a number but an argument which starts with digits,
+		// e.g. 2020-01-01 or 1.2.0, read it as a whole.
+		l.pos, l.readPos, l.ch = pos, readPos, ch
 	}
 
 	lit = l.readIdentifierFuncArgument()
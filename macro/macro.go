@@ -124,6 +124,7 @@ func convertBuilderFunc(fn interface{}) ParamFuncBuilder {
 	}
 
 	numFields := typFn.NumIn()
+	variadic := typFn.IsVariadic()
 
 	panicIfErr := func(i int, err error) {
 		if err != nil {
@@ -132,13 +133,28 @@ func convertBuilderFunc(fn interface{}) ParamFuncBuilder {
 	}
 
 	return func(args []string) reflect.Value {
-		if len(args) != numFields {
-			// no variadics support, for now.
+		if len(args) < numFields || variadic {
+			// the parser keeps non-numeric arguments as a whole,
+			// e.g. range(2020-01-01,2020-12-31) or enum(a,b,c), split them.
+			args = splitArgs(args)
+		}
+
+		if variadic {
+			// the last input argument can accept zero or more values, e.g. enum(a,b,c).
+			if len(args) < numFields-1 {
+				panic(fmt.Sprintf("args(len=%d) should be at least the len of numFields(%d)-1 for: %s", len(args), numFields, typFn))
+			}
+		} else if len(args) != numFields {
 			panic(fmt.Sprintf("args(len=%d) should be the same len as numFields(%d) for: %s", len(args), numFields, typFn))
 		}
 		var argValues []reflect.Value
-		for i := 0; i < numFields; i++ {
-			field := typFn.In(i)
+		for i := 0; i < len(args); i++ {
+			var field reflect.Type
+			if variadic && i >= numFields-1 {
+				field = typFn.In(numFields - 1).Elem()
+			} else {
+				field = typFn.In(i)
+			}
 			arg := args[i]
 
 			// try to convert the string literal as we get it from the parser.
@@ -235,6 +251,24 @@ func convertBuilderFunc(fn interface{}) ParamFuncBuilder {
 	}
 }
 
+// splitArgs splits comma separated function arguments.
+func splitArgs(args []string) []string {
+	var result []string
+	for _, arg := range args {
+		if len(arg) > 1 && arg[0] == '[' && arg[len(arg)-1] == ']' {
+			// it's a slice argument, keep it as it's.
+			result = append(result, arg)
+			continue
+		}
+
+		for _, s := range strings.Split(arg, ",") {
+			result = append(result, strings.TrimSpace(s))
+		}
+	}
+
+	return result
+}
+
 type (
 	// Macro represents the parsed macro,
 	// which holds
@@ -1,6 +1,7 @@
 package macro
 
 import (
+	"math/big"
 	"reflect"
 	"strconv"
 	"testing"
@@ -445,7 +446,7 @@ func TestUUIDEvaluatorRaw(t *testing.T) {
 		{false, "/assets/main.css"},                // 6
 	}
 	for i, tt := range tests {
-		testEvaluatorRaw(t, UUID, tt.input, reflect.String, tt.pass, i)
+		testEvaluatorRaw(t, UUID, tt.input, reflect.Array, tt.pass, i) // uuid.UUID.
 	}
 }
 
@@ -544,6 +545,172 @@ func TestWeekdayEvaluatorRaw(t *testing.T) {
 	}
 }
 
+func TestULIDEvaluatorRaw(t *testing.T) {
+	tests := []struct {
+		pass  bool
+		input string
+	}{
+		{true, "01ARZ3NDEKTSV4RRFFQ69G5FAV"},   // 0
+		{true, "01arz3ndektsv4rrffq69g5fav"},   // 1
+		{false, "81ARZ3NDEKTSV4RRFFQ69G5FAV"},  // 2 overflow
+		{false, "01ARZ3NDEKTSV4RRFFQ69G5FAU"},  // 3 U is not part of the alphabet
+		{false, "01ARZ3NDEKTSV4RRFFQ69G5FA"},   // 4
+		{false, "01ARZ3NDEKTSV4RRFFQ69G5FAVV"}, // 5
+	}
+	for i, tt := range tests {
+		testEvaluatorRaw(t, ULID, tt.input, reflect.String, tt.pass, i)
+	}
+}
+
+func TestSemverEvaluatorRaw(t *testing.T) {
+	tests := []struct {
+		pass  bool
+		input string
+	}{
+		{true, "1.0.0"},          // 0
+		{true, "v2.1.3"},         // 1
+		{true, "1.0.0-beta.1"},   // 2
+		{false, "1.0.0.0"},       // 3
+		{false, "version"},       // 4
+		{false, "1.0.0-beta..1"}, // 5
+	}
+	for i, tt := range tests {
+		testEvaluatorRaw(t, Semver, tt.input, reflect.Struct, tt.pass, i)
+	}
+}
+
+func TestBase64URLEvaluatorRaw(t *testing.T) {
+	tests := []struct {
+		pass  bool
+		input string
+	}{
+		{true, "aXJpcw"},   // 0
+		{true, "aXJpcw=="}, // 1
+		{true, "-_-_"},     // 2
+		{false, "aXJp+w"},  // 3
+		{false, "aXJp/w"},  // 4
+		{false, "a"},       // 5
+		{false, ""},        // 6
+	}
+	for i, tt := range tests {
+		testEvaluatorRaw(t, Base64URL, tt.input, reflect.String, tt.pass, i)
+	}
+}
+
+func TestHexEvaluatorRaw(t *testing.T) {
+	tests := []struct {
+		pass  bool
+		input string
+	}{
+		{true, "deadbeef"}, // 0
+		{true, "DEADBEEF"}, // 1
+		{false, "dead0"},   // 2
+		{false, "zz"},      // 3
+		{false, ""},        // 4
+	}
+	for i, tt := range tests {
+		testEvaluatorRaw(t, Hex, tt.input, reflect.String, tt.pass, i)
+	}
+}
+
+func TestDecimalEvaluatorRaw(t *testing.T) {
+	tests := []struct {
+		pass     bool
+		input    string
+		expected string
+	}{
+		{true, "19.99", "19.99"}, // 0
+		{true, "-0.10", "-0.10"}, // 1
+		{true, "100", "100.00"},  // 2
+		{false, "1e3", ""},       // 3
+		{false, "1/3", ""},       // 4
+		{false, "19.", ""},       // 5
+		{false, "nineteen", ""},  // 6
+	}
+	for i, tt := range tests {
+		testEvaluatorRaw(t, Decimal, tt.input, reflect.Ptr, tt.pass, i)
+
+		if v, ok := Decimal.Evaluator(tt.input); ok {
+			if expected, got := tt.expected, v.(*big.Rat).FloatString(2); expected != got {
+				t.Fatalf("[%d] expected: %s but got: %s", i, expected, got)
+			}
+		}
+	}
+}
+
+func TestDurationEvaluatorRaw(t *testing.T) {
+	tests := []struct {
+		pass     bool
+		input    string
+		expected time.Duration
+	}{
+		{true, "1h30m", 90 * time.Minute}, // 0
+		{true, "15s", 15 * time.Second},   // 1
+		{false, "15", 0},                  // 2
+		{false, "1d", 0},                  // 3
+	}
+	for i, tt := range tests {
+		testEvaluatorRaw(t, Duration, tt.input, reflect.Int64, tt.pass, i)
+
+		if v, ok := Duration.Evaluator(tt.input); ok {
+			if expected, got := tt.expected, v.(time.Duration); expected != got {
+				t.Fatalf("[%d] expected: %s but got: %s", i, expected, got)
+			}
+		}
+	}
+}
+
+func TestParamFuncs(t *testing.T) {
+	tests := []struct {
+		tmpl  string
+		input string
+		pass  bool
+	}{
+		{"/{lang:string enum(en,el,de)}", "el", true},                   // 0
+		{"/{lang:string enum(en,el,de)}", "fr", false},                  // 1
+		{"/{lang enum(en)}", "en", true},                                // 2
+		{"/{d:date after(2020-01-01)}", "2020/01/02", true},             // 3
+		{"/{d:date after(2020-01-01)}", "2020/01/01", false},            // 4
+		{"/{d:date before(2020-01-01)}", "2019/12/31", true},            // 5
+		{"/{d:date range(2020-01-01,2020-12-31)}", "2021/01/01", false}, // 6
+		{"/{v:semver min(1.2.0)}", "1.10.0", true},                      // 7
+		{"/{v:semver max(1.2.0)}", "1.10.0", false},                     // 8
+		{"/{amount:decimal range(0.01,100)}", "99.99", true},            // 9
+		{"/{amount:decimal min(0.01)}", "0.001", false},                 // 10
+		{"/{ttl:duration max(1h)}", "30m", true},                        // 11
+		{"/{ttl:duration min(1m)}", "30s", false},                       // 12
+		{"/{v:semver min(v1.2)}", "1.2.0", true},                        // 13
+		{"/{v:semver max(v1.2)}", "v1.3", false},                        // 14
+	}
+
+	for i, tt := range tests {
+		tmpl, err := Parse(tt.tmpl, *Defaults)
+		if err != nil {
+			t.Fatalf("[%d] %s: %v", i, tt.tmpl, err)
+		}
+
+		if len(tmpl.Params) != 1 || len(tmpl.Params[0].Funcs)+len(tmpl.Params[0].stringInFuncs) == 0 {
+			t.Fatalf("[%d] %s: expected one parameter with one function", i, tt.tmpl)
+		}
+
+		if _, passed := tmpl.Params[0].Eval(tt.input); passed != tt.pass {
+			t.Fatalf("[%d] %s: expected[pass] %v for %s but got %v", i, tt.tmpl, tt.pass, tt.input, passed)
+		}
+	}
+}
+
+func TestParamFuncsInvalidArguments(t *testing.T) {
+	for i, tmpl := range []string{
+		"/{v:semver min(invalid)}",
+		"/{d:date after(2020)}",
+		"/{id:int min(abc)}",
+	} {
+		if _, err := Parse(tmpl, *Defaults); err == nil {
+			t.Fatalf("[%d] %s: expected an error", i, tmpl)
+		}
+	}
+}
+
 func TestConvertBuilderFunc(t *testing.T) {
 	fn := func(min uint64, slice []string) func(string) bool {
 		return func(paramValue string) bool {
@@ -1,8 +1,11 @@
 package macro
 
 import (
+	"encoding/base64"
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"math/big"
 	"net"
 	"net/mail"
 	"strconv"
@@ -11,6 +14,7 @@ import (
 
 	"github.com/kataras/iris/v12/macro/interpreter/ast"
 
+	"github.com/blang/semver/v4"
 	"github.com/google/uuid"
 )
 
@@ -73,6 +77,19 @@ var (
 					}
 				}
 
+				return false
+			}
+		}).
+		// checks if param value's matches one of the given values,
+		// e.g. {lang:string enum(en,el,de)}.
+		RegisterFunc("enum", func(values ...string) func(string) bool {
+			return func(paramValue string) bool {
+				for _, s := range values {
+					if paramValue == s {
+						return true
+					}
+				}
+
 				return false
 			}
 		})
@@ -412,15 +429,16 @@ var (
 	// Should be living in the latest path segment of a route path.
 	Path = NewMacro("path", "", "", false, true, nil)
 
-	// UUID string type for validating a uuidv4 (and v1) path parameter.
+	// UUID type for validating a uuidv4 (and v1) path parameter.
+	// Its value is stored as uuid.UUID, use ctx.Params().Get to retrieve its string form.
 	// Read more at: https://tools.ietf.org/html/rfc4122.
-	UUID = NewMacro("uuid", "uuidv4", "", false, false, func(paramValue string) (interface{}, bool) {
-		_, err := uuid.Parse(paramValue) // this is x10+ times faster than regexp.
+	UUID = NewMacro("uuid", "uuidv4", uuid.UUID{}, false, false, func(paramValue string) (interface{}, bool) {
+		id, err := uuid.Parse(paramValue) // this is x10+ times faster than regexp.
 		if err != nil {
 			return err, false
 		}
 
-		return paramValue, true
+		return id, true
 	})
 
 	// Email string type for validating an e-mail path parameter. It returns the address as string, instead of an *mail.Address.
@@ -458,6 +476,9 @@ var (
 	})
 
 	simpleDateLayout = "2006/01/02"
+	// dateArgLayout is the layout of the date macro's function arguments,
+	// slashes are not allowed inside a route's path segment.
+	dateArgLayout = "2006-01-02"
 
 	// Date type.
 	Date = NewMacro("date", "", time.Time{}, false, true, func(paramValue string) (interface{}, bool) {
@@ -467,7 +488,29 @@ var (
 		}
 
 		return tt, true
-	})
+	}).
+		// checks if the param value's date is after the 'date' (YYYY-MM-DD) argument,
+		// e.g. {d:date after(2020-01-01)}.
+		RegisterFunc("after", func(date string) func(time.Time) bool {
+			after := mustParseDateArg(date)
+			return func(paramValue time.Time) bool {
+				return paramValue.After(after)
+			}
+		}).
+		// checks if the param value's date is before the 'date' (YYYY-MM-DD) argument.
+		RegisterFunc("before", func(date string) func(time.Time) bool {
+			before := mustParseDateArg(date)
+			return func(paramValue time.Time) bool {
+				return paramValue.Before(before)
+			}
+		}).
+		// checks if the param value's date is between min and max (YYYY-MM-DD), including 'min' and 'max'.
+		RegisterFunc("range", func(min, max string) func(time.Time) bool {
+			minDate, maxDate := mustParseDateArg(min), mustParseDateArg(max)
+			return func(paramValue time.Time) bool {
+				return !(paramValue.Before(minDate) || paramValue.After(maxDate))
+			}
+		})
 
 	// ErrParamNotWeekday is fired when the parameter value is not a form of a time.Weekday.
 	ErrParamNotWeekday = errors.New("parameter is not a valid weekday")
@@ -511,6 +554,145 @@ var (
 		return d, true
 	})
 
+	// ErrParamNotULID is fired when the parameter value is not a valid ULID.
+	ErrParamNotULID = errors.New("parameter is not a valid ULID")
+	// ULID string type for validating a Universally Unique Lexicographically Sortable Identifier,
+	// 26 characters of Crockford's base32 (case-insensitive).
+	// Read more at: https://github.com/ulid/spec.
+	ULID = NewMacro("ulid", "", "", false, false, func(paramValue string) (interface{}, bool) {
+		if !isULID(paramValue) {
+			return fmt.Errorf("%s: %w", paramValue, ErrParamNotULID), false
+		}
+
+		return paramValue, true
+	})
+
+	// Semver type, returns a type of semver.Version.
+	// Read more at: https://semver.org.
+	Semver = NewMacro("semver", "", semver.Version{}, false, false, func(paramValue string) (interface{}, bool) {
+		v, err := semver.ParseTolerant(paramValue)
+		if err != nil {
+			return fmt.Errorf("%s: %w", paramValue, err), false
+		}
+
+		return v, true
+	}).
+		// checks if the param value's version is greater or equal than 'min'.
+		RegisterFunc("min", func(min string) func(semver.Version) bool {
+			minVersion := mustParseSemverArg(min)
+			return func(paramValue semver.Version) bool {
+				return paramValue.GTE(minVersion)
+			}
+		}).
+		// checks if the param value's version is smaller or equal than 'max'.
+		RegisterFunc("max", func(max string) func(semver.Version) bool {
+			maxVersion := mustParseSemverArg(max)
+			return func(paramValue semver.Version) bool {
+				return paramValue.LTE(maxVersion)
+			}
+		})
+
+	// ErrParamNotBase64URL is fired when the parameter value is not a valid base64 URL-encoded text.
+	ErrParamNotBase64URL = errors.New("parameter is not a valid base64url")
+	// Base64URL string type for validating a base64 URL-encoded (RFC 4648) path parameter,
+	// padded or not. The value is kept as it is, decode it with the base64.RawURLEncoding.
+	Base64URL = NewMacro("base64url", "", "", false, false, func(paramValue string) (interface{}, bool) {
+		if _, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(paramValue, "=")); err != nil || paramValue == "" {
+			return fmt.Errorf("%s: %w", paramValue, ErrParamNotBase64URL), false
+		}
+
+		return paramValue, true
+	})
+
+	// ErrParamNotHex is fired when the parameter value is not a valid hexadecimal text.
+	ErrParamNotHex = errors.New("parameter is not a valid hex")
+	// Hex string type for validating a hexadecimal encoded path parameter, e.g. a sha256 sum.
+	Hex = NewMacro("hex", "", "", false, false, func(paramValue string) (interface{}, bool) {
+		if _, err := hex.DecodeString(paramValue); err != nil || paramValue == "" {
+			return fmt.Errorf("%s: %w", paramValue, ErrParamNotHex), false
+		}
+
+		return paramValue, true
+	})
+
+	// ErrParamNotDecimal is fired when the parameter value is not a valid decimal number.
+	ErrParamNotDecimal = errors.New("parameter is not a valid decimal")
+	decimalEval        = MustRegexp(`^[-+]?[0-9]+(\.[0-9]+)?$`)
+	// Decimal type, returns an arbitrary-precision *big.Rat
+	// so monetary values like "19.99" are kept exact.
+	// Exponents and fractions are not accepted, only plain decimals.
+	Decimal = NewMacro("decimal", "", new(big.Rat), false, false, func(paramValue string) (interface{}, bool) {
+		if !decimalEval(paramValue) {
+			return fmt.Errorf("%s: %w", paramValue, ErrParamNotDecimal), false
+		}
+
+		v, ok := new(big.Rat).SetString(paramValue)
+		if !ok {
+			return fmt.Errorf("%s: %w", paramValue, ErrParamNotDecimal), false
+		}
+
+		return v, true
+	}).
+		// checks if the param value's decimal representation is
+		// bigger or equal than 'min'.
+		RegisterFunc("min", func(min string) func(*big.Rat) bool {
+			minValue := mustParseDecimalArg(min)
+			return func(paramValue *big.Rat) bool {
+				return paramValue.Cmp(minValue) >= 0
+			}
+		}).
+		// checks if the param value's decimal representation is
+		// smaller or equal than 'max'.
+		RegisterFunc("max", func(max string) func(*big.Rat) bool {
+			maxValue := mustParseDecimalArg(max)
+			return func(paramValue *big.Rat) bool {
+				return paramValue.Cmp(maxValue) <= 0
+			}
+		}).
+		// checks if the param value's decimal representation is
+		// between min and max, including 'min' and 'max'.
+		RegisterFunc("range", func(min, max string) func(*big.Rat) bool {
+			minValue, maxValue := mustParseDecimalArg(min), mustParseDecimalArg(max)
+			return func(paramValue *big.Rat) bool {
+				return paramValue.Cmp(minValue) >= 0 && paramValue.Cmp(maxValue) <= 0
+			}
+		})
+
+	// Duration type, returns a type of time.Duration, e.g. "1h30m".
+	// Read more at go std time.ParseDuration function.
+	Duration = NewMacro("duration", "", time.Duration(0), false, false, func(paramValue string) (interface{}, bool) {
+		d, err := time.ParseDuration(paramValue)
+		if err != nil {
+			return fmt.Errorf("%s: %w", paramValue, err), false
+		}
+
+		return d, true
+	}).
+		// checks if the param value's duration is
+		// bigger or equal than 'min', e.g. min(1s).
+		RegisterFunc("min", func(min string) func(time.Duration) bool {
+			minDuration := mustParseDurationArg(min)
+			return func(paramValue time.Duration) bool {
+				return paramValue >= minDuration
+			}
+		}).
+		// checks if the param value's duration is
+		// smaller or equal than 'max', e.g. max(24h).
+		RegisterFunc("max", func(max string) func(time.Duration) bool {
+			maxDuration := mustParseDurationArg(max)
+			return func(paramValue time.Duration) bool {
+				return paramValue <= maxDuration
+			}
+		}).
+		// checks if the param value's duration is
+		// between min and max, including 'min' and 'max'.
+		RegisterFunc("range", func(min, max string) func(time.Duration) bool {
+			minDuration, maxDuration := mustParseDurationArg(min), mustParseDurationArg(max)
+			return func(paramValue time.Duration) bool {
+				return !(paramValue < minDuration || paramValue > maxDuration)
+			}
+		})
+
 	// Defaults contains the defaults macro and parameters types for the router.
 	//
 	// Read https://github.com/kataras/iris/tree/main/_examples/routing/macros for more details.
@@ -535,9 +717,85 @@ var (
 		Email,
 		Date,
 		Weekday,
+		ULID,
+		Semver,
+		Base64URL,
+		Hex,
+		Decimal,
+		Duration,
 	}
 )
 
+// crockfordAlphabet is the Crockford's base32 alphabet used by the ULID spec.
+const crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+// isULID reports whether "s" is a valid ULID text representation.
+func isULID(s string) bool {
+	if len(s) != 26 {
+		return false
+	}
+
+	// the first character can't be bigger than 7, otherwise it overflows the 128 bits.
+	if s[0] > '7' {
+		return false
+	}
+
+	for i := 0; i < len(s); i++ {
+		if strings.IndexByte(crockfordAlphabet, upperASCII(s[i])) == -1 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func upperASCII(c byte) byte {
+	if 'a' <= c && c <= 'z' {
+		return c - ('a' - 'A')
+	}
+
+	return c
+}
+
+// the below helpers run on route registration,
+// like the rest of the macro functions they panic on invalid input arguments.
+
+func mustParseDateArg(s string) time.Time {
+	tt, err := time.Parse(dateArgLayout, s)
+	if err != nil {
+		panic(fmt.Sprintf("date: invalid argument: %s: expected %s layout: %v", s, dateArgLayout, err))
+	}
+
+	return tt
+}
+
+func mustParseSemverArg(s string) semver.Version {
+	v, err := semver.ParseTolerant(s)
+	if err != nil {
+		panic(fmt.Sprintf("semver: invalid argument: %s: %v", s, err))
+	}
+
+	return v
+}
+
+func mustParseDecimalArg(s string) *big.Rat {
+	v, ok := new(big.Rat).SetString(s)
+	if !ok {
+		panic(fmt.Sprintf("decimal: invalid argument: %s", s))
+	}
+
+	return v
+}
+
+func mustParseDurationArg(s string) time.Duration {
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		panic(fmt.Sprintf("duration: invalid argument: %s: %v", s, err))
+	}
+
+	return d
+}
+
 // Macros is just a type of a slice of *Macro
 // which is responsible to register and search for macros based on the indent(parameter type).
 type Macros []*Macro
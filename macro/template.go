@@ -1,6 +1,7 @@
 package macro
 
 import (
+	"fmt"
 	"reflect"
 
 	"github.com/kataras/iris/v12/macro/interpreter/ast"
@@ -168,7 +169,11 @@ func Parse(src string, macros Macros) (Template, error) {
 				}
 			}
 
-			evalFn := tmplFn(paramfn.Args)
+			evalFn, err := buildParamFunc(tmplFn, paramfn.Args)
+			if err != nil {
+				return tmpl, fmt.Errorf("%s: %s: %w", p.Name, paramfn.Name, err)
+			}
+
 			if evalFn.IsNil() || !evalFn.IsValid() || evalFn.Kind() != reflect.Func {
 				continue
 			}
@@ -181,6 +186,18 @@ func Parse(src string, macros Macros) (Template, error) {
 	return tmpl, nil
 }
 
+// buildParamFunc calls the "builder" with the route's arguments,
+// e.g. min(1), and returns its panic, on invalid arguments, as an error.
+func buildParamFunc(builder ParamFuncBuilder, args []string) (fn reflect.Value, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v", r)
+		}
+	}()
+
+	return builder(args), nil
+}
+
 // CountParams returns the length of the dynamic path's input parameters.
 func CountParams(fullpath string, macros Macros) int {
 	tmpl, _ := Parse(fullpath, macros)
@@ -2,6 +2,8 @@ package basicauth
 
 import (
 	stdContext "context"
+	"crypto/rand"
+	"encoding/base64"
 	"net/http"
 	"net/url"
 	"strconv"
@@ -9,7 +11,9 @@ import (
 	"sync"
 	"time"
 
+	"github.com/kataras/iris/v12/auth/totp"
 	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/middleware/bruteforce"
 	"github.com/kataras/iris/v12/sessions"
 )
 
@@ -26,6 +30,12 @@ const (
 	// DefaultCookieMaxAge is the default cookie max age on MaxTries,
 	// when the Options.MaxAge is zero.
 	DefaultCookieMaxAge = time.Hour
+	// DefaultTOTPHeader is the default request header
+	// which holds the TOTP code when Options.TOTP is set.
+	DefaultTOTPHeader = "X-OTP"
+	// DefaultTOTPCookie is the default cookie name to store
+	// the two-factor authenticated session when Options.TOTP is set.
+	DefaultTOTPCookie = "basicotp"
 )
 
 const (
@@ -48,6 +58,11 @@ type AuthFunc func(ctx *context.Context, username, password string) (interface{}
 // See Options.ErrorHandler and DefaultErrorHandler for details.
 type ErrorHandler func(ctx *context.Context, err error)
 
+// TOTPFunc accepts the current request and the username and user value of an allowed login
+// and it should return the user's TOTP key or nil if the user has not enabled two-factor authentication.
+// Look the Options.TOTP field.
+type TOTPFunc func(ctx *context.Context, username string, user interface{}) (*totp.Key, error)
+
 // Options holds the necessary information that the BasicAuth instance needs to perform.
 // The only required value is the Allow field.
 //
@@ -76,10 +91,10 @@ type Options struct {
 	// and return a user object, e.g. fetch from database.
 	//
 	// There are two available builtin values, the AllowUsers and AllowUsersFile,
-	// both of them decode a static list of users and compares with the user input (see BCRYPT function too).
+	// both of them decode a static list of users and compares with the user input (see BCRYPT, ARGON2ID and SCRYPT functions too).
 	// Usage:
 	//  - Allow: AllowUsers(iris.Map{"username": "...", "password": "...", "other_field": ...}, [BCRYPT])
-	//  - Allow: AllowUsersFile("users.yml", [BCRYPT])
+	//  - Allow: AllowUsersFile("users.yml", [ARGON2ID, OnRehash(saveHash)])
 	// Look the user.go source file for details.
 	Allow AuthFunc
 	// MaxAge sets expiration duration for the in-memory credentials map.
@@ -126,6 +141,36 @@ type Options struct {
 	// Usage:
 	//  GC: basicauth.GC{Every: 2 * time.Hour}
 	GC GC
+	// TOTP, if not nil, enables two-factor authentication for the users it returns a TOTP key for.
+	// On their first login the client should send a code through the TOTPHeader,
+	// e.g. "X-OTP: 123456", otherwise the server responds with 401 and a "X-OTP: required" header.
+	// Each code can be used once. A verified code starts a two-factor authenticated session,
+	// stored to the TOTPCookie, which lasts for MaxAge (or one hour) so
+	// next requests of the same client do not need a code.
+	// Clients that do not keep cookies should send a new code on each request.
+	//
+	// Usage:
+	//  TOTP: func(ctx iris.Context, username string, user any) (*totp.Key, error) {
+	//    return db.FindTOTPKey(ctx, username)
+	//  }
+	TOTP TOTPFunc
+	// TOTPHeader is the request header which holds the TOTP code.
+	//
+	// Defaults to "X-OTP".
+	TOTPHeader string
+	// TOTPCookie is the cookie name the middleware uses to
+	// store the two-factor authenticated session.
+	//
+	// Defaults to "basicotp".
+	TOTPCookie string
+	// BruteForce, if not nil, tracks the failed sign in attempts per username and client IP
+	// and temporarily locks them out, see the bruteforce package for details.
+	// Unlike the MaxTries field, the attempts are tracked on the server side.
+	// The requests with already authorized credentials are not tracked.
+	//
+	// Usage:
+	//  BruteForce: bruteforce.New(bruteforce.Options{OnEvent: auditLog})
+	BruteForce *bruteforce.Tracker
 }
 
 // GC holds the context and the tick duration to clear expired stored credentials.
@@ -166,6 +211,17 @@ type BasicAuth struct {
 	credentials map[string]*time.Time // TODO: think of just a uint64 here (unix seconds).
 	// protects the credentials concurrent access.
 	mu sync.RWMutex
+
+	// totpSessions stores the two-factor authenticated sessions,
+	// key = cookie value, value = username:password and expiration time.
+	totpSessions map[string]totpSession
+	// totpSteps stores the last used TOTP time step of each username (replay prevention).
+	totpSteps map[string]int64
+}
+
+type totpSession struct {
+	fullUser  string
+	expiresAt time.Time
 }
 
 // New returns a new basic authentication middleware.
@@ -218,6 +274,16 @@ func New(opts Options) context.Handler {
 		opts.ErrorHandler = DefaultErrorHandler
 	}
 
+	if opts.TOTP != nil {
+		if opts.TOTPHeader == "" {
+			opts.TOTPHeader = DefaultTOTPHeader
+		}
+
+		if opts.TOTPCookie == "" {
+			opts.TOTPCookie = DefaultTOTPCookie
+		}
+	}
+
 	b := &BasicAuth{
 		opts:                    opts,
 		askCode:                 askCode,
@@ -225,6 +291,8 @@ func New(opts Options) context.Handler {
 		authenticateHeader:      authenticateHeader,
 		authenticateHeaderValue: authenticateHeaderValue,
 		credentials:             make(map[string]*time.Time),
+		totpSessions:            make(map[string]totpSession),
+		totpSteps:               make(map[string]int64),
 	}
 
 	if opts.GC.Every > 0 {
@@ -367,14 +435,27 @@ func (b *BasicAuth) serveHTTP(ctx *context.Context) {
 	var (
 		maxTries = b.opts.MaxTries
 		tries    int
+		// checked reports whether the attempt was checked by the BruteForce tracker.
+		checked bool
 	)
 
 	if maxTries > 0 {
 		tries = b.getCurrentTries(ctx)
 	}
 
+	if b.opts.BruteForce != nil && !b.hasCredentials(fullUser) {
+		if !b.checkAttempt(ctx, username) {
+			return
+		}
+		checked = true
+	}
+
 	user, ok := b.opts.Allow(ctx, username, password)
 	if !ok { // This username:password combination was not allowed.
+		if checked {
+			b.opts.BruteForce.Fail(ctx, username)
+		}
+
 		if maxTries > 0 {
 			tries++
 			b.setCurrentTries(ctx, tries)
@@ -400,6 +481,14 @@ func (b *BasicAuth) serveHTTP(ctx *context.Context) {
 		return
 	}
 
+	if b.opts.TOTP != nil && !b.verifyTOTP(ctx, fullUser, username, password, user, tries, checked) {
+		return
+	}
+
+	if checked {
+		b.opts.BruteForce.Succeed(ctx, username)
+	}
+
 	if tries > 0 {
 		// had failures but it's ok, reset the tries on success.
 		b.resetCurrentTries(ctx)
@@ -464,6 +553,150 @@ func (b *BasicAuth) serveHTTP(ctx *context.Context) {
 	ctx.Next()
 }
 
+// hasCredentials reports whether the "fullUser" (username:password) has been authorized before.
+func (b *BasicAuth) hasCredentials(fullUser string) bool {
+	b.mu.RLock()
+	_, ok := b.credentials[fullUser]
+	b.mu.RUnlock()
+	return ok
+}
+
+// checkAttempt checks the attempt through the BruteForce tracker
+// and reports whether the request can continue.
+func (b *BasicAuth) checkAttempt(ctx *context.Context, username string) bool {
+	if err := b.opts.BruteForce.Check(ctx, username); err != nil {
+		b.handleError(ctx, ErrTooManyAttempts{
+			Username:                username,
+			Err:                     err,
+			AuthenticateHeader:      b.authenticateHeader,
+			AuthenticateHeaderValue: b.authenticateHeaderValue,
+			Code:                    b.askCode,
+		})
+		return false
+	}
+
+	return true
+}
+
+// verifyTOTP verifies the second factor of the user, if enabled,
+// and reports whether the request can continue.
+func (b *BasicAuth) verifyTOTP(ctx *context.Context, fullUser, username, password string, user interface{}, tries int, checked bool) bool {
+	key, err := b.opts.TOTP(ctx, username, user)
+	if err != nil {
+		b.handleError(ctx, ErrTOTPInvalid{
+			Username:                username,
+			Err:                     err,
+			AuthenticateHeader:      b.authenticateHeader,
+			AuthenticateHeaderValue: b.authenticateHeaderValue,
+			Code:                    b.askCode,
+		})
+		return false
+	}
+
+	if key == nil { // two-factor authentication is not enabled for this user.
+		return true
+	}
+
+	now := time.Now()
+	sessionID := ctx.GetCookie(b.opts.TOTPCookie)
+	if sessionID != "" {
+		b.mu.RLock()
+		session, ok := b.totpSessions[sessionID]
+		b.mu.RUnlock()
+		if ok && session.fullUser == fullUser && session.expiresAt.After(now) {
+			return true
+		}
+	}
+
+	code := ctx.GetHeader(b.opts.TOTPHeader)
+	if code == "" {
+		b.handleError(ctx, ErrTOTPRequired{
+			Username:                username,
+			TOTPHeader:              b.opts.TOTPHeader,
+			AuthenticateHeader:      b.authenticateHeader,
+			AuthenticateHeaderValue: b.authenticateHeaderValue,
+			Code:                    b.askCode,
+		})
+		return false
+	}
+
+	if b.opts.BruteForce != nil && !checked && !b.checkAttempt(ctx, username) {
+		return false
+	}
+
+	step, err := key.Validate(code, now, totp.DefaultSkew)
+	if err == nil {
+		b.mu.Lock()
+		if lastStep, ok := b.totpSteps[username]; ok && step <= lastStep {
+			err = ErrTOTPCodeUsed
+		} else {
+			b.totpSteps[username] = step
+		}
+		b.mu.Unlock()
+	}
+
+	if err != nil {
+		if b.opts.BruteForce != nil {
+			b.opts.BruteForce.Fail(ctx, username)
+		}
+
+		if maxTries := b.opts.MaxTries; maxTries > 0 {
+			tries++
+			b.setCurrentTries(ctx, tries)
+			if tries >= maxTries {
+				b.handleError(ctx, ErrCredentialsForbidden{
+					Username: username,
+					Password: password,
+					Tries:    tries,
+					Age:      b.opts.MaxAge,
+				})
+				return false
+			}
+		}
+
+		b.handleError(ctx, ErrTOTPInvalid{
+			Username:                username,
+			Err:                     err,
+			CurrentTries:            tries,
+			AuthenticateHeader:      b.authenticateHeader,
+			AuthenticateHeaderValue: b.authenticateHeaderValue,
+			Code:                    b.askCode,
+		})
+		return false
+	}
+
+	maxAge := b.opts.MaxAge
+	if maxAge == 0 {
+		maxAge = DefaultCookieMaxAge // 1 hour.
+	}
+
+	sessionID = newTOTPSessionID()
+	b.mu.Lock()
+	b.totpSessions[sessionID] = totpSession{fullUser: fullUser, expiresAt: now.Add(maxAge)}
+	b.mu.Unlock()
+
+	ctx.SetCookie(&http.Cookie{
+		Name:     b.opts.TOTPCookie,
+		Path:     "/",
+		Value:    sessionID,
+		HttpOnly: true,
+		Secure:   ctx.IsSSL(),
+		Expires:  now.Add(maxAge),
+		MaxAge:   int(maxAge.Seconds()),
+	})
+
+	return true
+}
+
+func newTOTPSessionID() string {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		panic(err) // crypto/rand never fails on supported platforms.
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
 // logout clears the current user's credentials.
 func (b *BasicAuth) logout(ctx *context.Context) {
 	var (
@@ -497,8 +730,15 @@ func (b *BasicAuth) logout(ctx *context.Context) {
 
 		b.mu.Lock()
 		delete(b.credentials, fullUser)
+		if b.opts.TOTP != nil {
+			delete(b.totpSessions, ctx.GetCookie(b.opts.TOTPCookie))
+		}
 		b.mu.Unlock()
 
+		if b.opts.TOTP != nil {
+			ctx.RemoveCookie(b.opts.TOTPCookie)
+		}
+
 		if b.opts.MaxTries > 0 {
 			b.setCurrentTries(ctx, 0)
 		}
@@ -542,6 +782,14 @@ func (b *BasicAuth) gc() int {
 	}
 	b.mu.RUnlock()
 
+	b.mu.Lock()
+	for sessionID, session := range b.totpSessions {
+		if session.expiresAt.Before(now) {
+			delete(b.totpSessions, sessionID)
+		}
+	}
+	b.mu.Unlock()
+
 	n := len(markedForDeletion)
 	if n > 0 {
 		for _, fullUser := range markedForDeletion {
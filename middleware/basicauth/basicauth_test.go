@@ -5,10 +5,13 @@ package basicauth_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/auth/totp"
 	"github.com/kataras/iris/v12/httptest"
 	"github.com/kataras/iris/v12/middleware/basicauth"
+	"github.com/kataras/iris/v12/middleware/bruteforce"
 )
 
 func TestBasicAuthUseRouter(t *testing.T) {
@@ -155,3 +158,83 @@ func TestBasicAuthUseRouter(t *testing.T) {
 		sub.GET("/notfound").Expect().Status(httptest.StatusNotFound).Body().IsEqual("Not Found")
 	}
 }
+
+func TestBasicAuthTOTP(t *testing.T) {
+	key, err := totp.Generate("Iris", "admin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	app := iris.New()
+	app.Use(basicauth.New(basicauth.Options{
+		Allow:    basicauth.AllowUsers(map[string]string{"admin": "admin", "usr": "pss"}),
+		MaxTries: 3,
+		TOTP: func(ctx iris.Context, username string, user interface{}) (*totp.Key, error) {
+			if username == "admin" {
+				return key, nil
+			}
+			return nil, nil
+		},
+	}))
+	app.Get("/", func(ctx iris.Context) {
+		username, _ := ctx.User().GetUsername()
+		ctx.WriteString(username)
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://localhost"))
+
+	// Users without a key do not need a code.
+	e.GET("/").WithBasicAuth("usr", "pss").Expect().Status(httptest.StatusOK).Body().IsEqual("usr")
+
+	e.GET("/").WithBasicAuth("admin", "admin").Expect().Status(httptest.StatusUnauthorized).
+		Header(basicauth.DefaultTOTPHeader).IsEqual("required")
+	e.GET("/").WithBasicAuth("admin", "admin").WithHeader(basicauth.DefaultTOTPHeader, "000000x").Expect().
+		Status(httptest.StatusUnauthorized)
+
+	code, err := key.Code(time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A code for a wrong password is not consumed.
+	e.GET("/").WithBasicAuth("admin", "invalid").WithHeader(basicauth.DefaultTOTPHeader, code).Expect().
+		Status(httptest.StatusUnauthorized)
+
+	e.GET("/").WithBasicAuth("admin", "admin").WithHeader(basicauth.DefaultTOTPHeader, code).Expect().
+		Status(httptest.StatusOK).Body().IsEqual("admin")
+
+	// The two-factor authenticated session is stored to the cookie.
+	e.GET("/").WithBasicAuth("admin", "admin").Expect().Status(httptest.StatusOK).Body().IsEqual("admin")
+
+	// Another client, without the cookie, cannot reuse the code.
+	other := httptest.New(t, app, httptest.URL("http://localhost"))
+	other.GET("/").WithBasicAuth("admin", "admin").WithHeader(basicauth.DefaultTOTPHeader, code).Expect().
+		Status(httptest.StatusUnauthorized)
+}
+
+func TestBasicAuthBruteForce(t *testing.T) {
+	app := iris.New()
+	app.Use(basicauth.New(basicauth.Options{
+		Allow:      basicauth.AllowUsers(map[string]string{"admin": "admin", "usr": "pss"}),
+		BruteForce: bruteforce.New(bruteforce.Options{UsernameLimit: 2, IPLimit: -1}),
+	}))
+	app.Get("/", func(ctx iris.Context) {
+		username, _ := ctx.User().GetUsername()
+		ctx.WriteString(username)
+	})
+
+	e := httptest.New(t, app)
+
+	e.GET("/").WithBasicAuth("usr", "pss").Expect().Status(httptest.StatusOK).Body().IsEqual("usr")
+
+	for _, username := range []string{"admin", "usr"} {
+		e.GET("/").WithBasicAuth(username, "invalid").Expect().Status(httptest.StatusUnauthorized)
+		e.GET("/").WithBasicAuth(username, "invalid").Expect().Status(httptest.StatusUnauthorized)
+		e.GET("/").WithBasicAuth(username, "invalid").Expect().Status(httptest.StatusTooManyRequests).
+			Header("Retry-After").IsEqual("60")
+	}
+
+	e.GET("/").WithBasicAuth("admin", "admin").Expect().Status(httptest.StatusTooManyRequests)
+	// Already authorized credentials are not locked out.
+	e.GET("/").WithBasicAuth("usr", "pss").Expect().Status(httptest.StatusOK).Body().IsEqual("usr")
+}
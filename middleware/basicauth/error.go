@@ -1,11 +1,13 @@
 package basicauth
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
 	"time"
 
 	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/middleware/bruteforce"
 )
 
 type (
@@ -52,8 +54,45 @@ type (
 		AuthenticateHeaderValue string
 		Code                    int
 	}
+
+	// ErrTOTPRequired is fired when the username:password combination is valid,
+	// the user has enabled two-factor authentication and the TOTP header is missing.
+	ErrTOTPRequired struct {
+		Username   string
+		TOTPHeader string
+
+		AuthenticateHeader      string
+		AuthenticateHeaderValue string
+		Code                    int
+	}
+
+	// ErrTOTPInvalid is fired when the TOTP code is invalid or already used
+	// or when the Options.TOTP function returned an error.
+	ErrTOTPInvalid struct {
+		Username     string
+		Err          error
+		CurrentTries int
+
+		AuthenticateHeader      string
+		AuthenticateHeaderValue string
+		Code                    int
+	}
+
+	// ErrTooManyAttempts is fired when the Options.BruteForce tracker rejected the attempt,
+	// the Err is a *bruteforce.LockedError or the bruteforce.ErrCaptchaRequired.
+	ErrTooManyAttempts struct {
+		Username string
+		Err      error
+
+		AuthenticateHeader      string
+		AuthenticateHeaderValue string
+		Code                    int
+	}
 )
 
+// ErrTOTPCodeUsed is the Err of the ErrTOTPInvalid when a TOTP code is used again.
+var ErrTOTPCodeUsed = errors.New("totp code already used")
+
 func (e ErrHTTPVersion) Error() string {
 	return "http version not supported"
 }
@@ -78,6 +117,28 @@ func (e ErrCredentialsExpired) Error() string {
 	return fmt.Sprintf("credentials: expired <%s:%s>", e.Username, e.Password)
 }
 
+func (e ErrTOTPRequired) Error() string {
+	return fmt.Sprintf("credentials: totp code required for <%s>", e.Username)
+}
+
+func (e ErrTOTPInvalid) Error() string {
+	return fmt.Sprintf("credentials: invalid totp code for <%s> current tries <%d>: %v", e.Username, e.CurrentTries, e.Err)
+}
+
+// Unwrap returns the underline error.
+func (e ErrTOTPInvalid) Unwrap() error {
+	return e.Err
+}
+
+func (e ErrTooManyAttempts) Error() string {
+	return fmt.Sprintf("credentials: too many attempts for <%s>: %v", e.Username, e.Err)
+}
+
+// Unwrap returns the underline error.
+func (e ErrTooManyAttempts) Unwrap() error {
+	return e.Err
+}
+
 // DefaultErrorHandler is the default error handler for the Options.ErrorHandler field.
 func DefaultErrorHandler(ctx *context.Context, err error) {
 	switch e := err.(type) {
@@ -93,6 +154,19 @@ func DefaultErrorHandler(ctx *context.Context, err error) {
 	case ErrCredentialsInvalid:
 		unauthorize(ctx, e.AuthenticateHeader, e.AuthenticateHeaderValue, e.Code)
 	case ErrCredentialsExpired:
+		unauthorize(ctx, e.AuthenticateHeader, e.AuthenticateHeaderValue, e.Code)
+	case ErrTOTPRequired:
+		ctx.Header(e.TOTPHeader, "required")
+		unauthorize(ctx, e.AuthenticateHeader, e.AuthenticateHeaderValue, e.Code)
+	case ErrTOTPInvalid:
+		unauthorize(ctx, e.AuthenticateHeader, e.AuthenticateHeaderValue, e.Code)
+	case ErrTooManyAttempts:
+		if errors.Is(e.Err, bruteforce.ErrLocked) {
+			// The Retry-After header is set by the tracker.
+			ctx.StopWithStatus(http.StatusTooManyRequests)
+			return
+		}
+
 		unauthorize(ctx, e.AuthenticateHeader, e.AuthenticateHeaderValue, e.Code)
 	default:
 		// This will never happen.
@@ -0,0 +1,344 @@
+package basicauth
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kataras/iris/v12/context"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+	"golang.org/x/crypto/scrypt"
+)
+
+// ErrInvalidHash is returned when an encoded password hash is malformed.
+var ErrInvalidHash = errors.New("basicauth: invalid password hash")
+
+// PasswordHasher hashes and compares passwords of a specific algorithm.
+// The hashes are encoded in the PHC string format (https://github.com/P-H-C/phc-string-format),
+// e.g. $argon2id$v=19$m=65536,t=3,p=4$salt$hash, so hashes of different
+// algorithms and parameters can coexist. See the PasswordHashers type.
+//
+// Builtin implementations: Argon2id, Scrypt and Bcrypt.
+type PasswordHasher interface {
+	// Hash returns the encoded hash of the "password" with a new random salt.
+	Hash(password string) (string, error)
+	// Supports reports whether the "encoded" hash is of this hasher's algorithm.
+	Supports(encoded string) bool
+	// Compare reports whether the "password" matches the "encoded" hash
+	// and whether the hash should be re-computed because
+	// its parameters are not the same as the hasher's current ones.
+	Compare(encoded, password string) (ok bool, rehash bool, err error)
+}
+
+// Argon2id is a PasswordHasher of the argon2id algorithm (RFC 9106),
+// it is the recommended one for new applications.
+type Argon2id struct {
+	// Memory in KiB.
+	Memory      uint32
+	Iterations  uint32
+	Parallelism uint8
+	SaltLength  uint32
+	KeyLength   uint32
+}
+
+// Scrypt is a PasswordHasher of the scrypt algorithm (RFC 7914).
+// Its encoded hashes look like: $scrypt$ln=15,r=8,p=1$salt$hash.
+type Scrypt struct {
+	// LogN is the base-2 logarithm of the CPU/memory cost parameter N.
+	LogN       uint8
+	R          int
+	P          int
+	SaltLength int
+	KeyLength  int
+}
+
+// Bcrypt is a PasswordHasher of the bcrypt algorithm.
+// Its encoded hashes use the modular crypt format, e.g. $2a$10$...,
+// see the BCRYPT UserAuthOption too.
+type Bcrypt struct {
+	Cost int
+}
+
+// The default password hashers.
+var (
+	DefaultArgon2id = &Argon2id{Memory: 64 * 1024, Iterations: 3, Parallelism: 4, SaltLength: 16, KeyLength: 32}
+	DefaultScrypt   = &Scrypt{LogN: 15, R: 8, P: 1, SaltLength: 16, KeyLength: 32}
+	DefaultBcrypt   = &Bcrypt{Cost: bcrypt.DefaultCost}
+)
+
+// DefaultPasswordHashers hashes the new passwords with argon2id
+// and compares the argon2id, scrypt and bcrypt hashes.
+// Custom AuthFunc implementations, e.g. on top of a database,
+// can use it to hash and compare the users passwords.
+var DefaultPasswordHashers = PasswordHashers{DefaultArgon2id, DefaultScrypt, DefaultBcrypt}
+
+var b64 = base64.RawStdEncoding
+
+// The limits of the parameters of the compared hashes, a hash with parameters
+// out of them is reported as ErrInvalidHash instead of being computed,
+// so a stored hash cannot make each login attempt allocate or compute too much.
+const (
+	// maxHashMemory is the maximum memory of a hash computation in bytes (4 GiB).
+	maxHashMemory       = 4 << 30
+	maxArgon2Iterations = 64
+	maxScryptLogN       = 20
+	maxScryptRP         = 64
+)
+
+func randomSalt(n int) ([]byte, error) {
+	salt := make([]byte, n)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+
+	return salt, nil
+}
+
+// Hash implements the PasswordHasher interface.
+func (h *Argon2id) Hash(password string) (string, error) {
+	salt, err := randomSalt(int(h.SaltLength))
+	if err != nil {
+		return "", err
+	}
+
+	key := argon2.IDKey([]byte(password), salt, h.Iterations, h.Memory, h.Parallelism, h.KeyLength)
+	return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+		argon2.Version, h.Memory, h.Iterations, h.Parallelism, b64.EncodeToString(salt), b64.EncodeToString(key)), nil
+}
+
+// Supports implements the PasswordHasher interface.
+func (h *Argon2id) Supports(encoded string) bool {
+	return strings.HasPrefix(encoded, "$argon2id$")
+}
+
+// Compare implements the PasswordHasher interface.
+func (h *Argon2id) Compare(encoded, password string) (bool, bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 || parts[1] != "argon2id" {
+		return false, false, ErrInvalidHash
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil || version != argon2.Version {
+		return false, false, ErrInvalidHash
+	}
+
+	var p Argon2id
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.Memory, &p.Iterations, &p.Parallelism); err != nil || !p.validParams() {
+		return false, false, ErrInvalidHash
+	}
+
+	salt, key, err := decodeSaltAndKey(parts[4], parts[5])
+	if err != nil {
+		return false, false, err
+	}
+
+	computed := argon2.IDKey([]byte(password), salt, p.Iterations, p.Memory, p.Parallelism, uint32(len(key)))
+	if subtle.ConstantTimeCompare(key, computed) != 1 {
+		return false, false, nil
+	}
+
+	rehash := p.Memory != h.Memory || p.Iterations != h.Iterations || p.Parallelism != h.Parallelism ||
+		uint32(len(salt)) != h.SaltLength || uint32(len(key)) != h.KeyLength
+	return true, rehash, nil
+}
+
+// validParams reports whether the cost parameters of a compared hash are within the limits.
+func (h *Argon2id) validParams() bool {
+	return h.Iterations >= 1 && h.Iterations <= maxArgon2Iterations && h.Parallelism >= 1 &&
+		h.Memory >= 8*uint32(h.Parallelism) && uint64(h.Memory) <= maxHashMemory/1024
+}
+
+// Hash implements the PasswordHasher interface.
+func (h *Scrypt) Hash(password string) (string, error) {
+	salt, err := randomSalt(h.SaltLength)
+	if err != nil {
+		return "", err
+	}
+
+	key, err := scrypt.Key([]byte(password), salt, 1<<h.LogN, h.R, h.P, h.KeyLength)
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("$scrypt$ln=%d,r=%d,p=%d$%s$%s",
+		h.LogN, h.R, h.P, b64.EncodeToString(salt), b64.EncodeToString(key)), nil
+}
+
+// Supports implements the PasswordHasher interface.
+func (h *Scrypt) Supports(encoded string) bool {
+	return strings.HasPrefix(encoded, "$scrypt$")
+}
+
+// Compare implements the PasswordHasher interface.
+func (h *Scrypt) Compare(encoded, password string) (bool, bool, error) {
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 5 || parts[1] != "scrypt" {
+		return false, false, ErrInvalidHash
+	}
+
+	var p Scrypt
+	if _, err := fmt.Sscanf(parts[2], "ln=%d,r=%d,p=%d", &p.LogN, &p.R, &p.P); err != nil || !p.validParams() {
+		return false, false, ErrInvalidHash
+	}
+
+	salt, key, err := decodeSaltAndKey(parts[3], parts[4])
+	if err != nil {
+		return false, false, err
+	}
+
+	computed, err := scrypt.Key([]byte(password), salt, 1<<p.LogN, p.R, p.P, len(key))
+	if err != nil {
+		return false, false, ErrInvalidHash
+	}
+
+	if subtle.ConstantTimeCompare(key, computed) != 1 {
+		return false, false, nil
+	}
+
+	rehash := p.LogN != h.LogN || p.R != h.R || p.P != h.P ||
+		len(salt) != h.SaltLength || len(key) != h.KeyLength
+	return true, rehash, nil
+}
+
+// validParams reports whether the cost parameters of a compared hash are within the limits.
+// The memory of scrypt is 128*r*N bytes.
+func (h *Scrypt) validParams() bool {
+	return h.LogN >= 1 && h.LogN <= maxScryptLogN && h.R >= 1 && h.P >= 1 &&
+		h.R*h.P <= maxScryptRP && 128*uint64(h.R)<<h.LogN <= maxHashMemory
+}
+
+func decodeSaltAndKey(encodedSalt, encodedKey string) ([]byte, []byte, error) {
+	salt, err := b64.DecodeString(encodedSalt)
+	if err != nil {
+		return nil, nil, ErrInvalidHash
+	}
+
+	key, err := b64.DecodeString(encodedKey)
+	if err != nil || len(key) == 0 {
+		return nil, nil, ErrInvalidHash
+	}
+
+	return salt, key, nil
+}
+
+// Hash implements the PasswordHasher interface.
+func (h *Bcrypt) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.Cost)
+	return string(hash), err
+}
+
+// Supports implements the PasswordHasher interface.
+func (h *Bcrypt) Supports(encoded string) bool {
+	return strings.HasPrefix(encoded, "$2a$") || strings.HasPrefix(encoded, "$2b$") || strings.HasPrefix(encoded, "$2y$")
+}
+
+// Compare implements the PasswordHasher interface.
+func (h *Bcrypt) Compare(encoded, password string) (bool, bool, error) {
+	err := bcrypt.CompareHashAndPassword([]byte(encoded), []byte(password))
+	if err != nil {
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, false, nil
+		}
+
+		return false, false, ErrInvalidHash
+	}
+
+	cost, err := bcrypt.Cost([]byte(encoded))
+	return true, err == nil && cost != h.Cost, nil
+}
+
+// PasswordHashers is a list of PasswordHasher.
+// The first one hashes the new passwords and all of them
+// compare the existing hashes, so the algorithms can be changed
+// without forcing the users to reset their passwords.
+type PasswordHashers []PasswordHasher
+
+// Hash returns the encoded hash of the "password" using the first hasher.
+func (hashers PasswordHashers) Hash(password string) (string, error) {
+	if len(hashers) == 0 {
+		return "", errors.New("basicauth: no password hashers")
+	}
+
+	return hashers[0].Hash(password)
+}
+
+// Compare reports whether the "password" matches the "encoded" hash and
+// whether the hash should be upgraded: it's not hashed by the first hasher
+// or its parameters are not the current ones. On upgrade, call the Hash method
+// with the same password and store its result.
+func (hashers PasswordHashers) Compare(encoded, password string) (ok bool, rehash bool) {
+	for i, h := range hashers {
+		if !h.Supports(encoded) {
+			continue
+		}
+
+		ok, rehash, err := h.Compare(encoded, password)
+		if err != nil || !ok {
+			return false, false
+		}
+
+		return true, rehash || i > 0
+	}
+
+	return false, false
+}
+
+// RehashFunc is called after a successful login whose password hash was upgraded,
+// it should store the new "hash" of the "username", e.g. in a database.
+// The "user" is the value returned by the AuthFunc, if any.
+type RehashFunc func(ctx *context.Context, username string, user interface{}, hash string)
+
+// HashedPasswords is a UserAuthOption which compares the hashed passwords
+// with the "current" and "legacy" hashers (see the PasswordHashers type).
+// When a password hash is not of the current algorithm or parameters,
+// it's replaced with a new one on the next successful login, see the OnRehash option.
+//
+// Usage:
+//
+//	AllowUsersFile("users.yml", HashedPasswords(basicauth.DefaultArgon2id, basicauth.DefaultBcrypt), OnRehash(saveHash))
+//
+// See the ARGON2ID UserAuthOption too.
+func HashedPasswords(current PasswordHasher, legacy ...PasswordHasher) UserAuthOption {
+	hashers := append(PasswordHashers{current}, legacy...)
+
+	return func(opts *UserAuthOptions) {
+		opts.Hashers = hashers
+		opts.ComparePassword = func(stored, userPassword string) bool {
+			ok, _ := hashers.Compare(stored, userPassword)
+			return ok
+		}
+	}
+}
+
+// ARGON2ID is a UserAuthOption, it compares argon2id, scrypt and bcrypt hashed passwords
+// with their user input and upgrades the scrypt and bcrypt ones to argon2id, see the OnRehash option.
+// It's a shortcut of HashedPasswords(DefaultArgon2id, DefaultScrypt, DefaultBcrypt).
+//
+// Usage:
+//
+//	Default(..., ARGON2ID) OR
+//	Options.Allow = AllowUsers(..., ARGON2ID, OnRehash(saveHash))
+func ARGON2ID(opts *UserAuthOptions) {
+	HashedPasswords(DefaultArgon2id, DefaultScrypt, DefaultBcrypt)(opts)
+}
+
+// SCRYPT is a UserAuthOption, it compares scrypt, argon2id and bcrypt hashed passwords
+// with their user input and upgrades the argon2id and bcrypt ones to scrypt, see the OnRehash option.
+// It's a shortcut of HashedPasswords(DefaultScrypt, DefaultArgon2id, DefaultBcrypt).
+func SCRYPT(opts *UserAuthOptions) {
+	HashedPasswords(DefaultScrypt, DefaultArgon2id, DefaultBcrypt)(opts)
+}
+
+// OnRehash is a UserAuthOption which registers a function that stores the upgraded password hashes.
+// It's used along with the HashedPasswords, ARGON2ID and SCRYPT options.
+func OnRehash(fn RehashFunc) UserAuthOption {
+	return func(opts *UserAuthOptions) {
+		opts.OnRehash = fn
+	}
+}
@@ -0,0 +1,175 @@
+package basicauth
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/kataras/iris/v12/context"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Low cost parameters to keep the tests fast.
+var (
+	testArgon2id = &Argon2id{Memory: 1024, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}
+	testScrypt   = &Scrypt{LogN: 4, R: 8, P: 1, SaltLength: 16, KeyLength: 32}
+	testBcrypt   = &Bcrypt{Cost: bcrypt.MinCost}
+)
+
+func mustHash(t *testing.T, h PasswordHasher, password string) string {
+	t.Helper()
+
+	hash, err := h.Hash(password)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return hash
+}
+
+func TestPasswordHashers(t *testing.T) {
+	var tests = []struct {
+		hasher   PasswordHasher
+		upgraded PasswordHasher // same algorithm, different parameters.
+		prefix   string
+	}{
+		{testArgon2id, &Argon2id{Memory: 2048, Iterations: 1, Parallelism: 1, SaltLength: 16, KeyLength: 32}, "$argon2id$v=19$m=1024,t=1,p=1$"},
+		{testScrypt, &Scrypt{LogN: 5, R: 8, P: 1, SaltLength: 16, KeyLength: 32}, "$scrypt$ln=4,r=8,p=1$"},
+		{testBcrypt, &Bcrypt{Cost: bcrypt.MinCost + 1}, "$2a$04$"},
+	}
+
+	for i, tt := range tests {
+		hash := mustHash(t, tt.hasher, "kataras_pass")
+		if !strings.HasPrefix(hash, tt.prefix) {
+			t.Fatalf("[%d] expected hash prefix: %q but got: %q", i, tt.prefix, hash)
+		}
+
+		if other := mustHash(t, tt.hasher, "kataras_pass"); other == hash {
+			t.Fatalf("[%d] expected a random salt", i)
+		}
+
+		if !tt.hasher.Supports(hash) {
+			t.Fatalf("[%d] expected hasher to support its hash", i)
+		}
+
+		if ok, rehash, err := tt.hasher.Compare(hash, "kataras_pass"); err != nil || !ok || rehash {
+			t.Fatalf("[%d] expected ok without rehash but got: ok=%v, rehash=%v, err=%v", i, ok, rehash, err)
+		}
+
+		if ok, _, err := tt.hasher.Compare(hash, "invalid_pass"); err != nil || ok {
+			t.Fatalf("[%d] expected a mismatch but got: ok=%v, err=%v", i, ok, err)
+		}
+
+		if ok, rehash, err := tt.upgraded.Compare(hash, "kataras_pass"); err != nil || !ok || !rehash {
+			t.Fatalf("[%d] expected ok with rehash but got: ok=%v, rehash=%v, err=%v", i, ok, rehash, err)
+		}
+
+		if _, _, err := tt.hasher.Compare(tt.prefix+"invalid", "kataras_pass"); err == nil {
+			t.Fatalf("[%d] expected an error on malformed hash", i)
+		}
+	}
+
+	hashers := PasswordHashers{testArgon2id, testScrypt, testBcrypt}
+	for i, tt := range tests {
+		ok, rehash := hashers.Compare(mustHash(t, tt.hasher, "kataras_pass"), "kataras_pass")
+		if expected := i > 0; !ok || rehash != expected {
+			t.Fatalf("[%d] expected ok with rehash: %v but got: ok=%v, rehash=%v", i, expected, ok, rehash)
+		}
+	}
+
+	if ok, _ := hashers.Compare("kataras_pass", "kataras_pass"); ok {
+		t.Fatal("expected plain text passwords to not match")
+	}
+}
+
+func TestPasswordHashersInvalidParams(t *testing.T) {
+	const saltAndKey = "$c29tZXNhbHQ$c29tZWtleQ"
+
+	for i, encoded := range []string{
+		"$argon2id$v=19$m=1024,t=0,p=1" + saltAndKey,
+		"$argon2id$v=19$m=1024,t=1,p=0" + saltAndKey,
+		"$argon2id$v=19$m=0,t=1,p=1" + saltAndKey,
+		"$argon2id$v=19$m=4294967295,t=1,p=1" + saltAndKey,
+		"$argon2id$v=19$m=1024,t=100000,p=1" + saltAndKey,
+		"$argon2id$v=19$m=1024,t=1,p=256" + saltAndKey,
+		"$scrypt$ln=4,r=0,p=1" + saltAndKey,
+		"$scrypt$ln=4,r=8,p=0" + saltAndKey,
+		"$scrypt$ln=4,r=-1,p=1" + saltAndKey,
+		"$scrypt$ln=0,r=8,p=1" + saltAndKey,
+		"$scrypt$ln=31,r=8,p=1" + saltAndKey,
+		"$scrypt$ln=20,r=64,p=1" + saltAndKey,
+		"$scrypt$ln=4,r=8,p=1000" + saltAndKey,
+	} {
+		for _, h := range []PasswordHasher{testArgon2id, testScrypt} {
+			if !h.Supports(encoded) {
+				continue
+			}
+
+			if ok, _, err := h.Compare(encoded, "kataras_pass"); err != ErrInvalidHash || ok {
+				t.Fatalf("[%d] expected error: %v but got: ok=%v, err=%v", i, ErrInvalidHash, ok, err)
+			}
+		}
+	}
+}
+
+func TestAllowUsersRehash(t *testing.T) {
+	var (
+		mu       sync.Mutex
+		rehashed = make(map[string]string)
+	)
+
+	opts := []UserAuthOption{
+		HashedPasswords(testArgon2id, testScrypt, testBcrypt),
+		OnRehash(func(_ *context.Context, username string, _ interface{}, hash string) {
+			mu.Lock()
+			rehashed[username] = hash
+			mu.Unlock()
+		}),
+	}
+
+	for _, allow := range []AuthFunc{
+		AllowUsers(map[string]string{
+			"kataras": mustHash(t, testBcrypt, "kataras_pass"),
+			"makis":   mustHash(t, testScrypt, "makis_pass"),
+			"george":  mustHash(t, testArgon2id, "george_pass"),
+		}, opts...),
+		AllowUsers([]map[string]interface{}{
+			{"username": "kataras", "password": mustHash(t, testBcrypt, "kataras_pass")},
+			{"username": "makis", "password": mustHash(t, testScrypt, "makis_pass")},
+			{"username": "george", "password": mustHash(t, testArgon2id, "george_pass")},
+		}, opts...),
+	} {
+		rehashed = make(map[string]string)
+
+		if _, ok := allow(nil, "kataras", "invalid_pass"); ok {
+			t.Fatal("expected invalid password to fail")
+		}
+
+		for _, username := range []string{"kataras", "makis", "george"} {
+			if _, ok := allow(nil, username, username+"_pass"); !ok {
+				t.Fatalf("expected %s to pass", username)
+			}
+		}
+
+		if expected, got := 2, len(rehashed); expected != got {
+			t.Fatalf("expected %d rehashed passwords but got %d: %v", expected, got, rehashed)
+		}
+
+		for username, hash := range rehashed {
+			if !testArgon2id.Supports(hash) {
+				t.Fatalf("expected %s password to be upgraded to argon2id but got: %s", username, hash)
+			}
+
+			// The upgraded hash is used from now on.
+			delete(rehashed, username)
+			if _, ok := allow(nil, username, username+"_pass"); !ok {
+				t.Fatalf("expected %s to pass after rehash", username)
+			}
+		}
+
+		if len(rehashed) > 0 {
+			t.Fatalf("expected no more rehashes but got: %v", rehashed)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"reflect"
 	"strings"
+	"sync"
 
 	"github.com/kataras/iris/v12/context"
 
@@ -32,6 +33,14 @@ type UserAuthOptions struct {
 	// Defaults to plain check, can be modified for encrypted passwords,
 	// see the BCRYPT optional function.
 	ComparePassword func(stored, userPassword string) bool
+	// Hashers compare the PHC-encoded password hashes, if not empty
+	// it's used instead of the ComparePassword field.
+	// See the HashedPasswords, ARGON2ID and SCRYPT optional functions.
+	Hashers PasswordHashers
+	// OnRehash, if not nil, is called after a successful login
+	// whose password hash was upgraded by the Hashers.
+	// See the OnRehash optional function.
+	OnRehash RehashFunc
 }
 
 // UserAuthOption is the option function type
@@ -54,6 +63,7 @@ type UserAuthOption func(*UserAuthOptions)
 //	Options.Allow = AllowUsers(..., BCRYPT) OR
 //	OPtions.Allow = AllowUsersFile(..., BCRYPT)
 func BCRYPT(opts *UserAuthOptions) {
+	opts.Hashers = nil
 	opts.ComparePassword = func(stored, userPassword string) bool {
 		err := bcrypt.CompareHashAndPassword([]byte(stored), []byte(userPassword))
 		return err == nil
@@ -74,6 +84,26 @@ func toUserAuthOptions(opts []UserAuthOption) (options UserAuthOptions) {
 	return options
 }
 
+// compare reports whether the "userPassword" matches the "stored" one.
+// It returns the upgraded hash of the password, if any, see the OnRehash field.
+func (opts *UserAuthOptions) compare(stored, userPassword string) (bool, string) {
+	if len(opts.Hashers) == 0 {
+		return opts.ComparePassword(stored, userPassword), ""
+	}
+
+	ok, rehash := opts.Hashers.Compare(stored, userPassword)
+	if !ok || !rehash || opts.OnRehash == nil {
+		return ok, ""
+	}
+
+	hash, err := opts.Hashers.Hash(userPassword)
+	if err != nil {
+		return true, "" // keep the existing hash, try again on the next login.
+	}
+
+	return true, hash
+}
+
 // AllowUsers is an AuthFunc which authenticates user input based on a (static) user list.
 // The "users" input parameter can be one of the following forms:
 //
@@ -83,7 +113,7 @@ func toUserAuthOptions(opts []UserAuthOption) (options UserAuthOptions) {
 //	[]T which T contains at least Username and Password fields.
 //
 // Usage:
-// New(Options{Allow: AllowUsers(..., [BCRYPT])})
+// New(Options{Allow: AllowUsers(..., [BCRYPT|ARGON2ID|SCRYPT])})
 func AllowUsers(users interface{}, opts ...UserAuthOption) AuthFunc {
 	// create a local user structure to be used in the map copy,
 	// takes longer to initialize but faster to serve.
@@ -132,24 +162,69 @@ func AllowUsers(users interface{}, opts ...UserAuthOption) AuthFunc {
 	}
 
 	options := toUserAuthOptions(opts)
+	var mu sync.RWMutex // protects the upgraded passwords.
+
+	return func(ctx *context.Context, username, password string) (interface{}, bool) {
+		mu.RLock()
+		u, ok := cp[username] // fast map access,
+		var stored string
+		if ok {
+			stored = u.password
+		}
+		mu.RUnlock()
 
-	return func(_ *context.Context, username, password string) (interface{}, bool) {
-		if u, ok := cp[username]; ok { // fast map access,
-			if options.ComparePassword(u.password, password) {
-				return u.ref, true
-			}
+		if !ok {
+			return nil, false
+		}
+
+		ok, hash := options.compare(stored, password)
+		if !ok {
+			return nil, false
 		}
 
-		return nil, false
+		if hash != "" {
+			mu.Lock()
+			u.password = hash
+			mu.Unlock()
+
+			options.OnRehash(ctx, username, u.ref, hash)
+		}
+
+		return u.ref, true
 	}
 }
 
 func userMap(usernamePassword map[string]string, opts ...UserAuthOption) AuthFunc {
 	options := toUserAuthOptions(opts)
 
-	return func(_ *context.Context, username, password string) (interface{}, bool) {
-		pass, ok := usernamePassword[username]
-		return nil, ok && options.ComparePassword(pass, password)
+	var (
+		mu       sync.RWMutex
+		upgraded map[string]string // the upgraded passwords, the given map is not modified.
+	)
+
+	return func(ctx *context.Context, username, password string) (interface{}, bool) {
+		mu.RLock()
+		pass, ok := upgraded[username]
+		mu.RUnlock()
+		if !ok {
+			if pass, ok = usernamePassword[username]; !ok {
+				return nil, false
+			}
+		}
+
+		ok, hash := options.compare(pass, password)
+		if ok && hash != "" {
+			mu.Lock()
+			if upgraded == nil {
+				upgraded = make(map[string]string)
+			}
+			upgraded[username] = hash
+			mu.Unlock()
+
+			options.OnRehash(ctx, username, nil, hash)
+		}
+
+		return nil, ok
 	}
 }
 
@@ -0,0 +1,531 @@
+// Package bruteforce provides brute-force and credential stuffing protection for login handlers.
+//
+// A Tracker counts the failed sign in attempts per username and per client IP.
+// After a number of failures the username or the IP is temporarily locked out,
+// each next failure doubles the lockout duration (exponential backoff).
+// Optionally, a CAPTCHA (hCaptcha or reCAPTCHA) is required before the lockouts,
+// see the Options.Captcha field.
+//
+// The Tracker is used by the basicauth middleware (see basicauth.Options.BruteForce)
+// and the auth package (see Auth.SetBruteForce). Custom login handlers should call
+// the Check method before verifying the credentials and the Fail or Succeed methods after.
+package bruteforce
+
+import (
+	stdContext "context"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/middleware/hcaptcha"
+	"github.com/kataras/iris/v12/middleware/recaptcha"
+)
+
+// DefaultCaptchaHeader is the default value of the Options.CaptchaHeader field.
+const DefaultCaptchaHeader = "X-Captcha"
+
+var (
+	// ErrLocked is reported, through a *LockedError, when the username or the IP is locked out.
+	ErrLocked = errors.New("bruteforce: too many failed attempts")
+	// ErrCaptchaRequired is returned by the Check method when a CAPTCHA is required
+	// and the request's CAPTCHA response is missing or invalid.
+	ErrCaptchaRequired = errors.New("bruteforce: captcha required")
+)
+
+// LockedError is returned by the Check method when the username or the client's IP is locked out.
+// It reports true on errors.Is(err, ErrLocked).
+type LockedError struct {
+	// Username is not empty when the username is locked out.
+	Username string
+	// IP is not empty when the client's IP is locked out.
+	IP string
+	// RetryAfter is the remaining duration of the lockout.
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e *LockedError) Error() string {
+	return fmt.Sprintf("%s, retry after %s", ErrLocked.Error(), e.RetryAfter)
+}
+
+// Is reports whether the "target" is ErrLocked.
+func (e *LockedError) Is(target error) bool {
+	return target == ErrLocked
+}
+
+// EventType is the type of an audit Event.
+type EventType string
+
+// The audit event types.
+const (
+	// EventFailure is fired on each failed attempt.
+	EventFailure EventType = "failure"
+	// EventSuccess is fired on a successful attempt.
+	EventSuccess EventType = "success"
+	// EventLockout is fired when a username or an IP gets locked out.
+	EventLockout EventType = "lockout"
+	// EventBlocked is fired when an attempt is rejected because of a lockout.
+	EventBlocked EventType = "blocked"
+	// EventCaptchaFailed is fired when an attempt is rejected because of a missing or invalid CAPTCHA.
+	EventCaptchaFailed EventType = "captcha_failed"
+)
+
+// Event is an audit event, see the Options.OnEvent field.
+type Event struct {
+	Type     EventType `json:"type"`
+	Time     time.Time `json:"time"`
+	Username string    `json:"username,omitempty"`
+	IP       string    `json:"ip"`
+	// UsernameFailures and IPFailures are the current failures of the username and the IP.
+	UsernameFailures int `json:"username_failures"`
+	IPFailures       int `json:"ip_failures"`
+	// RetryAfter is the lockout duration of EventLockout and EventBlocked events.
+	RetryAfter time.Duration `json:"retry_after,omitempty"`
+}
+
+// CaptchaFunc should report whether the current request has a valid CAPTCHA response.
+// See the HCaptcha and ReCAPTCHA package-level functions.
+type CaptchaFunc func(ctx *context.Context) bool
+
+// HCaptcha returns a CaptchaFunc which verifies the "h-captcha-response" form value
+// (or URL query parameter) through the hcaptcha middleware's SiteVerify function.
+func HCaptcha(secret string, options ...hcaptcha.Option) CaptchaFunc {
+	c := new(hcaptcha.Client)
+	for _, opt := range options {
+		opt(c)
+	}
+
+	return func(ctx *context.Context) bool {
+		return hcaptcha.SiteVerify(ctx, secret, c.RemoteIP, c.SiteKey).Success
+	}
+}
+
+// ReCAPTCHA returns a CaptchaFunc which verifies the "g-recaptcha-response" form value
+// (or URL query parameter) through the recaptcha middleware's SiteVerify function.
+func ReCAPTCHA(secret string) CaptchaFunc {
+	return func(ctx *context.Context) bool {
+		return recaptcha.SiteVerify(ctx, secret).Success
+	}
+}
+
+// GC holds the context and the tick duration to clear the expired entries.
+// See the Options.GC field.
+type GC struct {
+	Context stdContext.Context
+	Every   time.Duration
+}
+
+// Options holds the configuration for the Tracker.
+type Options struct {
+	// UsernameLimit is the number of consecutive failures of a username,
+	// from any client, which locks out the username.
+	// Note that an attacker can lock out a legitimate user on purpose,
+	// prefer a CAPTCHA (see CaptchaAfter) with a higher limit.
+	//
+	// Defaults to 5, a negative value disables the username lockouts.
+	UsernameLimit int
+	// IPLimit is the number of failures of a client IP, for any username,
+	// which locks out the IP. It protects against credential stuffing.
+	//
+	// Defaults to 20, a negative value disables the IP lockouts.
+	IPLimit int
+	// Lockout is the duration of the first lockout,
+	// each next failure of the same username or IP doubles it, up to the MaxLockout.
+	//
+	// Defaults to 1 minute.
+	Lockout time.Duration
+	// MaxLockout is the maximum duration of a lockout.
+	//
+	// Defaults to 1 hour.
+	MaxLockout time.Duration
+	// Window is the duration after the last failure which the failures of
+	// a username or an IP are forgotten.
+	//
+	// Defaults to 24 hours.
+	Window time.Duration
+	// Captcha, if not nil, is called when the username or the IP has at least
+	// CaptchaAfter failures, the attempt is rejected with ErrCaptchaRequired
+	// if it reports false.
+	//
+	// Usage:
+	//  Captcha: bruteforce.HCaptcha(secret)
+	Captcha CaptchaFunc
+	// CaptchaAfter is the number of failures of a username or an IP
+	// which make the Captcha required. It should be lower than the limits.
+	//
+	// Defaults to 3.
+	CaptchaAfter int
+	// CaptchaHeader is the response header, with a value of "required",
+	// which is sent to the clients that should solve a CAPTCHA on the next attempt.
+	//
+	// Defaults to "X-Captcha".
+	CaptchaHeader string
+	// IP returns the client's IP.
+	//
+	// Defaults to the Context.RemoteAddr method, see the Configuration.RemoteAddrHeaders too.
+	IP func(ctx *context.Context) string
+	// OnEvent, if not nil, is called on each event, e.g. to write an audit log.
+	// It should not block.
+	OnEvent func(ctx *context.Context, event Event)
+	// MaxEntries is the maximum number of the tracked usernames and,
+	// separately, of the tracked IPs. When it's reached, an entry which
+	// is not locked out is removed to make room for the new one, so the memory
+	// cannot grow with the usernames or the IPs chosen by an attacker.
+	//
+	// Defaults to 100000, a negative value disables the limit.
+	MaxEntries int
+	// GC automatically clears the expired entries every x duration.
+	// The standard context can be used for the internal ticker cancelation, it can be nil.
+	//
+	// Defaults to every 10 minutes, a negative Every disables it.
+	//
+	// Usage:
+	//  GC: bruteforce.GC{Every: time.Hour}
+	GC GC
+}
+
+type attempts struct {
+	failures    int
+	last        time.Time
+	lockedUntil time.Time
+}
+
+// Tracker tracks the failed sign in attempts per username and per client IP.
+// Create a new one through the New package-level function.
+// It is safe for concurrent use.
+type Tracker struct {
+	opts Options
+
+	mu        sync.Mutex
+	usernames map[string]*attempts
+	ips       map[string]*attempts
+}
+
+// New returns a new attempts Tracker.
+//
+// Usage:
+//
+//	tracker := bruteforce.New(bruteforce.Options{
+//		Captcha: bruteforce.HCaptcha(secret),
+//		OnEvent: func(ctx iris.Context, e bruteforce.Event) {
+//			ctx.Application().Logger().Infof("auth: %s: %s from %s", e.Type, e.Username, e.IP)
+//		},
+//		GC: bruteforce.GC{Every: time.Hour},
+//	})
+func New(opts Options) *Tracker {
+	if opts.UsernameLimit == 0 {
+		opts.UsernameLimit = 5
+	}
+
+	if opts.IPLimit == 0 {
+		opts.IPLimit = 20
+	}
+
+	if opts.Lockout <= 0 {
+		opts.Lockout = time.Minute
+	}
+
+	if opts.MaxLockout <= 0 {
+		opts.MaxLockout = time.Hour
+	}
+
+	if opts.Window <= 0 {
+		opts.Window = 24 * time.Hour
+	}
+
+	if opts.CaptchaAfter <= 0 {
+		opts.CaptchaAfter = 3
+	}
+
+	if opts.CaptchaHeader == "" {
+		opts.CaptchaHeader = DefaultCaptchaHeader
+	}
+
+	if opts.MaxEntries == 0 {
+		opts.MaxEntries = 100000
+	}
+
+	if opts.GC.Every == 0 {
+		opts.GC.Every = 10 * time.Minute
+	}
+
+	if opts.IP == nil {
+		opts.IP = func(ctx *context.Context) string {
+			return ctx.RemoteAddr()
+		}
+	}
+
+	t := &Tracker{
+		opts:      opts,
+		usernames: make(map[string]*attempts),
+		ips:       make(map[string]*attempts),
+	}
+
+	if opts.GC.Every > 0 {
+		go t.runGC(opts.GC.Context, opts.GC.Every)
+	}
+
+	return t
+}
+
+// Check reports whether a sign in attempt of the "username" from the current client
+// is allowed. It should be called before the credentials verification.
+// The "username" can be empty to check the client's IP only.
+//
+// It returns a *LockedError, and sends the Retry-After header, when the username or the IP
+// is locked out and ErrCaptchaRequired, and sends the CaptchaHeader, when a CAPTCHA is
+// required but the request's CAPTCHA response is missing or invalid.
+func (t *Tracker) Check(ctx *context.Context, username string) error {
+	now := time.Now()
+	event := Event{Time: now, Username: username, IP: t.opts.IP(ctx)}
+
+	t.mu.Lock()
+	u := t.getLocked(t.usernames, username, now)
+	ip := t.getLocked(t.ips, event.IP, now)
+	event.UsernameFailures, event.IPFailures = u.failuresOrZero(), ip.failuresOrZero()
+
+	var lockedErr *LockedError
+	if retryAfter := u.retryAfter(now); retryAfter > 0 {
+		lockedErr = &LockedError{Username: username, RetryAfter: retryAfter}
+	}
+	if retryAfter := ip.retryAfter(now); retryAfter > 0 {
+		if lockedErr == nil {
+			lockedErr = new(LockedError)
+		}
+		lockedErr.IP = event.IP
+		if retryAfter > lockedErr.RetryAfter {
+			lockedErr.RetryAfter = retryAfter
+		}
+	}
+	t.mu.Unlock()
+
+	if lockedErr != nil {
+		event.Type = EventBlocked
+		event.RetryAfter = lockedErr.RetryAfter
+		t.emit(ctx, event)
+
+		ctx.Header("Retry-After", strconv.FormatInt(int64(math.Ceil(lockedErr.RetryAfter.Seconds())), 10))
+		return lockedErr
+	}
+
+	if t.captchaRequired(event) && !t.opts.Captcha(ctx) {
+		event.Type = EventCaptchaFailed
+		t.emit(ctx, event)
+
+		ctx.Header(t.opts.CaptchaHeader, "required")
+		return ErrCaptchaRequired
+	}
+
+	return nil
+}
+
+// Fail records a failed sign in attempt of the "username" from the current client.
+// The "username" can be empty to record the failure to the client's IP only.
+// The failures of an unknown (empty) client IP are not tracked.
+func (t *Tracker) Fail(ctx *context.Context, username string) {
+	now := time.Now()
+	event := Event{Type: EventFailure, Time: now, Username: username, IP: t.opts.IP(ctx)}
+
+	var lockouts []time.Duration
+
+	t.mu.Lock()
+	if username != "" {
+		u := t.getOrCreateLocked(t.usernames, username, now)
+		if lockout := t.failLocked(u, t.opts.UsernameLimit, now); lockout > 0 {
+			lockouts = append(lockouts, lockout)
+		}
+		event.UsernameFailures = u.failures
+	}
+
+	if event.IP != "" {
+		ip := t.getOrCreateLocked(t.ips, event.IP, now)
+		if lockout := t.failLocked(ip, t.opts.IPLimit, now); lockout > 0 {
+			lockouts = append(lockouts, lockout)
+		}
+		event.IPFailures = ip.failures
+	}
+	t.mu.Unlock()
+
+	t.emit(ctx, event)
+
+	for _, lockout := range lockouts {
+		event.Type = EventLockout
+		event.RetryAfter = lockout
+		t.emit(ctx, event)
+	}
+
+	if t.captchaRequired(event) {
+		ctx.Header(t.opts.CaptchaHeader, "required")
+	}
+}
+
+// Succeed records a successful sign in of the "username" from the current client,
+// it resets the failures of the username. The failures of the client's IP
+// are not reset, so an attacker cannot reset them through a known account.
+func (t *Tracker) Succeed(ctx *context.Context, username string) {
+	event := Event{Type: EventSuccess, Time: time.Now(), Username: username, IP: t.opts.IP(ctx)}
+
+	t.mu.Lock()
+	delete(t.usernames, username)
+	if ip, ok := t.ips[event.IP]; ok {
+		event.IPFailures = ip.failures
+	}
+	t.mu.Unlock()
+
+	t.emit(ctx, event)
+}
+
+// Reset clears the failures and the lockout of the "username", e.g. after a password reset.
+func (t *Tracker) Reset(username string) {
+	t.mu.Lock()
+	delete(t.usernames, username)
+	t.mu.Unlock()
+}
+
+// ResetIP clears the failures and the lockout of a client IP.
+func (t *Tracker) ResetIP(ip string) {
+	t.mu.Lock()
+	delete(t.ips, ip)
+	t.mu.Unlock()
+}
+
+func (t *Tracker) captchaRequired(event Event) bool {
+	return t.opts.Captcha != nil &&
+		(event.UsernameFailures >= t.opts.CaptchaAfter || event.IPFailures >= t.opts.CaptchaAfter)
+}
+
+// failLocked records a failure and returns the new lockout duration, if any.
+func (t *Tracker) failLocked(a *attempts, limit int, now time.Time) time.Duration {
+	a.failures++
+	a.last = now
+
+	if limit <= 0 || a.failures < limit {
+		return 0
+	}
+
+	// 1st lockout: Lockout, 2nd: 2*Lockout, 3rd: 4*Lockout... up to MaxLockout.
+	lockout := t.opts.MaxLockout
+	if n := a.failures - limit; n < 32 {
+		if d := t.opts.Lockout << uint(n); d > 0 && d < lockout {
+			lockout = d
+		}
+	}
+
+	a.lockedUntil = now.Add(lockout)
+	return lockout
+}
+
+// getLocked returns the attempts of a key or nil, expired ones are removed.
+func (t *Tracker) getLocked(m map[string]*attempts, key string, now time.Time) *attempts {
+	if key == "" {
+		return nil
+	}
+
+	a, ok := m[key]
+	if !ok {
+		return nil
+	}
+
+	if a.expired(now, t.opts.Window) {
+		delete(m, key)
+		return nil
+	}
+
+	return a
+}
+
+// getOrCreateLocked returns the attempts of a key, a new entry is stored if
+// there is room for it. When all the MaxEntries are locked out the new entry is not stored.
+func (t *Tracker) getOrCreateLocked(m map[string]*attempts, key string, now time.Time) *attempts {
+	a := t.getLocked(m, key, now)
+	if a == nil {
+		a = new(attempts)
+		if t.opts.MaxEntries <= 0 || len(m) < t.opts.MaxEntries || evictLocked(m, now) {
+			m[key] = a
+		}
+	}
+
+	return a
+}
+
+// evictLocked removes an entry which is not locked out and reports whether it did.
+// The map iteration order is random, so an attacker cannot choose the removed entry.
+func evictLocked(m map[string]*attempts, now time.Time) bool {
+	for key, a := range m {
+		if !a.lockedUntil.After(now) {
+			delete(m, key)
+			return true
+		}
+	}
+
+	return false
+}
+
+func (a *attempts) expired(now time.Time, window time.Duration) bool {
+	return !a.lockedUntil.After(now) && now.Sub(a.last) > window
+}
+
+func (a *attempts) failuresOrZero() int {
+	if a == nil {
+		return 0
+	}
+
+	return a.failures
+}
+
+func (a *attempts) retryAfter(now time.Time) time.Duration {
+	if a == nil || !a.lockedUntil.After(now) {
+		return 0
+	}
+
+	return a.lockedUntil.Sub(now)
+}
+
+func (t *Tracker) emit(ctx *context.Context, event Event) {
+	if t.opts.OnEvent != nil {
+		t.opts.OnEvent(ctx, event)
+	}
+}
+
+// runGC runs a function in a separate go routine
+// every x duration to clear the expired entries.
+func (t *Tracker) runGC(ctx stdContext.Context, every time.Duration) {
+	if ctx == nil {
+		ctx = stdContext.Background()
+	}
+
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			t.gc()
+		}
+	}
+}
+
+// gc removes the expired entries and returns their number.
+func (t *Tracker) gc() int {
+	now := time.Now()
+	n := 0
+
+	t.mu.Lock()
+	for _, m := range []map[string]*attempts{t.usernames, t.ips} {
+		for key, a := range m {
+			if a.expired(now, t.opts.Window) {
+				delete(m, key)
+				n++
+			}
+		}
+	}
+	t.mu.Unlock()
+
+	return n
+}
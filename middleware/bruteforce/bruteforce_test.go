@@ -0,0 +1,244 @@
+package bruteforce
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+type eventRecorder struct {
+	mu     sync.Mutex
+	events []Event
+}
+
+func (r *eventRecorder) record(_ *context.Context, event Event) {
+	r.mu.Lock()
+	r.events = append(r.events, event)
+	r.mu.Unlock()
+}
+
+// pop returns and clears the recorded events.
+func (r *eventRecorder) pop() []Event {
+	r.mu.Lock()
+	events := r.events
+	r.events = nil
+	r.mu.Unlock()
+	return events
+}
+
+func newLoginApp(t *Tracker) *iris.Application {
+	app := iris.New()
+	app.Post("/login", func(ctx iris.Context) {
+		username := ctx.URLParam("username")
+		if err := t.Check(ctx, username); err != nil {
+			if errors.Is(err, ErrLocked) {
+				ctx.StopWithStatus(iris.StatusTooManyRequests)
+				return
+			}
+
+			ctx.StopWithStatus(iris.StatusUnauthorized)
+			return
+		}
+
+		if ctx.URLParam("password") != username+"_pass" {
+			t.Fail(ctx, username)
+			ctx.StopWithStatus(iris.StatusUnauthorized)
+			return
+		}
+
+		t.Succeed(ctx, username)
+		ctx.WriteString("OK")
+	})
+
+	return app
+}
+
+func testIP(ctx *context.Context) string {
+	return ctx.GetHeader("X-IP")
+}
+
+func TestTracker(t *testing.T) {
+	recorder := new(eventRecorder)
+	tracker := New(Options{
+		UsernameLimit: 3,
+		IPLimit:       10,
+		Lockout:       time.Minute,
+		MaxLockout:    4 * time.Minute,
+		Captcha: func(ctx *context.Context) bool {
+			return ctx.URLParam("captcha") == "ok"
+		},
+		CaptchaAfter: 2,
+		IP:           testIP,
+		OnEvent:      recorder.record,
+	})
+
+	e := httptest.New(t, newLoginApp(tracker))
+	login := func(password, captcha string) *httptest.Request {
+		return e.POST("/login").WithHeader("X-IP", "1.1.1.1").
+			WithQuery("username", "kataras").WithQuery("password", password).WithQuery("captcha", captcha)
+	}
+	unlock := func() {
+		tracker.mu.Lock()
+		tracker.usernames["kataras"].lockedUntil = time.Time{}
+		tracker.mu.Unlock()
+	}
+
+	login("invalid", "").Expect().Status(httptest.StatusUnauthorized).Header(DefaultCaptchaHeader).IsEmpty()
+	login("invalid", "").Expect().Status(httptest.StatusUnauthorized).Header(DefaultCaptchaHeader).IsEqual("required")
+	// CAPTCHA required.
+	login("kataras_pass", "").Expect().Status(httptest.StatusUnauthorized).Header(DefaultCaptchaHeader).IsEqual("required")
+	login("kataras_pass", "invalid").Expect().Status(httptest.StatusUnauthorized)
+	// Third failure locks out the username.
+	login("invalid", "ok").Expect().Status(httptest.StatusUnauthorized)
+	login("kataras_pass", "ok").Expect().Status(httptest.StatusTooManyRequests).Header("Retry-After").IsEqual("60")
+
+	expected := []Event{
+		{Type: EventFailure, Username: "kataras", IP: "1.1.1.1", UsernameFailures: 1, IPFailures: 1},
+		{Type: EventFailure, Username: "kataras", IP: "1.1.1.1", UsernameFailures: 2, IPFailures: 2},
+		{Type: EventCaptchaFailed, Username: "kataras", IP: "1.1.1.1", UsernameFailures: 2, IPFailures: 2},
+		{Type: EventCaptchaFailed, Username: "kataras", IP: "1.1.1.1", UsernameFailures: 2, IPFailures: 2},
+		{Type: EventFailure, Username: "kataras", IP: "1.1.1.1", UsernameFailures: 3, IPFailures: 3},
+		{Type: EventLockout, Username: "kataras", IP: "1.1.1.1", UsernameFailures: 3, IPFailures: 3, RetryAfter: time.Minute},
+		{Type: EventBlocked, Username: "kataras", IP: "1.1.1.1", UsernameFailures: 3, IPFailures: 3},
+	}
+	events := recorder.pop()
+	if len(events) != len(expected) {
+		t.Fatalf("expected %d events but got %d: %#+v", len(expected), len(events), events)
+	}
+	for i, event := range events {
+		if event.Time.IsZero() {
+			t.Fatalf("[%d] expected event time", i)
+		}
+		event.Time = time.Time{}
+		if event.Type == EventBlocked {
+			if event.RetryAfter <= 0 || event.RetryAfter > time.Minute {
+				t.Fatalf("[%d] expected retry after of the remaining lockout but got: %s", i, event.RetryAfter)
+			}
+			event.RetryAfter = 0
+		}
+
+		if event != expected[i] {
+			t.Fatalf("[%d] expected event:\n%#+v\nbut got:\n%#+v", i, expected[i], event)
+		}
+	}
+
+	// Each next failure doubles the lockout, up to the MaxLockout.
+	for _, lockout := range []time.Duration{2 * time.Minute, 4 * time.Minute, 4 * time.Minute} {
+		unlock()
+		login("invalid", "ok").Expect().Status(httptest.StatusUnauthorized)
+
+		events = recorder.pop()
+		if last := events[len(events)-1]; last.Type != EventLockout || last.RetryAfter != lockout {
+			t.Fatalf("expected lockout of %s but got: %#+v", lockout, last)
+		}
+	}
+
+	login("kataras_pass", "ok").Expect().Status(httptest.StatusTooManyRequests)
+	unlock()
+	login("kataras_pass", "ok").Expect().Status(httptest.StatusOK).Body().IsEqual("OK")
+
+	events = recorder.pop()
+	if last := events[len(events)-1]; last.Type != EventSuccess || last.IPFailures != 6 {
+		t.Fatalf("expected success event with the IP failures but got: %#+v", last)
+	}
+
+	// The username failures are reset on success but not the IP ones.
+	if tracker.usernames["kataras"] != nil {
+		t.Fatal("expected username failures to be reset on success")
+	}
+	login("kataras_pass", "").Expect().Status(httptest.StatusUnauthorized).Header(DefaultCaptchaHeader).IsEqual("required")
+
+	login("invalid", "ok").Expect().Status(httptest.StatusUnauthorized)
+	tracker.Reset("kataras")
+	if tracker.usernames["kataras"] != nil {
+		t.Fatal("expected username failures to be reset")
+	}
+}
+
+func TestTrackerIPLimit(t *testing.T) {
+	tracker := New(Options{UsernameLimit: -1, IPLimit: 2, IP: testIP})
+	e := httptest.New(t, newLoginApp(tracker))
+	login := func(ip, username, password string) *httptest.Request {
+		return e.POST("/login").WithHeader("X-IP", ip).
+			WithQuery("username", username).WithQuery("password", password)
+	}
+
+	// Credential stuffing: different usernames from the same IP.
+	login("1.1.1.1", "kataras", "invalid").Expect().Status(httptest.StatusUnauthorized)
+	login("1.1.1.1", "makis", "invalid").Expect().Status(httptest.StatusUnauthorized)
+	login("1.1.1.1", "george", "george_pass").Expect().Status(httptest.StatusTooManyRequests).Header("Retry-After").IsEqual("60")
+	login("2.2.2.2", "george", "george_pass").Expect().Status(httptest.StatusOK)
+
+	tracker.ResetIP("1.1.1.1")
+	login("1.1.1.1", "george", "george_pass").Expect().Status(httptest.StatusOK)
+}
+
+func TestTrackerLockedError(t *testing.T) {
+	tracker := New(Options{UsernameLimit: 1, IP: testIP})
+
+	app := iris.New()
+	app.Get("/", func(ctx iris.Context) {
+		tracker.Fail(ctx, "kataras")
+
+		err := tracker.Check(ctx, "kataras")
+		var lockedErr *LockedError
+		if !errors.As(err, &lockedErr) || !errors.Is(err, ErrLocked) {
+			t.Fatalf("expected a locked error but got: %v", err)
+		}
+
+		if lockedErr.Username != "kataras" || lockedErr.IP != "" || lockedErr.RetryAfter <= 0 {
+			t.Fatalf("unexpected locked error: %#+v", lockedErr)
+		}
+
+		if err = tracker.Check(ctx, "makis"); err != nil {
+			t.Fatalf("expected other usernames to not be locked out but got: %v", err)
+		}
+	})
+
+	httptest.New(t, app).GET("/").WithHeader("X-IP", "1.1.1.1").Expect().Status(httptest.StatusOK)
+}
+
+func TestTrackerGC(t *testing.T) {
+	tracker := New(Options{UsernameLimit: 1, Lockout: time.Millisecond, Window: time.Millisecond, IP: testIP})
+
+	app := iris.New()
+	app.Get("/", func(ctx iris.Context) {
+		tracker.Fail(ctx, "kataras")
+	})
+	httptest.New(t, app).GET("/").WithHeader("X-IP", "1.1.1.1").Expect().Status(httptest.StatusOK)
+
+	time.Sleep(5 * time.Millisecond)
+	if expected, got := 2, tracker.gc(); expected != got {
+		t.Fatalf("expected %d expired entries but got %d", expected, got)
+	}
+}
+
+func TestTrackerMaxEntries(t *testing.T) {
+	tracker := New(Options{UsernameLimit: 2, IPLimit: -1, MaxEntries: 2, IP: testIP})
+	e := httptest.New(t, newLoginApp(tracker))
+
+	// Lock out "kataras".
+	for i := 0; i < 2; i++ {
+		e.POST("/login").WithQuery("username", "kataras").WithHeader("X-IP", "1.1.1.1").Expect().Status(httptest.StatusUnauthorized)
+	}
+
+	// Attacker-chosen usernames do not grow the map and do not evict the lockout.
+	for _, username := range []string{"a", "b", "c", "d"} {
+		e.POST("/login").WithQuery("username", username).WithHeader("X-IP", "1.1.1.1").Expect().Status(httptest.StatusUnauthorized)
+	}
+
+	tracker.mu.Lock()
+	n := len(tracker.usernames)
+	tracker.mu.Unlock()
+	if n > 2 {
+		t.Fatalf("expected at most 2 tracked usernames but got %d", n)
+	}
+
+	e.POST("/login").WithQuery("username", "kataras").WithQuery("password", "kataras_pass").WithHeader("X-IP", "1.1.1.1").
+		Expect().Status(httptest.StatusTooManyRequests)
+}
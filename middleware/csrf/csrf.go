@@ -0,0 +1,567 @@
+// Package csrf provides a Cross-Site Request Forgery protection middleware.
+//
+// It supports two modes:
+//   - Synchronizer: the token is stored in the client's server-side session (see the sessions package).
+//   - DoubleSubmit: the token is stored in a signed cookie, no server-side state is required.
+//     Set the Options.SessionID to bind the cookie to the client's session (signed double submit).
+//
+// Unsafe requests (any method except GET, HEAD, OPTIONS and TRACE) must send the token
+// through the request header (X-CSRF-Token) or the form field (csrf_token)
+// and, if present, their Origin or Referer header should match the current host or a trusted origin.
+package csrf
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"html/template"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/core/router"
+	"github.com/kataras/iris/v12/sessions"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/csrf.*", "iris.csrf")
+}
+
+// Mode is the way the CSRF token is stored, see the Options.Mode field.
+type Mode uint8
+
+const (
+	// DoubleSubmit stores the token in a cookie signed with the Options.Secret.
+	// It's stateless, the client should send the token back
+	// through the header or the form field as well.
+	//
+	// Without the Options.SessionID the cookie is not bound to the client, so an attacker
+	// who can set cookies on the application's domain (e.g. through a compromised subdomain
+	// or a man in the middle on plain HTTP) can inject their own valid cookie and token pair.
+	// Set the Options.SessionID or prefer the Synchronizer mode in that case.
+	DoubleSubmit Mode = iota
+	// Synchronizer stores the token in the client's session.
+	// It requires the sessions middleware to be registered before the CSRF one.
+	Synchronizer
+)
+
+// The default values of the Options.
+const (
+	DefaultCookieName  = "_csrf"
+	DefaultHeaderName  = "X-CSRF-Token"
+	DefaultFieldName   = "csrf_token"
+	DefaultSessionKey  = "csrf_token"
+	DefaultViewDataKey = "csrf_token"
+	// TemplateFuncName is the name of the template function registered by the RegisterFuncs method,
+	// e.g. {{ csrf_field .csrf_token }}.
+	TemplateFuncName = "csrf_field"
+)
+
+const (
+	tokenLength      = 32
+	tokenContextKey  = "iris.csrf.token"
+	exemptContextKey = "iris.csrf.exempt"
+)
+
+var (
+	// ErrTokenMissing is passed to the ErrorHandler when the request has no token.
+	ErrTokenMissing = errors.New("csrf: token is missing")
+	// ErrTokenInvalid is passed to the ErrorHandler when the request's token does not match.
+	ErrTokenInvalid = errors.New("csrf: token is invalid")
+	// ErrOriginMismatch is passed to the ErrorHandler when the request's Origin
+	// or Referer header does not match the current host or a trusted origin.
+	ErrOriginMismatch = errors.New("csrf: origin does not match")
+	// ErrRefererMissing is passed to the ErrorHandler when a secure request has neither Origin nor Referer header.
+	ErrRefererMissing = errors.New("csrf: referer is missing")
+	// ErrNoSession is passed to the ErrorHandler when the Synchronizer mode
+	// is used without a session, the sessions middleware should be registered first.
+	ErrNoSession = errors.New("csrf: session is missing")
+)
+
+// DefaultErrorHandler is the default ErrorHandler.
+// It sends 500 Internal Server Error on ErrNoSession and 403 Forbidden otherwise.
+var DefaultErrorHandler = func(ctx *context.Context, err error) {
+	if errors.Is(err, ErrNoSession) {
+		ctx.StopWithError(http.StatusInternalServerError, err)
+		return
+	}
+
+	ctx.StopWithError(http.StatusForbidden, err)
+}
+
+// Options holds the configuration for the CSRF middleware.
+type Options struct {
+	// Mode is the way the token is stored.
+	// Defaults to DoubleSubmit.
+	Mode Mode
+	// Secret is the key which signs the token cookie of the DoubleSubmit mode.
+	// Required for the DoubleSubmit mode, it should be at least 32 random bytes
+	// and the same across the application's instances.
+	Secret []byte
+	// SessionID returns the identifier of the client's session, e.g. the sessions.Session ID
+	// or the ID of the authenticated user's token. It's signed along with the token cookie
+	// of the DoubleSubmit mode, so a cookie is valid for the session it was issued for only
+	// and a new token is issued when the session changes (e.g. after sign in).
+	// An empty identifier means no session.
+	//
+	// Usage:
+	//
+	//	SessionID: func(ctx iris.Context) string { return sessions.Get(ctx).ID() }
+	//
+	// Optional but recommended, see the DoubleSubmit mode.
+	SessionID func(ctx *context.Context) string
+	// CookieName is the name of the token cookie of the DoubleSubmit mode.
+	// Defaults to "_csrf".
+	CookieName string
+	// CookieOptions are applied to the token cookie, e.g. context.CookieSameSite(http.SameSiteStrictMode).
+	// The cookie is HttpOnly, SameSite=Lax and Secure (if the request is secure) by default.
+	// Set context.CookieHTTPOnly(false) if the client-side scripts should read
+	// and send the cookie's value through the header.
+	CookieOptions []context.CookieOption
+	// SessionKey is the session key of the token of the Synchronizer mode.
+	// Defaults to "csrf_token".
+	SessionKey string
+	// HeaderName is the request header which the token is extracted from.
+	// Defaults to "X-CSRF-Token".
+	HeaderName string
+	// FieldName is the form field which the token is extracted from
+	// when the request header is missing.
+	// Defaults to "csrf_token".
+	FieldName string
+	// ViewDataKey is the view data key of the token (see Context.ViewData).
+	// Defaults to "csrf_token".
+	ViewDataKey string
+	// TrustedOrigins is a list of origins, except the current host, which are allowed
+	// to send unsafe requests, e.g. "https://example.com".
+	// An origin starting with "*." allows all the subdomains of a domain, e.g. "https://*.example.com".
+	TrustedOrigins []string
+	// Skip reports whether the CSRF protection should be skipped for a request.
+	// See the CSRF.Exempt method to skip specific routes.
+	Skip func(ctx *context.Context) bool
+	// ErrorHandler is fired when a request is rejected.
+	// Defaults to the DefaultErrorHandler.
+	ErrorHandler func(ctx *context.Context, err error)
+}
+
+// CSRF is the Cross-Site Request Forgery protection middleware.
+// Create a new one through the New package-level function.
+type CSRF struct {
+	opts   Options
+	exempt map[string]struct{}
+}
+
+// New returns a new CSRF protection middleware.
+// Register its Handler method after the sessions middleware, if the Synchronizer mode is used.
+//
+// Usage:
+//
+//	c := csrf.New(csrf.Options{Secret: secret})
+//	app.Use(c.Handler)
+//	c.Exempt(app.Post("/webhooks/payments", handlePayment))
+//
+//	tmpl := iris.HTML("./views", ".html")
+//	c.RegisterFuncs(tmpl)
+//	app.RegisterView(tmpl)
+//
+// And in the template: <form method="POST">{{ csrf_field .csrf_token }}...</form>
+func New(opts Options) *CSRF {
+	if opts.Mode == DoubleSubmit && len(opts.Secret) == 0 {
+		panic("csrf: secret is required for the double submit mode")
+	}
+
+	if opts.CookieName == "" {
+		opts.CookieName = DefaultCookieName
+	}
+
+	if opts.SessionKey == "" {
+		opts.SessionKey = DefaultSessionKey
+	}
+
+	if opts.HeaderName == "" {
+		opts.HeaderName = DefaultHeaderName
+	}
+
+	if opts.FieldName == "" {
+		opts.FieldName = DefaultFieldName
+	}
+
+	if opts.ViewDataKey == "" {
+		opts.ViewDataKey = DefaultViewDataKey
+	}
+
+	if opts.ErrorHandler == nil {
+		opts.ErrorHandler = DefaultErrorHandler
+	}
+
+	for i, origin := range opts.TrustedOrigins {
+		opts.TrustedOrigins[i] = strings.ToLower(strings.TrimSuffix(origin, "/"))
+	}
+
+	return &CSRF{
+		opts:   opts,
+		exempt: make(map[string]struct{}),
+	}
+}
+
+// Exempt disables the CSRF protection for the given routes, e.g. webhooks.
+// It should be called before the server runs.
+//
+// Usage:
+//
+//	c.Exempt(app.Post("/webhooks", handler))
+func (c *CSRF) Exempt(routes ...*router.Route) *CSRF {
+	for _, r := range routes {
+		if r != nil {
+			c.exempt[r.Name] = struct{}{}
+		}
+	}
+
+	return c
+}
+
+// ExemptHandler disables the CSRF protection for the current request.
+// It should run before the CSRF Handler, e.g. app.UseRouter(conditionalExemptHandler).
+// See the Exempt method and the Options.Skip field too.
+func ExemptHandler(ctx *context.Context) {
+	ctx.Values().Set(exemptContextKey, struct{}{})
+	ctx.Next()
+}
+
+func (c *CSRF) isExempt(ctx *context.Context) bool {
+	if ctx.Values().Get(exemptContextKey) != nil {
+		return true
+	}
+
+	if c.opts.Skip != nil && c.opts.Skip(ctx) {
+		return true
+	}
+
+	if len(c.exempt) > 0 {
+		if r := ctx.GetCurrentRoute(); r != nil {
+			_, ok := c.exempt[r.Name()]
+			return ok
+		}
+	}
+
+	return false
+}
+
+// Handler is the CSRF middleware.
+// It makes sure that the client has a token and validates the unsafe requests.
+// The masked token is available through the Token package-level function and the view data.
+func (c *CSRF) Handler(ctx *context.Context) {
+	token, err := c.loadOrCreate(ctx)
+	if err != nil {
+		c.opts.ErrorHandler(ctx, err)
+		return
+	}
+
+	c.setToken(ctx, token)
+
+	if isSafeMethod(ctx.Method()) || c.isExempt(ctx) {
+		ctx.Next()
+		return
+	}
+
+	if err = c.checkOrigin(ctx); err == nil {
+		err = c.checkToken(ctx, token)
+	}
+
+	if err != nil {
+		ctx.Application().Logger().Debugf("%s %s: %v", ctx.Method(), ctx.Path(), err)
+		c.opts.ErrorHandler(ctx, err)
+		return
+	}
+
+	ctx.Next()
+}
+
+// Rotate replaces the client's token with a new one.
+// Call it when the privileges of the client change, e.g. right after sign in,
+// to protect against session fixation. The previously rendered tokens become invalid.
+func (c *CSRF) Rotate(ctx *context.Context) error {
+	token, err := newToken()
+	if err != nil {
+		return err
+	}
+
+	if err = c.save(ctx, token); err != nil {
+		return err
+	}
+
+	c.setToken(ctx, token)
+	return nil
+}
+
+func (c *CSRF) setToken(ctx *context.Context, token []byte) {
+	ctx.Values().Set(tokenContextKey, token)
+	ctx.ViewData(c.opts.ViewDataKey, maskToken(token))
+}
+
+// loadOrCreate returns the client's token or stores a new one.
+func (c *CSRF) loadOrCreate(ctx *context.Context) ([]byte, error) {
+	switch c.opts.Mode {
+	case Synchronizer:
+		sess := sessions.Get(ctx)
+		if sess == nil {
+			return nil, ErrNoSession
+		}
+
+		if token, err := decodeToken(sess.GetString(c.opts.SessionKey)); err == nil {
+			return token, nil
+		}
+	default:
+		if token, ok := c.verifyCookie(ctx, ctx.GetCookie(c.opts.CookieName)); ok {
+			return token, nil
+		}
+	}
+
+	token, err := newToken()
+	if err != nil {
+		return nil, err
+	}
+
+	return token, c.save(ctx, token)
+}
+
+func (c *CSRF) save(ctx *context.Context, token []byte) error {
+	switch c.opts.Mode {
+	case Synchronizer:
+		sess := sessions.Get(ctx)
+		if sess == nil {
+			return ErrNoSession
+		}
+
+		sess.Set(c.opts.SessionKey, encodeToken(token))
+	default:
+		cookie := &http.Cookie{
+			Name:     c.opts.CookieName,
+			Value:    c.signCookie(ctx, token),
+			Path:     "/",
+			HttpOnly: true,
+			SameSite: http.SameSiteLaxMode,
+		}
+
+		ctx.UpsertCookie(cookie, append([]context.CookieOption{context.CookieSecure}, c.opts.CookieOptions...)...)
+	}
+
+	return nil
+}
+
+// signCookie returns the token and its signature: base64(token).base64(hmac(token + session id)).
+func (c *CSRF) signCookie(ctx *context.Context, token []byte) string {
+	return encodeToken(token) + "." + encodeToken(c.sign(ctx, token))
+}
+
+func (c *CSRF) verifyCookie(ctx *context.Context, value string) ([]byte, bool) {
+	encodedToken, encodedSignature, ok := strings.Cut(value, ".")
+	if !ok {
+		return nil, false
+	}
+
+	token, err := decodeToken(encodedToken)
+	if err != nil {
+		return nil, false
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(encodedSignature)
+	if err != nil || !hmac.Equal(signature, c.sign(ctx, token)) {
+		return nil, false
+	}
+
+	return token, true
+}
+
+// sign returns the signature of the token and the client's session identifier.
+// The token has a fixed length, so the session identifier is just appended.
+func (c *CSRF) sign(ctx *context.Context, token []byte) []byte {
+	h := hmac.New(sha256.New, c.opts.Secret)
+	h.Write(token)
+	if c.opts.SessionID != nil {
+		h.Write([]byte(c.opts.SessionID(ctx)))
+	}
+	return h.Sum(nil)
+}
+
+// checkOrigin validates the Origin header or, if missing, the Referer one.
+// The Referer is required for secure requests only, as some clients omit it on plain HTTP.
+func (c *CSRF) checkOrigin(ctx *context.Context) error {
+	origin := ctx.GetHeader("Origin")
+	if origin == "" {
+		referer := ctx.GetHeader("Referer")
+		if referer == "" {
+			if ctx.IsSSL() {
+				return ErrRefererMissing
+			}
+
+			return nil
+		}
+
+		u, err := url.Parse(referer)
+		if err != nil || u.Host == "" {
+			return ErrOriginMismatch
+		}
+
+		origin = u.Scheme + "://" + u.Host
+	}
+
+	origin = strings.ToLower(origin)
+	if origin == strings.ToLower(ctx.Scheme()+ctx.Host()) {
+		return nil
+	}
+
+	for _, trusted := range c.opts.TrustedOrigins {
+		if originMatches(trusted, origin) {
+			return nil
+		}
+	}
+
+	return ErrOriginMismatch
+}
+
+func originMatches(trusted, origin string) bool {
+	scheme, host, ok := strings.Cut(trusted, "://*.")
+	if !ok {
+		return trusted == origin
+	}
+
+	// https://*.example.com matches https://api.example.com but not https://example.com.
+	return strings.HasPrefix(origin, scheme+"://") && strings.HasSuffix(origin, "."+host)
+}
+
+// checkToken compares the request's token with the client's one.
+func (c *CSRF) checkToken(ctx *context.Context, token []byte) error {
+	value := ctx.GetHeader(c.opts.HeaderName)
+	if value == "" {
+		value = ctx.FormValue(c.opts.FieldName)
+		if value == "" {
+			return ErrTokenMissing
+		}
+	}
+
+	if c.opts.Mode == DoubleSubmit {
+		// Allow scripts to send the cookie's value as it is (see the Options.CookieOptions field).
+		if cookieToken, ok := c.verifyCookie(ctx, value); ok {
+			value = encodeToken(cookieToken)
+		}
+	}
+
+	requestToken, err := unmaskToken(value)
+	if err != nil || subtle.ConstantTimeCompare(requestToken, token) != 1 {
+		return ErrTokenInvalid
+	}
+
+	return nil
+}
+
+// Token returns the client's token of the current request, masked with a new random pad
+// on each call so the rendered tokens are not the same (see BREACH attack).
+// Returns an empty string if the CSRF middleware did not run.
+func Token(ctx *context.Context) string {
+	if token, ok := ctx.Values().Get(tokenContextKey).([]byte); ok {
+		return maskToken(token)
+	}
+
+	return ""
+}
+
+// TemplateField returns the hidden input element of the current request's token.
+func (c *CSRF) TemplateField(ctx *context.Context) template.HTML {
+	return c.templateFunc(Token(ctx))
+}
+
+func (c *CSRF) templateFunc(token string) template.HTML {
+	return template.HTML(fmt.Sprintf(`<input type="hidden" name="%s" value="%s">`,
+		template.HTMLEscapeString(c.opts.FieldName), template.HTMLEscapeString(token)))
+}
+
+// RegisterFuncs registers the "csrf_field" template function to the given view engines.
+// It accepts the token, which is available through the view data, and
+// renders the hidden input element of the form, e.g.
+//   - html, blocks and ace: {{ csrf_field .csrf_token }}
+//   - django: {{ csrf_field(csrf_token)|safe }}
+//   - jet: {{ csrf_field(.csrf_token)|raw }}
+//   - handlebars: {{{ csrf_field csrf_token }}}
+//
+// Call it before the application's Build (e.g. before Listen).
+func (c *CSRF) RegisterFuncs(engines ...context.ViewEngineFuncer) {
+	for _, e := range engines {
+		e.AddFunc(TemplateFuncName, c.templateFunc)
+	}
+}
+
+func isSafeMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return true
+	default:
+		return false
+	}
+}
+
+func newToken() ([]byte, error) {
+	token := make([]byte, tokenLength)
+	if _, err := rand.Read(token); err != nil {
+		return nil, err
+	}
+
+	return token, nil
+}
+
+func encodeToken(token []byte) string {
+	return base64.RawURLEncoding.EncodeToString(token)
+}
+
+func decodeToken(s string) ([]byte, error) {
+	token, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(token) != tokenLength {
+		return nil, ErrTokenInvalid
+	}
+
+	return token, nil
+}
+
+// maskToken returns base64(pad + (pad XOR token)).
+func maskToken(token []byte) string {
+	masked := make([]byte, 2*tokenLength)
+	if _, err := rand.Read(masked[:tokenLength]); err != nil {
+		return encodeToken(token)
+	}
+
+	for i := 0; i < tokenLength; i++ {
+		masked[tokenLength+i] = masked[i] ^ token[i]
+	}
+
+	return base64.RawURLEncoding.EncodeToString(masked)
+}
+
+// unmaskToken accepts a masked or an unmasked token.
+func unmaskToken(s string) ([]byte, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+
+	switch len(b) {
+	case tokenLength:
+		return b, nil
+	case 2 * tokenLength:
+		token := make([]byte, tokenLength)
+		for i := 0; i < tokenLength; i++ {
+			token[i] = b[i] ^ b[tokenLength+i]
+		}
+
+		return token, nil
+	default:
+		return nil, ErrTokenInvalid
+	}
+}
@@ -0,0 +1,153 @@
+package csrf_test
+
+import (
+	"regexp"
+	"testing"
+	"testing/fstest"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/csrf"
+	"github.com/kataras/iris/v12/sessions"
+)
+
+var fieldRegexp = regexp.MustCompile(`<input type="hidden" name="csrf_token" value="([^"]+)">`)
+
+func newApp(opts csrf.Options, uses ...iris.Handler) (*iris.Application, *csrf.CSRF) {
+	app := iris.New()
+	c := csrf.New(opts)
+
+	tmpl := iris.HTML(fstest.MapFS{
+		"form.html": {Data: []byte(`<form method="POST">{{ csrf_field .csrf_token }}</form>`)},
+	}, ".html")
+	c.RegisterFuncs(tmpl)
+	app.RegisterView(tmpl)
+
+	app.Use(uses...)
+	app.Use(c.Handler)
+	app.Get("/form", func(ctx iris.Context) {
+		ctx.View("form.html")
+	})
+	app.Get("/token", func(ctx iris.Context) {
+		ctx.WriteString(csrf.Token(ctx))
+	})
+	app.Post("/form", func(ctx iris.Context) {
+		ctx.WriteString("OK")
+	})
+	c.Exempt(app.Post("/webhook", func(ctx iris.Context) {
+		ctx.WriteString("OK")
+	}))
+
+	return app, c
+}
+
+func testCSRF(t *testing.T, app *iris.Application) {
+	t.Helper()
+
+	e := httptest.New(t, app, httptest.URL("http://localhost"))
+
+	body := e.GET("/form").Expect().Status(httptest.StatusOK).Body().Raw()
+	matches := fieldRegexp.FindStringSubmatch(body)
+	if len(matches) != 2 {
+		t.Fatalf("expected a hidden input but got: %q", body)
+	}
+	fieldToken := matches[1]
+
+	headerToken := e.GET("/token").Expect().Status(httptest.StatusOK).Body().NotEmpty().Raw()
+	if headerToken == fieldToken {
+		t.Fatal("expected tokens to be masked differently on each request")
+	}
+
+	// Missing and invalid tokens.
+	e.POST("/form").Expect().Status(httptest.StatusForbidden).Body().IsEqual(csrf.ErrTokenMissing.Error())
+	e.POST("/form").WithHeader(csrf.DefaultHeaderName, "invalid").Expect().Status(httptest.StatusForbidden).
+		Body().IsEqual(csrf.ErrTokenInvalid.Error())
+
+	// Form field and header.
+	e.POST("/form").WithFormField(csrf.DefaultFieldName, fieldToken).Expect().Status(httptest.StatusOK).Body().IsEqual("OK")
+	e.POST("/form").WithHeader(csrf.DefaultHeaderName, headerToken).Expect().Status(httptest.StatusOK)
+
+	// Origin and referer validation.
+	e.POST("/form").WithHeader(csrf.DefaultHeaderName, headerToken).WithHeader("Origin", "http://localhost").
+		Expect().Status(httptest.StatusOK)
+	e.POST("/form").WithHeader(csrf.DefaultHeaderName, headerToken).WithHeader("Origin", "http://evil.com").
+		Expect().Status(httptest.StatusForbidden).Body().IsEqual(csrf.ErrOriginMismatch.Error())
+	e.POST("/form").WithHeader(csrf.DefaultHeaderName, headerToken).WithHeader("Origin", "https://app.example.com").
+		Expect().Status(httptest.StatusOK)
+	e.POST("/form").WithHeader(csrf.DefaultHeaderName, headerToken).WithHeader("Referer", "http://evil.com/form").
+		Expect().Status(httptest.StatusForbidden)
+	e.POST("/form").WithHeader(csrf.DefaultHeaderName, headerToken).WithHeader("Referer", "http://localhost/form").
+		Expect().Status(httptest.StatusOK)
+
+	// Exempt routes.
+	e.POST("/webhook").Expect().Status(httptest.StatusOK)
+
+	// Tokens of other clients are not valid.
+	httptest.New(t, app, httptest.URL("http://localhost")).POST("/form").WithHeader(csrf.DefaultHeaderName, headerToken).
+		Expect().Status(httptest.StatusForbidden).Body().IsEqual(csrf.ErrTokenInvalid.Error())
+}
+
+func TestCSRFDoubleSubmit(t *testing.T) {
+	app, _ := newApp(csrf.Options{
+		Secret:         []byte("a secret of at least 32 bytes long"),
+		TrustedOrigins: []string{"https://*.example.com"},
+	})
+
+	testCSRF(t, app)
+
+	// A cookie signed with another secret is replaced.
+	other, _ := newApp(csrf.Options{Secret: []byte("another secret of at least 32 bytes")})
+	e := httptest.New(t, other, httptest.URL("http://localhost"))
+	forged := e.GET("/token").Expect().Status(httptest.StatusOK).Body().Raw()
+	e = httptest.New(t, app, httptest.URL("http://localhost"))
+	e.POST("/form").WithHeader(csrf.DefaultHeaderName, forged).Expect().Status(httptest.StatusForbidden)
+}
+
+func TestCSRFDoubleSubmitSessionID(t *testing.T) {
+	app, _ := newApp(csrf.Options{
+		Secret: []byte("a secret of at least 32 bytes long"),
+		SessionID: func(ctx iris.Context) string {
+			return ctx.GetHeader("X-Session")
+		},
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://localhost"))
+	token := e.GET("/token").WithHeader("X-Session", "a").Expect().Status(httptest.StatusOK).Body().Raw()
+	e.POST("/form").WithHeader("X-Session", "a").WithHeader(csrf.DefaultHeaderName, token).Expect().Status(httptest.StatusOK)
+
+	// The cookie and token pair of a session is not valid for another one.
+	e.POST("/form").WithHeader("X-Session", "b").WithHeader(csrf.DefaultHeaderName, token).
+		Expect().Status(httptest.StatusForbidden).Body().IsEqual(csrf.ErrTokenInvalid.Error())
+}
+
+func TestCSRFSynchronizer(t *testing.T) {
+	sess := sessions.New(sessions.Config{Cookie: "sessionid"})
+	app, _ := newApp(csrf.Options{
+		Mode:           csrf.Synchronizer,
+		TrustedOrigins: []string{"https://*.example.com"},
+	}, sess.Handler())
+
+	testCSRF(t, app)
+
+	noSession, _ := newApp(csrf.Options{Mode: csrf.Synchronizer})
+	httptest.New(t, noSession).GET("/form").Expect().Status(httptest.StatusInternalServerError)
+}
+
+func TestCSRFRotate(t *testing.T) {
+	app, c := newApp(csrf.Options{Secret: []byte("a secret of at least 32 bytes long")})
+	app.Post("/signin", func(ctx iris.Context) {
+		if err := c.Rotate(ctx); err != nil {
+			ctx.StopWithError(iris.StatusInternalServerError, err)
+			return
+		}
+
+		ctx.WriteString(csrf.Token(ctx))
+	})
+
+	e := httptest.New(t, app, httptest.URL("http://localhost"))
+	token := e.GET("/token").Expect().Status(httptest.StatusOK).Body().Raw()
+	newToken := e.POST("/signin").WithHeader(csrf.DefaultHeaderName, token).Expect().Status(httptest.StatusOK).Body().Raw()
+
+	e.POST("/form").WithHeader(csrf.DefaultHeaderName, token).Expect().Status(httptest.StatusForbidden)
+	e.POST("/form").WithHeader(csrf.DefaultHeaderName, newToken).Expect().Status(httptest.StatusOK)
+}
@@ -12,6 +12,8 @@ var (
 	ErrIssuedInTheFuture = jwt.ErrIssuedInTheFuture
 	ErrMissing           = jwt.ErrMissing
 	ErrMissingKey        = jwt.ErrMissingKey
+	ErrEmptyKid          = jwt.ErrEmptyKid
+	ErrUnknownKid        = jwt.ErrUnknownKid
 	ErrNotValidYet       = jwt.ErrNotValidYet
 	ErrTokenAlg          = jwt.ErrTokenAlg
 	ErrTokenForm         = jwt.ErrTokenForm
@@ -81,6 +83,25 @@ type (
 	SignOption = jwt.SignOption
 	// TokenPair is just a helper structure which holds both access and refresh tokens.
 	TokenPair = jwt.TokenPair
+
+	// PublicKey is the type alias for the public key of a signature algorithm.
+	PublicKey = jwt.PublicKey
+	// PrivateKey is the type alias for the private key of a signature algorithm.
+	PrivateKey = jwt.PrivateKey
+	// InjectFunc is the type alias for the payload encryption and decryption functions.
+	InjectFunc = jwt.InjectFunc
+	// Key holds a key pair and its "kid", see KeySet.
+	Key = jwt.Key
+	// Keys is a map of key id and a key pair, it completes the KeyResolver interface.
+	Keys = jwt.Keys
+	// HeaderWithKid represents a token's header which holds the "kid" and "alg" fields.
+	HeaderWithKid = jwt.HeaderWithKid
+	// JWKS represents a JSON Web Key Set.
+	JWKS = jwt.JWKS
+	// JWK represents a JSON Web Key.
+	JWK = jwt.JWK
+	// HTTPClient is the interface which fetches a remote JSON Web Key Set, see RemoteKeySet.
+	HTTPClient = jwt.HTTPClient
 )
 
 // Encryption algorithms.
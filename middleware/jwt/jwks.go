@@ -0,0 +1,141 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/kataras/jwt"
+)
+
+// RemoteKeySet is a KeyResolver which verifies tokens
+// against the JSON Web Key Set of a remote issuer.
+// The key set is cached and it is fetched again after the CacheDuration
+// or when a token is signed by an unknown key id, e.g. after a keys rotation of the issuer.
+//
+// Usage:
+//
+//	keys := jwt.NewRemoteKeySet("https://issuer.example.com/.well-known/jwks.json")
+//	verifier := jwt.NewVerifierWithKeys(keys, jwt.Expected{Issuer: "https://issuer.example.com"})
+type RemoteKeySet struct {
+	// URL of the JSON Web Key Set.
+	URL string
+	// Client is the HTTP Client which fetches the key set.
+	// Defaults to http.DefaultClient.
+	Client HTTPClient
+	// CacheDuration is the duration the fetched key set is used before fetched again.
+	// Defaults to 1 hour.
+	CacheDuration time.Duration
+	// MinRefreshInterval is the minimum duration between two fetches
+	// caused by unknown key ids, so invalid tokens cannot flood the issuer.
+	// It is also the duration to wait before fetching again after a failed fetch,
+	// meanwhile the last fetched key set, if any, is used even if the CacheDuration has passed.
+	// Defaults to 1 minute.
+	MinRefreshInterval time.Duration
+
+	mu        sync.RWMutex
+	keys      Keys
+	fetchedAt time.Time
+	failedAt  time.Time // the time of the last failed fetch.
+	fetchErr  error     // the error of the last failed fetch.
+
+	fetchMu sync.Mutex
+}
+
+// NewRemoteKeySet returns a new RemoteKeySet of the JSON Web Key Set "url".
+func NewRemoteKeySet(url string) *RemoteKeySet {
+	return &RemoteKeySet{
+		URL:                url,
+		Client:             http.DefaultClient,
+		CacheDuration:      time.Hour,
+		MinRefreshInterval: time.Minute,
+	}
+}
+
+// ValidateHeader completes the KeyResolver interface.
+// It resolves the key of the token's "kid" between the cached keys
+// and fetches the key set again when the cache has expired or the "kid" is unknown.
+func (s *RemoteKeySet) ValidateHeader(alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc, error) {
+	s.mu.RLock()
+	keys, fetchedAt, failedAt, fetchErr := s.keys, s.fetchedAt, s.failedAt, s.fetchErr
+	s.mu.RUnlock()
+
+	if keys != nil && time.Since(fetchedAt) < s.CacheDuration {
+		verifyAlg, publicKey, decrypt, err := keys.ValidateHeader(alg, headerDecoded)
+		if !errors.Is(err, jwt.ErrUnknownKid) || time.Since(fetchedAt) < s.MinRefreshInterval {
+			return verifyAlg, publicKey, decrypt, err
+		}
+	}
+
+	if time.Since(failedAt) < s.MinRefreshInterval { // back off after a failed fetch.
+		if keys != nil {
+			return keys.ValidateHeader(alg, headerDecoded)
+		}
+
+		return nil, nil, nil, fetchErr
+	}
+
+	keys, err := s.refresh(fetchedAt, false)
+	if err != nil {
+		if keys != nil { // keep serving the last fetched keys.
+			return keys.ValidateHeader(alg, headerDecoded)
+		}
+
+		return nil, nil, nil, err
+	}
+
+	return keys.ValidateHeader(alg, headerDecoded)
+}
+
+// Refresh fetches the key set.
+func (s *RemoteKeySet) Refresh() error {
+	_, err := s.refresh(time.Time{}, true)
+	return err
+}
+
+// refresh fetches the key set, unless "force" is false and it was fetched
+// by another request after the "fetchedAt" one or another request's fetch has just failed.
+// On failure it returns the last fetched keys, if any, along with the error.
+func (s *RemoteKeySet) refresh(fetchedAt time.Time, force bool) (Keys, error) {
+	s.fetchMu.Lock()
+	defer s.fetchMu.Unlock()
+
+	s.mu.RLock()
+	keys, lastFetchedAt, failedAt, fetchErr := s.keys, s.fetchedAt, s.failedAt, s.fetchErr
+	s.mu.RUnlock()
+
+	if !force {
+		if lastFetchedAt.After(fetchedAt) {
+			return keys, nil
+		}
+
+		if time.Since(failedAt) < s.MinRefreshInterval { // another request failed meanwhile.
+			return keys, fetchErr
+		}
+	}
+
+	set, err := jwt.FetchJWKS(s.Client, s.URL)
+	if err != nil {
+		err = fmt.Errorf("jwt: jwks: %w", err)
+
+		s.mu.Lock()
+		s.failedAt = time.Now()
+		s.fetchErr = err
+		s.mu.Unlock()
+
+		return keys, err
+	}
+
+	keys = set.PublicKeys()
+
+	s.mu.Lock()
+	s.keys = keys
+	s.fetchedAt = time.Now()
+	s.failedAt = time.Time{}
+	s.fetchErr = nil
+	s.mu.Unlock()
+
+	return keys, nil
+}
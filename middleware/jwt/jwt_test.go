@@ -1,7 +1,12 @@
 package jwt_test
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	stdhttptest "net/http/httptest"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -63,3 +68,174 @@ func TestJWT(t *testing.T) {
 	e.GET("/protected").WithHeader("Authorization", headerValue).Expect().
 		Status(iris.StatusUnauthorized).Body().IsEqual("jwt: token expired")
 }
+
+func TestKeySet(t *testing.T) {
+	keys := jwt.NewKeySet(jwt.GenerateKey(jwt.EdDSA), 100*time.Millisecond)
+	if err := keys.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+
+	app := iris.New()
+	signer := jwt.NewSignerWithKeys(keys, time.Minute)
+	app.Get("/", func(ctx iris.Context) {
+		token, err := signer.Sign(fooClaims{Foo: "bar"})
+		if err != nil {
+			ctx.StopWithError(iris.StatusInternalServerError, err)
+			return
+		}
+		ctx.Write(token)
+	})
+	app.Get(jwt.JWKSPath, keys.JWKSHandler)
+
+	verifier := jwt.NewVerifierWithKeys(keys)
+	app.Get("/protected", verifier.Verify(func() interface{} { return new(fooClaims) }), func(ctx iris.Context) {
+		ctx.WriteString(jwt.Get(ctx).(*fooClaims).Foo)
+	})
+
+	e := httptest.New(t, app)
+
+	oldToken := e.GET("/").Expect().Status(iris.StatusOK).Body().Raw()
+	oldKid := keys.Current().ID
+	if err := keys.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	newToken := e.GET("/").Expect().Status(iris.StatusOK).Body().Raw()
+
+	// Both the current and the retired key are published and verify their tokens.
+	e.GET(jwt.JWKSPath).Expect().Status(iris.StatusOK).JSON().Path("$.keys[*].kid").Array().
+		ContainsOnly(oldKid, keys.Current().ID)
+	e.GET("/protected").WithQuery("token", oldToken).Expect().Status(iris.StatusOK).Body().IsEqual("bar")
+	e.GET("/protected").WithQuery("token", newToken).Expect().Status(iris.StatusOK).Body().IsEqual("bar")
+
+	// After the overlap window the retired key is removed.
+	time.Sleep(150 * time.Millisecond)
+	e.GET(jwt.JWKSPath).Expect().Status(iris.StatusOK).JSON().Path("$.keys[*].kid").Array().
+		ContainsOnly(keys.Current().ID)
+	e.GET("/protected").WithQuery("token", oldToken).Expect().Status(iris.StatusUnauthorized)
+	e.GET("/protected").WithQuery("token", newToken).Expect().Status(iris.StatusOK).Body().IsEqual("bar")
+}
+
+func TestRemoteKeySet(t *testing.T) {
+	keys := jwt.NewKeySet(jwt.GenerateKey(jwt.ES256), time.Minute)
+	if err := keys.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+
+	var fetches int32
+	issuer := stdhttptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		set, err := keys.JWKS()
+		if err != nil {
+			t.Error(err)
+		}
+		json.NewEncoder(w).Encode(set)
+	}))
+	defer issuer.Close()
+
+	remote := jwt.NewRemoteKeySet(issuer.URL + jwt.JWKSPath)
+	remote.MinRefreshInterval = 0
+	verifier := jwt.NewVerifierWithKeys(remote)
+
+	verify := func(expectedFetches int32) {
+		t.Helper()
+
+		token, err := keys.Sign(fooClaims{Foo: "bar"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = verifier.VerifyToken(token); err != nil {
+			t.Fatal(err)
+		}
+
+		if got := atomic.LoadInt32(&fetches); got != expectedFetches {
+			t.Fatalf("expected %d fetches of the key set but got %d", expectedFetches, got)
+		}
+	}
+
+	verify(1)
+	verify(1) // cached.
+
+	// Refresh on unknown kid.
+	if err := keys.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	verify(2)
+
+	// A key which is not part of the issuer's key set.
+	other := jwt.NewKeySet(jwt.GenerateKey(jwt.ES256), 0)
+	if err := other.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+	token, err := other.Sign(fooClaims{Foo: "bar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = verifier.VerifyToken(token); !errors.Is(err, jwt.ErrUnknownKid) {
+		t.Fatalf("expected error: %v but got: %v", jwt.ErrUnknownKid, err)
+	}
+}
+
+func TestRemoteKeySetFetchFailure(t *testing.T) {
+	keys := jwt.NewKeySet(jwt.GenerateKey(jwt.ES256), time.Minute)
+	if err := keys.Rotate(); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		fetches int32
+		failing int32
+	)
+	issuer := stdhttptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&fetches, 1)
+		if atomic.LoadInt32(&failing) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		set, err := keys.JWKS()
+		if err != nil {
+			t.Error(err)
+		}
+		json.NewEncoder(w).Encode(set)
+	}))
+	defer issuer.Close()
+
+	token, err := keys.Sign(fooClaims{Foo: "bar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verify := func(remote *jwt.RemoteKeySet, expectedFetches int32, expectErr bool) {
+		t.Helper()
+
+		_, err := jwt.NewVerifierWithKeys(remote).VerifyToken(token)
+		if expectErr != (err != nil) {
+			t.Fatalf("expected error: %v but got: %v", expectErr, err)
+		}
+
+		if got := atomic.LoadInt32(&fetches); got != expectedFetches {
+			t.Fatalf("expected %d fetches of the key set but got %d", expectedFetches, got)
+		}
+	}
+
+	remote := jwt.NewRemoteKeySet(issuer.URL + jwt.JWKSPath)
+	remote.CacheDuration = 0 // fetch on every verification.
+	verify(remote, 1, false)
+
+	atomic.StoreInt32(&failing, 1)
+	verify(remote, 2, false) // the fetch failed, the cached keys are used.
+	verify(remote, 2, false) // back off, the cached keys are used.
+
+	// Without cached keys.
+	remote = jwt.NewRemoteKeySet(issuer.URL + jwt.JWKSPath)
+	verify(remote, 3, true)
+	verify(remote, 3, true) // back off, the last error is returned.
+
+	if err = remote.Refresh(); err == nil { // forced.
+		t.Fatalf("expected a fetch error")
+	}
+	if got := atomic.LoadInt32(&fetches); got != 4 {
+		t.Fatalf("expected 4 fetches of the key set but got %d", got)
+	}
+}
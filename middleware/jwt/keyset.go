@@ -0,0 +1,307 @@
+package jwt
+
+import (
+	stdContext "context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+
+	"github.com/google/uuid"
+	"github.com/kataras/jwt"
+)
+
+// JWKSPath is the well-known path which the
+// JSON Web Key Set of an issuer is served from.
+const JWKSPath = "/.well-known/jwks.json"
+
+// KeyResolver resolves the algorithm and the public key
+// which a token should be verified with, based on its header's "kid" field.
+// It is completed by the KeySet, RemoteKeySet and the Keys types
+// and it is used by the Verifier's Keys field.
+type KeyResolver interface {
+	ValidateHeader(alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc, error)
+}
+
+var (
+	_ KeyResolver = (*KeySet)(nil)
+	_ KeyResolver = (*RemoteKeySet)(nil)
+	_ KeyResolver = (Keys)(nil)
+)
+
+// KeyGenerator generates a new key, its ID is filled by the KeySet.
+// See the GenerateKey package-level function.
+type KeyGenerator func() (*Key, error)
+
+// GenerateKey returns a KeyGenerator which generates
+// key pairs for the given asymmetric signature algorithm
+// (RS256, RS384, RS512, PS256, PS384, PS512, ES256, ES384, ES512 and EdDSA).
+func GenerateKey(alg Alg) KeyGenerator {
+	return func() (*Key, error) {
+		var (
+			publicKey  PublicKey
+			privateKey PrivateKey
+		)
+
+		switch alg {
+		case RS256, RS384, RS512, PS256, PS384, PS512:
+			k, err := rsa.GenerateKey(rand.Reader, 2048)
+			if err != nil {
+				return nil, err
+			}
+			publicKey, privateKey = &k.PublicKey, k
+		case ES256, ES384, ES512:
+			curve := elliptic.P256()
+			if alg == ES384 {
+				curve = elliptic.P384()
+			} else if alg == ES512 {
+				curve = elliptic.P521()
+			}
+
+			k, err := ecdsa.GenerateKey(curve, rand.Reader)
+			if err != nil {
+				return nil, err
+			}
+			publicKey, privateKey = &k.PublicKey, k
+		case EdDSA:
+			public, private, err := ed25519.GenerateKey(rand.Reader)
+			if err != nil {
+				return nil, err
+			}
+			publicKey, privateKey = public, private
+		default:
+			return nil, fmt.Errorf("jwt: generate key: unsupported algorithm: %s", alg.Name())
+		}
+
+		return &Key{Alg: alg, Public: publicKey, Private: privateKey}, nil
+	}
+}
+
+type keySetEntry struct {
+	key       *Key
+	retiredAt time.Time // zero for the current signing key.
+}
+
+// KeySet holds a set of keys identified by their "kid".
+// The last added key is the current one, which signs new tokens,
+// the previous keys are retired and they can only verify tokens
+// for an overlap window after their retirement, so keys
+// can be rotated without invalidating all the live tokens.
+//
+// It is safe for concurrent use.
+// Use it through the Signer's and Verifier's Keys field,
+// see NewSignerWithKeys and NewVerifierWithKeys,
+// and serve its public keys through the JWKSHandler method.
+//
+// Usage:
+//
+//	keys := jwt.NewKeySet(jwt.GenerateKey(jwt.EdDSA), 30*time.Minute)
+//	if err := keys.Rotate(); err != nil { ... }
+//	go keys.RotateEvery(ctx, 24*time.Hour)
+//
+//	signer := jwt.NewSignerWithKeys(keys, 15*time.Minute)
+//	verifier := jwt.NewVerifierWithKeys(keys)
+//	app.Get(jwt.JWKSPath, keys.JWKSHandler)
+type KeySet struct {
+	// Generate is used by the Rotate method to generate a new key.
+	Generate KeyGenerator
+	// Overlap is the duration that a retired key is still valid to verify tokens.
+	// It should be equal or greater than the max age of the signed tokens.
+	Overlap time.Duration
+	// OnRotate, if not nil, is called after each rotation of the RotateEvery method.
+	// The "kid" is the new current key id or empty on failure.
+	OnRotate func(kid string, err error)
+
+	mu      sync.RWMutex
+	entries []*keySetEntry // the last one is the current key.
+}
+
+// NewKeySet returns a new empty KeySet which generates keys
+// through the "generate" function and keeps retired keys for the "overlap" duration.
+// Call its Rotate method to generate its first key or add an existing one through its Add method.
+func NewKeySet(generate KeyGenerator, overlap time.Duration) *KeySet {
+	return &KeySet{
+		Generate: generate,
+		Overlap:  overlap,
+	}
+}
+
+// Add adds one or more keys to the set, the last one becomes the current signing key.
+// A key without an ID is given a random one.
+func (s *KeySet) Add(keys ...*Key) *KeySet {
+	s.mu.Lock()
+	now := time.Now()
+	for _, key := range keys {
+		if key.ID == "" {
+			key.ID = uuid.NewString()
+		}
+
+		if n := len(s.entries); n > 0 {
+			s.entries[n-1].retiredAt = now
+		}
+
+		s.entries = append(s.entries, &keySetEntry{key: key})
+	}
+	s.pruneLocked(now)
+	s.mu.Unlock()
+
+	return s
+}
+
+// Rotate generates a new key through the Generate field and sets it as the current signing key.
+// The previous signing key is retired and it is removed after the Overlap duration.
+func (s *KeySet) Rotate() error {
+	if s.Generate == nil {
+		return fmt.Errorf("jwt: rotate: missing key generator")
+	}
+
+	key, err := s.Generate()
+	if err != nil {
+		return fmt.Errorf("jwt: rotate: %w", err)
+	}
+
+	s.Add(key)
+	return nil
+}
+
+// RotateEvery rotates the keys every "interval" until the "ctx" is done.
+// It blocks, so it should be called in its own goroutine.
+// Set the OnRotate field to get notified about the rotations and their errors.
+func (s *KeySet) RotateEvery(ctx stdContext.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			err := s.Rotate()
+			if s.OnRotate != nil {
+				kid := ""
+				if err == nil {
+					kid = s.Current().ID
+				}
+
+				s.OnRotate(kid, err)
+			}
+		}
+	}
+}
+
+// pruneLocked removes the retired keys which their overlap window is over.
+func (s *KeySet) pruneLocked(now time.Time) {
+	entries := s.entries[:0]
+	for _, entry := range s.entries {
+		if !entry.retiredAt.IsZero() && now.Sub(entry.retiredAt) > s.Overlap {
+			continue
+		}
+
+		entries = append(entries, entry)
+	}
+
+	for i := len(entries); i < len(s.entries); i++ {
+		s.entries[i] = nil
+	}
+	s.entries = entries
+}
+
+// Current returns the current signing key or nil if the set is empty.
+func (s *KeySet) Current() *Key {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if n := len(s.entries); n > 0 {
+		return s.entries[n-1].key
+	}
+
+	return nil
+}
+
+// Keys returns the valid keys of the set: the current one
+// and the retired ones which are still inside their overlap window.
+func (s *KeySet) Keys() Keys {
+	now := time.Now()
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make(Keys, len(s.entries))
+	for _, entry := range s.entries {
+		if !entry.retiredAt.IsZero() && now.Sub(entry.retiredAt) > s.Overlap {
+			continue
+		}
+
+		keys[entry.key.ID] = entry.key
+	}
+
+	return keys
+}
+
+// Sign signs the "claims" with the current key and
+// sets the "kid" field of the token's header to its ID.
+func (s *KeySet) Sign(claims interface{}, opts ...SignOption) ([]byte, error) {
+	key := s.Current()
+	if key == nil {
+		return nil, ErrMissingKey
+	}
+
+	return jwt.SignEncryptedWithHeader(key.Alg, key.Private, key.Encrypt, claims, HeaderWithKid{
+		Kid: key.ID,
+		Alg: key.Alg.Name(),
+	}, opts...)
+}
+
+// ValidateHeader completes the KeyResolver interface.
+// It resolves the key of the token's "kid" between the valid keys of the set.
+func (s *KeySet) ValidateHeader(alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc, error) {
+	return s.Keys().ValidateHeader(alg, headerDecoded)
+}
+
+// JWKS returns the JSON Web Key Set of the valid public keys of the set.
+// Keys of symmetric algorithms (e.g. HMAC) are never published.
+func (s *KeySet) JWKS() (*JWKS, error) {
+	keys := s.Keys()
+
+	set := &JWKS{Keys: make([]*JWK, 0, len(keys))}
+	for _, key := range keys {
+		publicKey := key.Public
+		switch k := publicKey.(type) {
+		case []byte: // HMAC.
+			continue
+		case *ecdsa.PublicKey:
+			publicKey = *k
+		}
+
+		jwk, err := jwt.GenerateJWK(key.ID, key.Alg.Name(), publicKey)
+		if err != nil {
+			return nil, fmt.Errorf("jwt: jwks: %s: %w", key.ID, err)
+		}
+
+		set.Keys = append(set.Keys, jwk)
+	}
+
+	return set, nil
+}
+
+// JWKSHandler is an Iris Handler which sends the JSON Web Key Set of the set.
+// Register it on the JWKSPath.
+//
+// Usage:
+//
+//	app.Get(jwt.JWKSPath, keys.JWKSHandler)
+func (s *KeySet) JWKSHandler(ctx *context.Context) {
+	set, err := s.JWKS()
+	if err != nil {
+		ctx.StopWithError(500, err)
+		return
+	}
+
+	ctx.JSON(set)
+}
@@ -11,10 +11,13 @@ import (
 // Its Sign method can be used to generate a token which can be sent to the client.
 // Its NewTokenPair can be used to construct a token pair (access_token, refresh_token).
 //
-// It does not support JWE, JWK.
+// It does not support JWE.
 type Signer struct {
 	Alg Alg
 	Key interface{}
+	// Keys, if not nil, signs the tokens with its current key
+	// instead of the Alg and Key fields, see NewSignerWithKeys.
+	Keys *KeySet
 
 	// MaxAge to set "exp" and "iat".
 	// Recommended value for access tokens: 15 minutes.
@@ -54,6 +57,29 @@ func NewSigner(signatureAlg Alg, signatureKey interface{}, maxAge time.Duration)
 	return s
 }
 
+// NewSignerWithKeys accepts a key set and the max life time duration of generated tokens
+// and returns a JWT signer which signs the tokens with the current key of the set.
+// The "kid" header field of the tokens holds the key's ID, so the key set can be rotated
+// without invalidating the tokens signed by its previous keys.
+//
+// Usage:
+//
+//	keys := NewKeySet(GenerateKey(EdDSA), 15*time.Minute)
+//	keys.Rotate()
+//	signer := NewSignerWithKeys(keys, 15*time.Minute)
+func NewSignerWithKeys(keys *KeySet, maxAge time.Duration) *Signer {
+	s := &Signer{
+		Keys:   keys,
+		MaxAge: maxAge,
+	}
+
+	if maxAge > 0 {
+		s.Options = []SignOption{MaxAge(maxAge)}
+	}
+
+	return s
+}
+
 // WithEncryption enables AES-GCM payload-only decryption.
 func (s *Signer) WithEncryption(key, additionalData []byte) *Signer {
 	encrypt, _, err := jwt.GCM(key, additionalData)
@@ -73,6 +99,14 @@ func (s *Signer) Sign(claims interface{}, opts ...SignOption) ([]byte, error) {
 		opts = s.Options
 	}
 
+	return s.sign(claims, opts...)
+}
+
+func (s *Signer) sign(claims interface{}, opts ...SignOption) ([]byte, error) {
+	if s.Keys != nil {
+		return s.Keys.Sign(claims, opts...)
+	}
+
 	return SignEncrypted(s.Alg, s.Key, s.Encrypt, claims, opts...)
 }
 
@@ -89,7 +123,12 @@ func (s *Signer) NewTokenPair(accessClaims interface{}, refreshClaims interface{
 		return TokenPair{}, err
 	}
 
-	refreshToken, err := Sign(s.Alg, s.Key, refreshClaims, MaxAge(refreshMaxAge))
+	var refreshToken []byte
+	if s.Keys != nil {
+		refreshToken, err = s.Keys.Sign(refreshClaims, MaxAge(refreshMaxAge))
+	} else {
+		refreshToken, err = Sign(s.Alg, s.Key, refreshClaims, MaxAge(refreshMaxAge))
+	}
 	if err != nil {
 		return TokenPair{}, err
 	}
@@ -39,10 +39,13 @@ func GetVerifiedToken(ctx *context.Context) *VerifiedToken {
 // Verifier holds common options to verify an incoming token.
 // Its Verify method can be used as a middleware to allow authorized clients to access an API.
 //
-// It does not support JWE, JWK.
+// It does not support JWE.
 type Verifier struct {
 	Alg Alg
 	Key interface{}
+	// Keys, if not nil, resolves the key of each token based on its "kid" header field
+	// instead of the Alg and Key fields, see NewVerifierWithKeys.
+	Keys KeyResolver
 
 	Decrypt func([]byte) ([]byte, error)
 
@@ -105,6 +108,19 @@ func NewVerifier(signatureAlg Alg, signatureKey interface{}, validators ...Token
 	}
 }
 
+// NewVerifierWithKeys accepts a key resolver, e.g. a KeySet, a RemoteKeySet or Keys,
+// and optionally some token validators and returns a JWT verifier
+// which verifies each token with the key of its "kid" header field.
+//
+// Usage:
+//
+//	verifier := NewVerifierWithKeys(NewRemoteKeySet("https://issuer.example.com/.well-known/jwks.json"))
+func NewVerifierWithKeys(keys KeyResolver, validators ...TokenValidator) *Verifier {
+	v := NewVerifier(nil, nil, validators...)
+	v.Keys = keys
+	return v
+}
+
 // WithDecryption enables AES-GCM payload-only encryption.
 func (v *Verifier) WithDecryption(key, additionalData []byte) *Verifier {
 	_, decrypt, err := jwt.GCM(key, additionalData)
@@ -163,6 +179,10 @@ type (
 // VerifyToken simply verifies the given "token" and validates its standard claims (such as expiration).
 // Returns a structure which holds the token's information. See the Verify method instead.
 func (v *Verifier) VerifyToken(token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	if v.Keys != nil {
+		return jwt.VerifyEncryptedWithHeaderValidator(nil, nil, v.Decrypt, token, v.Keys.ValidateHeader, validators...)
+	}
+
 	return jwt.VerifyEncrypted(v.Alg, v.Key, v.Decrypt, token, validators...)
 }
 
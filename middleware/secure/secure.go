@@ -0,0 +1,377 @@
+// Package secure provides a middleware which sets the security related response headers,
+// e.g. Strict-Transport-Security, X-Frame-Options, Referrer-Policy and Content-Security-Policy
+// with per-request nonces, and a handler which collects the Content Security Policy violation reports.
+package secure
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/middleware/cors"
+)
+
+func init() {
+	context.SetHandlerName("iris/middleware/secure.*", "iris.secure")
+}
+
+const (
+	// NoncePlaceholder is replaced by the request's nonce in the Options.ContentSecurityPolicy,
+	// e.g. "script-src 'self' 'nonce-{nonce}'".
+	NoncePlaceholder = "{nonce}"
+	// DefaultViewDataKey is the default view data key of the request's nonce,
+	// e.g. <script nonce="{{ .csp_nonce }}">.
+	DefaultViewDataKey = "csp_nonce"
+	// ReportEndpointName is the name of the Reporting-Endpoints header's endpoint
+	// which is referenced by the "report-to" directive of the Content Security Policy.
+	ReportEndpointName = "csp-endpoint"
+
+	nonceContextKey   = "iris.secure.nonce"
+	maxReportBodySize = 64 << 10
+)
+
+// Options holds the security headers to send.
+// Empty or zero fields are not sent, see the DefaultOptions function.
+type Options struct {
+	// STSMaxAge is the max-age of the Strict-Transport-Security header.
+	// The header is sent on secure requests only (see Context.IsSSL), unless ForceSTS is true.
+	STSMaxAge time.Duration
+	// STSIncludeSubdomains adds the "includeSubDomains" directive to the Strict-Transport-Security header.
+	STSIncludeSubdomains bool
+	// STSPreload adds the "preload" directive to the Strict-Transport-Security header.
+	STSPreload bool
+	// ForceSTS sends the Strict-Transport-Security header on plain HTTP requests too,
+	// e.g. when the TLS is terminated by a proxy which does not set the SSL proxy headers.
+	ForceSTS bool
+	// FrameOptions is the X-Frame-Options header, e.g. "DENY" or "SAMEORIGIN".
+	FrameOptions string
+	// ContentTypeNosniff sends the "X-Content-Type-Options: nosniff" header.
+	ContentTypeNosniff bool
+	// ReferrerPolicy is the Referrer-Policy header.
+	ReferrerPolicy cors.ReferrerPolicy
+	// CrossOriginOpenerPolicy is the Cross-Origin-Opener-Policy header, e.g. "same-origin".
+	CrossOriginOpenerPolicy string
+	// CrossOriginResourcePolicy is the Cross-Origin-Resource-Policy header, e.g. "same-origin".
+	CrossOriginResourcePolicy string
+	// PermissionsPolicy is the Permissions-Policy header, e.g. "camera=(), microphone=()".
+	PermissionsPolicy string
+	// ContentSecurityPolicy is the Content-Security-Policy header.
+	// Each NoncePlaceholder is replaced by a new random nonce on each request,
+	// which is available through the Nonce package-level function and the view data.
+	ContentSecurityPolicy string
+	// ContentSecurityPolicyReportOnly sends the Content-Security-Policy-Report-Only header instead,
+	// the browsers report the violations without blocking the resources.
+	ContentSecurityPolicyReportOnly bool
+	// ReportURI is the path or URL of the endpoint which receives the violation reports,
+	// see the Secure.ReportHandler method. When set, the "report-uri" and "report-to" directives
+	// are added to the Content Security Policy and the Reporting-Endpoints header is sent.
+	ReportURI string
+	// ViewDataKey is the view data key of the nonce (see Context.ViewData).
+	// Defaults to "csp_nonce".
+	ViewDataKey string
+	// OnReport is fired by the ReportHandler on each violation report.
+	// Defaults to a function which logs the report through the application's logger at the debug level.
+	OnReport func(ctx *context.Context, report Report)
+}
+
+// DefaultOptions returns a strict set of security headers:
+//   - Strict-Transport-Security: max-age=31536000; includeSubDomains
+//   - X-Frame-Options: DENY
+//   - X-Content-Type-Options: nosniff
+//   - Referrer-Policy: strict-origin-when-cross-origin
+//   - Cross-Origin-Opener-Policy: same-origin
+//   - Content-Security-Policy: default-src 'self'; script-src 'self' 'nonce-{nonce}';
+//     style-src 'self' 'nonce-{nonce}'; object-src 'none'; base-uri 'self'; frame-ancestors 'none'
+func DefaultOptions() Options {
+	return Options{
+		STSMaxAge:               365 * 24 * time.Hour,
+		STSIncludeSubdomains:    true,
+		FrameOptions:            "DENY",
+		ContentTypeNosniff:      true,
+		ReferrerPolicy:          cors.StrictOriginWhenCrossOrigin,
+		CrossOriginOpenerPolicy: "same-origin",
+		ContentSecurityPolicy: "default-src 'self'; script-src 'self' 'nonce-" + NoncePlaceholder + "'; " +
+			"style-src 'self' 'nonce-" + NoncePlaceholder + "'; object-src 'none'; base-uri 'self'; frame-ancestors 'none'",
+	}
+}
+
+// Secure is the security headers middleware.
+// Create a new one through the New package-level function.
+type Secure struct {
+	opts Options
+
+	headers   [][2]string // the static headers.
+	stsHeader string
+	cspHeader string
+	cspParts  []string // the Content Security Policy split by the NoncePlaceholder.
+}
+
+// New returns a new security headers middleware.
+//
+// Usage:
+//
+//	opts := secure.DefaultOptions()
+//	opts.ReportURI = "/csp-report"
+//	s := secure.New(opts)
+//	app.UseRouter(s.Handler)
+//	app.Post("/csp-report", s.ReportHandler)
+//
+// And in the templates: <script nonce="{{ .csp_nonce }}">...</script>
+func New(opts Options) *Secure {
+	if opts.ViewDataKey == "" {
+		opts.ViewDataKey = DefaultViewDataKey
+	}
+
+	if opts.OnReport == nil {
+		opts.OnReport = logReport
+	}
+
+	s := &Secure{opts: opts}
+
+	if opts.STSMaxAge > 0 {
+		s.stsHeader = "max-age=" + strconv.FormatInt(int64(opts.STSMaxAge/time.Second), 10)
+		if opts.STSIncludeSubdomains {
+			s.stsHeader += "; includeSubDomains"
+		}
+		if opts.STSPreload {
+			s.stsHeader += "; preload"
+		}
+	}
+
+	s.addHeader("X-Frame-Options", opts.FrameOptions)
+	if opts.ContentTypeNosniff {
+		s.addHeader("X-Content-Type-Options", "nosniff")
+	}
+	s.addHeader("Referrer-Policy", opts.ReferrerPolicy.String())
+	s.addHeader("Cross-Origin-Opener-Policy", opts.CrossOriginOpenerPolicy)
+	s.addHeader("Cross-Origin-Resource-Policy", opts.CrossOriginResourcePolicy)
+	s.addHeader("Permissions-Policy", opts.PermissionsPolicy)
+
+	if csp := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(opts.ContentSecurityPolicy), ";")); csp != "" {
+		if opts.ReportURI != "" {
+			csp += "; report-uri " + opts.ReportURI + "; report-to " + ReportEndpointName
+			s.addHeader("Reporting-Endpoints", ReportEndpointName+`="`+opts.ReportURI+`"`)
+		}
+
+		s.cspHeader = "Content-Security-Policy"
+		if opts.ContentSecurityPolicyReportOnly {
+			s.cspHeader = "Content-Security-Policy-Report-Only"
+		}
+
+		s.cspParts = strings.Split(csp, NoncePlaceholder)
+	}
+
+	return s
+}
+
+func (s *Secure) addHeader(key, value string) {
+	if value != "" {
+		s.headers = append(s.headers, [2]string{key, value})
+	}
+}
+
+// Handler is the security headers middleware.
+func (s *Secure) Handler(ctx *context.Context) {
+	header := ctx.ResponseWriter().Header()
+
+	for _, kv := range s.headers {
+		header.Set(kv[0], kv[1])
+	}
+
+	if s.stsHeader != "" && (s.opts.ForceSTS || ctx.IsSSL()) {
+		header.Set("Strict-Transport-Security", s.stsHeader)
+	}
+
+	if len(s.cspParts) > 0 {
+		csp := s.cspParts[0]
+		if len(s.cspParts) > 1 {
+			nonce, err := newNonce()
+			if err != nil {
+				ctx.StopWithError(http.StatusInternalServerError, err)
+				return
+			}
+
+			ctx.Values().Set(nonceContextKey, nonce)
+			ctx.ViewData(s.opts.ViewDataKey, nonce)
+			csp = strings.Join(s.cspParts, nonce)
+		}
+
+		header.Set(s.cspHeader, csp)
+	}
+
+	ctx.Next()
+}
+
+// Nonce returns the Content Security Policy nonce of the current request.
+// Returns an empty string if the policy has no NoncePlaceholder or the middleware did not run.
+func Nonce(ctx *context.Context) string {
+	return ctx.Values().GetString(nonceContextKey)
+}
+
+func newNonce() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	// URL-safe, so the templates render it unescaped.
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Report is a Content Security Policy violation report.
+type Report struct {
+	DocumentURI        string `json:"document_uri"`
+	Referrer           string `json:"referrer,omitempty"`
+	BlockedURI         string `json:"blocked_uri,omitempty"`
+	ViolatedDirective  string `json:"violated_directive,omitempty"`
+	EffectiveDirective string `json:"effective_directive,omitempty"`
+	OriginalPolicy     string `json:"original_policy,omitempty"`
+	Disposition        string `json:"disposition,omitempty"` // "enforce" or "report".
+	SourceFile         string `json:"source_file,omitempty"`
+	LineNumber         int    `json:"line_number,omitempty"`
+	ColumnNumber       int    `json:"column_number,omitempty"`
+	StatusCode         int    `json:"status_code,omitempty"`
+	Sample             string `json:"sample,omitempty"`
+	UserAgent          string `json:"user_agent,omitempty"`
+}
+
+type (
+	// legacyReport is the body of the "application/csp-report" requests (report-uri).
+	legacyReport struct {
+		Report struct {
+			DocumentURI        string `json:"document-uri"`
+			Referrer           string `json:"referrer"`
+			BlockedURI         string `json:"blocked-uri"`
+			ViolatedDirective  string `json:"violated-directive"`
+			EffectiveDirective string `json:"effective-directive"`
+			OriginalPolicy     string `json:"original-policy"`
+			Disposition        string `json:"disposition"`
+			SourceFile         string `json:"source-file"`
+			LineNumber         int    `json:"line-number"`
+			ColumnNumber       int    `json:"column-number"`
+			StatusCode         int    `json:"status-code"`
+			ScriptSample       string `json:"script-sample"`
+		} `json:"csp-report"`
+	}
+
+	// reportingAPIReport is an item of the "application/reports+json" requests (report-to).
+	reportingAPIReport struct {
+		Type      string `json:"type"`
+		UserAgent string `json:"user_agent"`
+		Body      struct {
+			DocumentURL        string `json:"documentURL"`
+			Referrer           string `json:"referrer"`
+			BlockedURL         string `json:"blockedURL"`
+			EffectiveDirective string `json:"effectiveDirective"`
+			OriginalPolicy     string `json:"originalPolicy"`
+			Disposition        string `json:"disposition"`
+			SourceFile         string `json:"sourceFile"`
+			LineNumber         int    `json:"lineNumber"`
+			ColumnNumber       int    `json:"columnNumber"`
+			StatusCode         int    `json:"statusCode"`
+			Sample             string `json:"sample"`
+		} `json:"body"`
+	}
+)
+
+// ReportHandler collects the Content Security Policy violation reports
+// of both the "report-uri" (application/csp-report) and
+// the "report-to" (application/reports+json) directives
+// and fires the Options.OnReport for each one of them.
+// Register it to the Options.ReportURI path, e.g. app.Post("/csp-report", s.ReportHandler).
+func (s *Secure) ReportHandler(ctx *context.Context) {
+	ctx.SetMaxRequestBodySize(maxReportBodySize)
+	body, err := io.ReadAll(ctx.Request().Body)
+	if err != nil {
+		ctx.StopWithStatus(http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	reports, err := parseReports(body)
+	if err != nil {
+		ctx.StopWithError(http.StatusBadRequest, err)
+		return
+	}
+
+	userAgent := ctx.GetHeader("User-Agent")
+	for _, report := range reports {
+		if report.UserAgent == "" {
+			report.UserAgent = userAgent
+		}
+
+		s.opts.OnReport(ctx, report)
+	}
+
+	ctx.StatusCode(http.StatusNoContent)
+}
+
+func parseReports(body []byte) ([]Report, error) {
+	body = bytes.TrimSpace(body)
+
+	if len(body) > 0 && body[0] == '[' {
+		var items []reportingAPIReport
+		if err := json.Unmarshal(body, &items); err != nil {
+			return nil, err
+		}
+
+		reports := make([]Report, 0, len(items))
+		for _, item := range items {
+			if item.Type != "csp-violation" {
+				continue
+			}
+
+			reports = append(reports, Report{
+				DocumentURI:        item.Body.DocumentURL,
+				Referrer:           item.Body.Referrer,
+				BlockedURI:         item.Body.BlockedURL,
+				ViolatedDirective:  item.Body.EffectiveDirective,
+				EffectiveDirective: item.Body.EffectiveDirective,
+				OriginalPolicy:     item.Body.OriginalPolicy,
+				Disposition:        item.Body.Disposition,
+				SourceFile:         item.Body.SourceFile,
+				LineNumber:         item.Body.LineNumber,
+				ColumnNumber:       item.Body.ColumnNumber,
+				StatusCode:         item.Body.StatusCode,
+				Sample:             item.Body.Sample,
+				UserAgent:          item.UserAgent,
+			})
+		}
+
+		return reports, nil
+	}
+
+	var legacy legacyReport
+	if err := json.Unmarshal(body, &legacy); err != nil {
+		return nil, err
+	}
+
+	r := legacy.Report
+	return []Report{{
+		DocumentURI:        r.DocumentURI,
+		Referrer:           r.Referrer,
+		BlockedURI:         r.BlockedURI,
+		ViolatedDirective:  r.ViolatedDirective,
+		EffectiveDirective: r.EffectiveDirective,
+		OriginalPolicy:     r.OriginalPolicy,
+		Disposition:        r.Disposition,
+		SourceFile:         r.SourceFile,
+		LineNumber:         r.LineNumber,
+		ColumnNumber:       r.ColumnNumber,
+		StatusCode:         r.StatusCode,
+		Sample:             r.ScriptSample,
+	}}, nil
+}
+
+func logReport(ctx *context.Context, report Report) {
+	// The report is sent by the client, quote its fields
+	// and keep it out of the default log level.
+	ctx.Application().Logger().Debugf("secure: content security policy violation (%q): %q blocked %q on %q",
+		report.Disposition, report.EffectiveDirective, report.BlockedURI, report.DocumentURI)
+}
@@ -0,0 +1,113 @@
+package secure_test
+
+import (
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/httptest"
+	"github.com/kataras/iris/v12/middleware/cors"
+	"github.com/kataras/iris/v12/middleware/secure"
+)
+
+func TestSecure(t *testing.T) {
+	var (
+		mu      sync.Mutex
+		reports []secure.Report
+	)
+
+	opts := secure.DefaultOptions()
+	opts.ReferrerPolicy = cors.NoReferrer
+	opts.ContentSecurityPolicyReportOnly = true
+	opts.ReportURI = "/csp-report"
+	opts.OnReport = func(ctx iris.Context, report secure.Report) {
+		mu.Lock()
+		reports = append(reports, report)
+		mu.Unlock()
+	}
+	s := secure.New(opts)
+
+	app := iris.New()
+	app.RegisterView(iris.HTML(fstest.MapFS{
+		"index.html": {Data: []byte(`<script nonce="{{ .csp_nonce }}"></script>`)},
+	}, ".html"))
+	app.UseRouter(s.Handler)
+	app.Get("/", func(ctx iris.Context) {
+		ctx.View("index.html")
+	})
+	app.Get("/nonce", func(ctx iris.Context) {
+		ctx.WriteString(secure.Nonce(ctx))
+	})
+	app.Post("/csp-report", s.ReportHandler)
+
+	e := httptest.New(t, app)
+
+	resp := e.GET("/").Expect().Status(httptest.StatusOK)
+	resp.Header("X-Frame-Options").IsEqual("DENY")
+	resp.Header("X-Content-Type-Options").IsEqual("nosniff")
+	resp.Header("Referrer-Policy").IsEqual("no-referrer")
+	resp.Header("Cross-Origin-Opener-Policy").IsEqual("same-origin")
+	resp.Header("Reporting-Endpoints").IsEqual(`csp-endpoint="/csp-report"`)
+	resp.Header("Content-Security-Policy").IsEmpty()
+	// Plain HTTP.
+	resp.Header("Strict-Transport-Security").IsEmpty()
+
+	csp := resp.Header("Content-Security-Policy-Report-Only").Raw()
+	body := resp.Body().Raw()
+	nonce := strings.TrimSuffix(strings.TrimPrefix(body, `<script nonce="`), `"></script>`)
+	if nonce == "" || nonce == body {
+		t.Fatalf("expected a nonce in the template but got: %q", body)
+	}
+
+	expectedCSP := "default-src 'self'; script-src 'self' 'nonce-" + nonce + "'; style-src 'self' 'nonce-" + nonce +
+		"'; object-src 'none'; base-uri 'self'; frame-ancestors 'none'; report-uri /csp-report; report-to csp-endpoint"
+	if csp != expectedCSP {
+		t.Fatalf("expected policy:\n%s\nbut got:\n%s", expectedCSP, csp)
+	}
+
+	if other := e.GET("/nonce").Expect().Status(httptest.StatusOK).Body().NotEmpty().Raw(); other == nonce {
+		t.Fatal("expected a new nonce on each request")
+	}
+
+	e.POST("/csp-report").WithHeader("Content-Type", "application/csp-report").WithHeader("User-Agent", "Browser").
+		WithBytes([]byte(`{"csp-report":{"document-uri":"http://localhost/","blocked-uri":"inline","effective-directive":"script-src-elem","disposition":"report","line-number":4}}`)).
+		Expect().Status(httptest.StatusNoContent)
+	e.POST("/csp-report").WithHeader("Content-Type", "application/reports+json").
+		WithBytes([]byte(`[{"type":"csp-violation","user_agent":"Test","body":{"documentURL":"http://localhost/","blockedURL":"https://evil.com/x.js","effectiveDirective":"script-src-elem","disposition":"report"}},{"type":"deprecation","body":{}}]`)).
+		Expect().Status(httptest.StatusNoContent)
+	e.POST("/csp-report").WithBytes([]byte(`{`)).Expect().Status(httptest.StatusBadRequest)
+
+	expected := []secure.Report{
+		{DocumentURI: "http://localhost/", BlockedURI: "inline", EffectiveDirective: "script-src-elem", Disposition: "report", LineNumber: 4, UserAgent: "Browser"},
+		{DocumentURI: "http://localhost/", BlockedURI: "https://evil.com/x.js", ViolatedDirective: "script-src-elem", EffectiveDirective: "script-src-elem", Disposition: "report", UserAgent: "Test"},
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(reports) != len(expected) {
+		t.Fatalf("expected %d reports but got %d: %#+v", len(expected), len(reports), reports)
+	}
+
+	for i := range expected {
+		if reports[i] != expected[i] {
+			t.Fatalf("[%d] expected report:\n%#+v\nbut got:\n%#+v", i, expected[i], reports[i])
+		}
+	}
+}
+
+func TestSecureSTS(t *testing.T) {
+	app := iris.New()
+	app.Use(secure.New(secure.Options{STSMaxAge: 2 * 365 * 24 * time.Hour, STSIncludeSubdomains: true, STSPreload: true, ForceSTS: true}).Handler)
+	app.Get("/", func(ctx iris.Context) {
+		ctx.WriteString(secure.Nonce(ctx))
+	})
+
+	resp := httptest.New(t, app).GET("/").Expect().Status(httptest.StatusOK)
+	resp.Header("Strict-Transport-Security").IsEqual("max-age=63072000; includeSubDomains; preload")
+	resp.Header("X-Frame-Options").IsEmpty()
+	resp.Header("Content-Security-Policy").IsEmpty()
+	resp.Body().IsEmpty()
+}
@@ -0,0 +1,147 @@
+package mvc
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Authorize is a zero-size type which declares the authorization requirements
+// of a controller and its methods through the struct tags of a blank field.
+// The "authorize" tag holds the requirements of all the controller's routes
+// and the "authorize.MethodName" tags the requirements of a specific method's routes,
+// both are comma separated and all of them should be satisfied.
+// The requirements are evaluated by the Party's Authorizer, see Party.Authorize.
+//
+// Usage:
+//
+//	type UsersController struct {
+//		_ mvc.Authorize `authorize:"user" authorize.DeleteBy:"admin" authorize.Post:"admin,editor"`
+//	}
+type Authorize struct{}
+
+const authorizeTagKey = "authorize"
+
+var authorizeTyp = reflect.TypeOf(Authorize{})
+
+// parseAuthorizeTags returns the requirements of the controller's Authorize fields,
+// key = method name or empty for the controller's ones.
+func parseAuthorizeTags(typ reflect.Type) map[string][]string {
+	typ = indirectType(typ)
+	if typ.Kind() != reflect.Struct {
+		return nil
+	}
+
+	var requirements map[string][]string
+	for i := 0; i < typ.NumField(); i++ {
+		f := typ.Field(i)
+		if f.Type != authorizeTyp {
+			continue
+		}
+
+		if requirements == nil {
+			requirements = make(map[string][]string)
+		}
+
+		for _, kv := range structTagPairs(f.Tag) {
+			var methodName string
+			if kv[0] != authorizeTagKey {
+				if !strings.HasPrefix(kv[0], authorizeTagKey+".") {
+					continue
+				}
+				methodName = kv[0][len(authorizeTagKey)+1:]
+			}
+
+			for _, requirement := range strings.Split(kv[1], ",") {
+				if requirement = strings.TrimSpace(requirement); requirement != "" {
+					requirements[methodName] = append(requirements[methodName], requirement)
+				}
+			}
+		}
+	}
+
+	return requirements
+}
+
+// checkAuthorization logs an error for each "authorize.MethodName" tag
+// which does not name a method of the controller, e.g. a typo,
+// as its routes would be registered without the tag's requirements.
+func (c *ControllerActivator) checkAuthorization() {
+	for _, methodName := range sortedKeys(c.authorization) {
+		if methodName == "" {
+			continue
+		}
+
+		if _, ok := c.Type.MethodByName(methodName); !ok {
+			c.logErrorf("MVC: authorize tag: '%s.%s': method does not exist", c.fullName, methodName)
+		}
+	}
+}
+
+// authorizationOf returns the controller's and the "funcName" method's requirements.
+func (c *ControllerActivator) authorizationOf(funcName string) []string {
+	if len(c.authorization) == 0 {
+		return nil
+	}
+
+	return append(append([]string(nil), c.authorization[""]...), c.authorization[funcName]...)
+}
+
+// structTagPairs returns all the key:"value" pairs of a struct tag,
+// it follows the conventional format of the reflect.StructTag.
+func structTagPairs(tag reflect.StructTag) [][2]string {
+	var pairs [][2]string
+
+	for tag != "" {
+		i := 0
+		for i < len(tag) && tag[i] == ' ' {
+			i++
+		}
+		tag = tag[i:]
+		if tag == "" {
+			break
+		}
+
+		i = 0
+		for i < len(tag) && tag[i] > ' ' && tag[i] != ':' && tag[i] != '"' && tag[i] != 0x7f {
+			i++
+		}
+		if i == 0 || i+1 >= len(tag) || tag[i] != ':' || tag[i+1] != '"' {
+			break
+		}
+		name := string(tag[:i])
+		tag = tag[i+1:]
+
+		i = 1
+		for i < len(tag) && tag[i] != '"' {
+			if tag[i] == '\\' {
+				i++
+			}
+			i++
+		}
+		if i >= len(tag) {
+			break
+		}
+		quoted := string(tag[:i+1])
+		tag = tag[i+1:]
+
+		value, err := strconv.Unquote(quoted)
+		if err != nil {
+			break
+		}
+
+		pairs = append(pairs, [2]string{name, value})
+	}
+
+	return pairs
+}
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -101,6 +101,10 @@ type ControllerActivator struct {
 
 	// true to skip the internal "activate".
 	activated bool
+
+	// the requirements of the controller's Authorize fields,
+	// key = method name or empty for the controller's ones.
+	authorization map[string][]string
 }
 
 // NameOf returns the package name + the struct type's name,
@@ -139,7 +143,8 @@ func newControllerActivator(app *Application, controller interface{}) *Controlle
 		// are being appended to the slice at the `parseMethods`,
 		// if a new method is registered via `Handle` its function name
 		// is also appended to that slice.
-		routes: whatReservedMethods(typ),
+		routes:        whatReservedMethods(typ),
+		authorization: parseAuthorizeTags(typ),
 	}
 
 	if IgnoreEmbeddedControllers {
@@ -394,6 +399,7 @@ func (c *ControllerActivator) activate() {
 
 	c.parseMethods()
 	c.parseHTTPErrorHandler()
+	c.checkAuthorization()
 }
 
 func (c *ControllerActivator) parseHTTPErrorHandler() {
@@ -499,6 +505,12 @@ func (c *ControllerActivator) handleMany(method, path, funcName string, override
 	handler := c.handlerOf(path, funcName)
 	middleware = context.JoinHandlers(c.BeginHandlers, middleware)
 
+	// the authorization runs last, after any authentication middleware.
+	requirements := c.authorizationOf(funcName)
+	if len(requirements) > 0 {
+		middleware = append(middleware, c.app.Router.AuthorizeHandler(requirements...))
+	}
+
 	// register the handler now.
 	routes := c.app.Router.HandleMany(method, path, append(middleware, handler)...)
 	if routes == nil {
@@ -506,6 +518,10 @@ func (c *ControllerActivator) handleMany(method, path, funcName string, override
 		return nil
 	}
 
+	for _, r := range routes {
+		r.Authorization = append(r.Authorization, requirements...)
+	}
+
 	c.saveRoutes(funcName, routes, override)
 	return routes
 }
@@ -2,7 +2,9 @@
 package mvc_test
 
 import (
+	"bytes"
 	"fmt"
+	"strings"
 	"testing"
 
 	"github.com/kataras/iris/v12"
@@ -759,3 +761,71 @@ func (c *testControllerFieldErrorHandlerContinue) HandleError(ctx iris.Context,
 func (c *testControllerFieldErrorHandlerContinue) PostTestField() string {
 	return fmt.Sprintf("%s is %d years old\n", c.Form.Username, c.Form.Age)
 }
+
+type testControllerAuthorize struct {
+	_ Authorize `authorize:"user" authorize.Delete:"admin" authorize.PostBy:"admin, editor"`
+}
+
+func (c *testControllerAuthorize) Get() string {
+	return "get"
+}
+
+func (c *testControllerAuthorize) Delete() string {
+	return "delete"
+}
+
+func (c *testControllerAuthorize) PostBy(id int) string {
+	return fmt.Sprintf("post %d", id)
+}
+
+func TestControllerAuthorize(t *testing.T) {
+	app := iris.New()
+	app.Use(func(ctx iris.Context) {
+		if roles := ctx.URLParamSlice("role"); len(roles) > 0 {
+			ctx.SetUser(&context.SimpleUser{ID: "kataras", Roles: roles})
+		}
+		ctx.Next()
+	})
+	m := New(app.Party("/authorize"))
+	m.Handle(new(testControllerAuthorize))
+
+	e := httptest.New(t, app)
+	e.GET("/authorize").Expect().Status(httptest.StatusUnauthorized)
+	e.GET("/authorize").WithQuery("role", "guest").Expect().Status(httptest.StatusForbidden)
+	e.GET("/authorize").WithQuery("role", "user").Expect().Status(httptest.StatusOK).Body().IsEqual("get")
+	e.DELETE("/authorize").WithQuery("role", "user").Expect().Status(httptest.StatusForbidden)
+	e.DELETE("/authorize").WithQuery("role", "user").WithQuery("role", "admin").
+		Expect().Status(httptest.StatusOK).Body().IsEqual("delete")
+	e.POST("/authorize/42").WithQuery("role", "user").WithQuery("role", "admin").
+		Expect().Status(httptest.StatusForbidden)
+	e.POST("/authorize/42").WithQuery("role", "user").WithQuery("role", "admin").WithQuery("role", "editor").
+		Expect().Status(httptest.StatusOK).Body().IsEqual("post 42")
+
+	for _, r := range app.GetRoutes() {
+		if r.Method == iris.MethodPost {
+			if expected, got := []string{"user", "admin", "editor"}, r.Authorization; fmt.Sprint(expected) != fmt.Sprint(got) {
+				t.Fatalf("expected route authorization: %v but got: %v", expected, got)
+			}
+		}
+	}
+}
+
+type testControllerAuthorizeTypo struct {
+	_ Authorize `authorize.DeletBy:"admin"`
+}
+
+func (c *testControllerAuthorizeTypo) DeleteBy(id int) string {
+	return fmt.Sprintf("delete %d", id)
+}
+
+func TestControllerAuthorizeUnknownMethod(t *testing.T) {
+	var buf bytes.Buffer
+	app := iris.New()
+	app.Logger().SetOutput(&buf)
+
+	New(app.Party("/typo")).Handle(new(testControllerAuthorizeTypo))
+
+	if expected, got := "testControllerAuthorizeTypo.DeletBy': method does not exist", buf.String(); !strings.Contains(got, expected) {
+		t.Fatalf("expected log to contain: %q but got: %q", expected, got)
+	}
+}
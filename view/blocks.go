@@ -3,6 +3,9 @@ package view
 import (
 	"html/template"
 	"io"
+	"io/fs"
+	"strings"
+	"sync"
 
 	"github.com/kataras/blocks"
 )
@@ -18,21 +21,37 @@ import (
 // - urlpath "routename" parameters...
 // - tr "language" "key" arguments...
 // - partial "template_name" data
+// - async "template_name" data, see `Stream`
 //
 // Read more at: https://github.com/kataras/blocks.
 type BlocksEngine struct {
 	Engine *blocks.Blocks
+
+	fs     fs.FS // the file system of the templates, if known, see `FileSystem`.
+	stream bool
+
+	// protects the templates of the Engine from the ReloadTemplates
+	// while they are executed.
+	mu sync.RWMutex
 }
 
 var (
-	_ Engine       = (*BlocksEngine)(nil)
-	_ EngineFuncer = (*BlocksEngine)(nil)
+	_ Engine         = (*BlocksEngine)(nil)
+	_ EngineFuncer   = (*BlocksEngine)(nil)
+	_ EngineReloader = (*BlocksEngine)(nil)
+
+	_ EngineBlockExecutor = (*BlocksEngine)(nil)
 )
 
 // WrapBlocks wraps an initialized blocks engine and returns its Iris adapter.
 // See `Blocks` package-level function too.
 func WrapBlocks(v *blocks.Blocks) *BlocksEngine {
-	return &BlocksEngine{Engine: v}
+	s := &BlocksEngine{Engine: v}
+	v.Funcs(template.FuncMap{
+		"async": asyncFunc(func() bool { return s.stream }, v.PartialFunc),
+	})
+
+	return s
 }
 
 // Blocks returns a new blocks view engine.
@@ -45,7 +64,9 @@ func WrapBlocks(v *blocks.Blocks) *BlocksEngine {
 // Blocks(iris.Dir("./views"), ".html") or
 // Blocks(embed.FS, ".html") or Blocks(AssetFile(), ".html") for embedded data.
 func Blocks(fs interface{}, extension string) *BlocksEngine {
-	return WrapBlocks(blocks.New(fs).Extension(extension))
+	s := WrapBlocks(blocks.New(fs).Extension(extension))
+	s.fs = getFS(fs)
+	return s
 }
 
 // Name returns the blocks engine's name.
@@ -56,9 +77,33 @@ func (s *BlocksEngine) Name() string {
 // RootDir sets the directory to use as the root one inside the provided File System.
 func (s *BlocksEngine) RootDir(root string) *BlocksEngine {
 	s.Engine.RootDir(root)
+
+	if s.fs != nil && root != "" && root != "/" && root != "." {
+		if sub, err := fs.Sub(s.fs, strings.TrimPrefix(root, "/")); err == nil {
+			s.fs = sub
+		}
+	}
+
 	return s
 }
 
+// FileSystem returns the file system of the templates given on the `Blocks` function,
+// it returns nil for the engines created by `WrapBlocks`.
+func (s *BlocksEngine) FileSystem() fs.FS {
+	return s.fs
+}
+
+// ReloadTemplates re-parses all the templates, the blocks engine
+// does not support reloading a subset of them.
+//
+// See `View.Watch` too.
+func (s *BlocksEngine) ReloadTemplates(names ...string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.Engine.Load()
+}
+
 // LayoutDir sets a custom layouts directory,
 // always relative to the "rootDir" one.
 // Layouts are recognised by their prefix names.
@@ -109,16 +154,57 @@ func (s *BlocksEngine) Reload(b bool) *BlocksEngine {
 	return s
 }
 
+// Stream if set to true the pages are streamed to the client while they are rendered:
+// the response is flushed right after the layout's </head>
+// and the templates rendered through the "async" template function,
+// e.g. {{ async "partials/orders" . }}, are rendered in the background
+// and written in the order they complete, see `HTMLEngine.Stream` for details.
+//
+// When streaming is disabled the "async" function renders the template in place.
+func (s *BlocksEngine) Stream(enable bool) *BlocksEngine {
+	s.stream = enable
+	return s
+}
+
 // Load parses the files into templates.
 func (s *BlocksEngine) Load() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
 	return s.Engine.Load()
 }
 
+// ExecuteBlock renders only the {{ define "block" }} of the "tmplName" template on "w".
+func (s *BlocksEngine) ExecuteBlock(w io.Writer, tmplName, block string, data interface{}) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tmpl, ok := s.Engine.Templates[tmplName]
+	if !ok {
+		return ErrNotExist{Name: tmplName, IsLayout: false, Data: data}
+	}
+
+	t := tmpl.Lookup(block)
+	if t == nil {
+		return ErrNotExist{Name: block, IsLayout: false, Data: data}
+	}
+
+	return t.Execute(w, data)
+}
+
 // ExecuteWriter renders a template on "w".
 func (s *BlocksEngine) ExecuteWriter(w io.Writer, tmplName, layoutName string, data interface{}) error {
 	if layoutName == NoLayout {
 		layoutName = ""
 	}
 
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.stream {
+		sw := newStreamWriter(w)
+		return sw.finish(s.Engine.ExecuteTemplate(sw, tmplName, layoutName, data))
+	}
+
 	return s.Engine.ExecuteTemplate(w, tmplName, layoutName, data)
 }
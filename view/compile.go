@@ -0,0 +1,294 @@
+package view
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/format"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// TemplateError describes a template which failed to parse
+// or which references a template that does not exist.
+// See the `Compile` package-level function.
+type TemplateError struct {
+	// Name is the template file name.
+	Name string
+	// Line is the line of the template file where the error occurred,
+	// zero if the engine did not report it.
+	Line int
+	// Err is the parse error of the engine
+	// or an `ErrMissingTemplate` one.
+	Err error
+}
+
+// Error returns the "name:line: error" form of the template error.
+func (e *TemplateError) Error() string {
+	if e.Name == "" {
+		return e.Err.Error()
+	}
+
+	if e.Line > 0 {
+		return fmt.Sprintf("%s:%d: %v", e.Name, e.Line, e.Err)
+	}
+
+	return fmt.Sprintf("%s: %v", e.Name, e.Err)
+}
+
+// Unwrap returns the underline error.
+func (e *TemplateError) Unwrap() error {
+	return e.Err
+}
+
+// ErrMissingTemplate is reported by the `Compile` function
+// when a template references another template which does not exist.
+var ErrMissingTemplate = errors.New("missing template")
+
+// CompileReport is the result of the `Compile` package-level function.
+type CompileReport struct {
+	// Engine is the name of the compiled view engine.
+	Engine string
+	// Templates holds the names of the template files, sorted.
+	Templates []string
+	// Errors holds the parse errors and the missing template references.
+	Errors []*TemplateError
+}
+
+// Err returns all the template errors joined, nil if the templates are valid.
+func (r *CompileReport) Err() error {
+	errs := make([]error, 0, len(r.Errors))
+	for _, err := range r.Errors {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+// WriteEmbed writes the source code of a Go file of the "packageName" package
+// which embeds exactly the verified template files into a `Templates` embed.FS variable.
+// The file should be saved at the root directory of the templates,
+// so the production binaries boot with the verified template set in one pass, e.g.
+//
+//	app.RegisterView(iris.HTML(views.Templates, ".html"))
+//
+// It returns the report's errors, without writing anything, if the templates are not valid.
+func (r *CompileReport) WriteEmbed(w io.Writer, packageName string) error {
+	if err := r.Err(); err != nil {
+		return err
+	}
+
+	if len(r.Templates) == 0 {
+		return fmt.Errorf("no templates found")
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by view.Compile of the %s engine. DO NOT EDIT.\n\n", r.Engine)
+	fmt.Fprintf(&buf, "package %s\n\n", packageName)
+	buf.WriteString("import \"embed\"\n\n")
+	buf.WriteString("// Templates holds the verified template files.\n")
+	for _, name := range r.Templates {
+		fmt.Fprintf(&buf, "//\n//go:embed %s\n", strconv.Quote(name))
+	}
+	buf.WriteString("var Templates embed.FS\n")
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(src)
+	return err
+}
+
+// WriteEmbedFile calls the `WriteEmbed` method and saves the result to the "filename".
+// The file is not touched if the templates are not valid.
+func (r *CompileReport) WriteEmbedFile(filename, packageName string) error {
+	var buf bytes.Buffer
+	if err := r.WriteEmbed(&buf, packageName); err != nil {
+		return err
+	}
+
+	return os.WriteFile(filename, buf.Bytes(), os.FileMode(0644))
+}
+
+// templateChecker is implemented by the view engines
+// which can parse a single template file without modifying their templates.
+type templateChecker interface {
+	checkTemplate(name string, contents []byte) error
+}
+
+var (
+	// templateFileReferenceRegex matches the quoted names of the template files that a template references,
+	// the partial and partial_r functions and the handlebars partials are not template files.
+	templateFileReferenceRegex = regexp.MustCompile(`\b(?:template|render|extends|include|import)\s+["'` + "`" + `]([\w./-]+)["'` + "`" + `]`)
+	// templateDefinitionRegex matches the names of the templates that a template file defines.
+	templateDefinitionRegex = regexp.MustCompile(`\b(?:define|block)\s+["'` + "`" + `]([\w./-]+)["'` + "`" + `]`)
+	// templateErrorLineRegex matches the line of a parse error of the builtin engines, e.g.
+	// "template: index.html:3: ...", "[Error (where: parser) in <string> | Line 3 Col 1 ...]"
+	// and "Parse error on line 3".
+	templateErrorLineRegex = regexp.MustCompile(`(?:\w:|[Ll]ine\s+)(\d+)`)
+)
+
+func newTemplateError(name string, err error) *TemplateError {
+	tErr := &TemplateError{Name: name, Err: err}
+
+	if name == "" { // an error of the whole set.
+		return tErr
+	}
+
+	if m := templateErrorLineRegex.FindStringSubmatch(err.Error()); len(m) > 1 {
+		tErr.Line, _ = strconv.Atoi(m[1])
+	}
+
+	return tErr
+}
+
+// Compile validates and parses all the templates of the "e" view engine,
+// it is designed to run at "go generate" time. Each template file is parsed separately,
+// so all of the syntax errors are reported with their file and line,
+// followed by the references to templates that do not exist.
+// The engine should have its template functions registered before the call,
+// see the `Application.CompileViews` method which registers the framework's ones too.
+//
+// The engine should implement the `EngineReloader` interface, all the builtin engines do.
+// It returns a non-nil error if the template files could not be read,
+// the template errors are reported through the `CompileReport.Errors` field.
+// Use the `CompileReport.WriteEmbedFile` method to embed the verified templates.
+//
+// Example Code:
+//
+//	//go:build ignore
+//
+//	package main
+//
+//	func main() {
+//		engine := view.HTML("./views", ".html").Layout("layouts/main.html")
+//		report, err := view.Compile(engine)
+//		if err != nil {
+//			log.Fatal(err)
+//		}
+//		if err = report.WriteEmbedFile("./views/views.go", "views"); err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+func Compile(e Engine) (*CompileReport, error) {
+	r, ok := e.(EngineReloader)
+	if !ok {
+		return nil, fmt.Errorf("%s: compile is not supported", e.Name())
+	}
+
+	fileSystem := r.FileSystem()
+	if fileSystem == nil || context.IsNoOpFS(fileSystem) {
+		return nil, fmt.Errorf("%s: compile: templates are not loaded from a file system", e.Name())
+	}
+
+	files, err := templateFiles(fileSystem, e.Ext())
+	if err != nil {
+		return nil, err
+	}
+
+	report := &CompileReport{
+		Engine:    e.Name(),
+		Templates: make([]string, 0, len(files)),
+	}
+
+	for name := range files {
+		report.Templates = append(report.Templates, name)
+	}
+	sort.Strings(report.Templates)
+
+	var (
+		contents = make(map[string][]byte, len(files))
+		defined  = make(map[string]struct{})
+		failed   = make(map[string]struct{})
+	)
+
+	checker, canCheck := e.(templateChecker)
+
+	for _, name := range report.Templates {
+		b, err := asset(fileSystem, name)
+		if err != nil {
+			return nil, err
+		}
+		contents[name] = b
+
+		for _, m := range templateDefinitionRegex.FindAllSubmatch(b, -1) {
+			defined[string(m[1])] = struct{}{}
+		}
+
+		if canCheck {
+			if err = checker.checkTemplate(name, b); err != nil {
+				report.Errors = append(report.Errors, newTemplateError(name, err))
+				failed[name] = struct{}{}
+			}
+		}
+	}
+
+	for _, name := range report.Templates {
+		if _, ok := failed[name]; ok { // the parse error is enough.
+			continue
+		}
+
+		b := contents[name]
+
+		for _, m := range templateFileReferenceRegex.FindAllSubmatchIndex(b, -1) {
+			ref := string(b[m[2]:m[3]])
+			if _, ok := defined[ref]; ok {
+				continue
+			}
+
+			if !referenceExists(name, ref, e.Ext(), files) {
+				report.Errors = append(report.Errors, &TemplateError{
+					Name: name,
+					Line: bytes.Count(b[:m[2]], []byte("\n")) + 1,
+					Err:  fmt.Errorf("%w: %q", ErrMissingTemplate, ref),
+				})
+			}
+		}
+	}
+
+	if len(report.Errors) == 0 || !canCheck {
+		// parse the whole set, as the application does on boot.
+		if err = e.Load(); err != nil {
+			report.Errors = append(report.Errors, newTemplateError("", err))
+		}
+	}
+
+	return report, nil
+}
+
+func referenceExists(name, ref, ext string, files map[string]templateFile) bool {
+	for _, candidate := range templateReferenceCandidates(name, ref, ext) {
+		if _, ok := files[candidate]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// templateReferenceCandidates returns the possible file names of the "ref" template
+// referenced by the "name" one, as references can be relative to the template's directory
+// and can omit the extension.
+func templateReferenceCandidates(name, ref, ext string) []string {
+	var candidates []string
+
+	for _, candidate := range []string{ref, path.Join(path.Dir(name), ref)} {
+		candidate = strings.TrimPrefix(path.Clean("/"+candidate), "/")
+		candidates = append(candidates, candidate)
+
+		if ext != "" && !strings.HasSuffix(candidate, ext) {
+			candidates = append(candidates, candidate+ext)
+		}
+	}
+
+	return candidates
+}
@@ -0,0 +1,79 @@
+package view
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, contents string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), os.FileMode(0644)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("index.html", `{{ define "title" }}Index{{ end }}{{ template "title" }} {{ render "partial.html" }}`)
+	write("partial.html", "partial")
+	write("broken.html", "line 1\n{{ .Broken ")
+	write("missing.html", "line 1\nline 2\n{{ template \"header.html\" }}")
+
+	report, err := Compile(HTML(dir, ".html"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedTemplates := []string{"broken.html", "index.html", "missing.html", "partial.html"}
+	if got := strings.Join(report.Templates, ","); got != strings.Join(expectedTemplates, ",") {
+		t.Fatalf("expected templates: %v but got: %v", expectedTemplates, report.Templates)
+	}
+
+	if len(report.Errors) != 2 {
+		t.Fatalf("expected two errors but got: %v", report.Err())
+	}
+
+	if tErr := report.Errors[0]; tErr.Name != "broken.html" || tErr.Line != 2 {
+		t.Fatalf("expected a parse error at broken.html:2 but got: %v", tErr)
+	}
+
+	if tErr := report.Errors[1]; tErr.Name != "missing.html" || tErr.Line != 3 || !errors.Is(tErr, ErrMissingTemplate) {
+		t.Fatalf("expected a missing template at missing.html:3 but got: %v", tErr)
+	}
+
+	var buf bytes.Buffer
+	if err = report.WriteEmbed(&buf, "views"); err == nil || buf.Len() > 0 {
+		t.Fatalf("expected the invalid templates to not be embedded")
+	}
+
+	os.Remove(filepath.Join(dir, "broken.html"))
+	os.Remove(filepath.Join(dir, "missing.html"))
+
+	if report, err = Compile(HTML(dir, ".html")); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = report.WriteEmbed(&buf, "views"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `// Code generated by view.Compile of the HTML engine. DO NOT EDIT.
+
+package views
+
+import "embed"
+
+// Templates holds the verified template files.
+//
+//go:embed "index.html"
+//go:embed "partial.html"
+var Templates embed.FS
+`
+	if got := buf.String(); got != expected {
+		t.Fatalf("expected:\n%s\nbut got:\n%s", expected, got)
+	}
+}
@@ -2,6 +2,8 @@ package view
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
 	"io"
 	"io/fs"
 	"os"
@@ -104,11 +106,15 @@ type DjangoEngine struct {
 	globals       map[string]interface{}
 	Set           *pongo2.TemplateSet
 	templateCache map[string]*pongo2.Template
+	deps          templateDeps
 }
 
 var (
-	_ Engine       = (*DjangoEngine)(nil)
-	_ EngineFuncer = (*DjangoEngine)(nil)
+	_ Engine         = (*DjangoEngine)(nil)
+	_ EngineFuncer   = (*DjangoEngine)(nil)
+	_ EngineReloader = (*DjangoEngine)(nil)
+
+	_ EngineBlockExecutor = (*DjangoEngine)(nil)
 )
 
 // Django creates and returns a new django view engine.
@@ -126,6 +132,7 @@ func Django(fs interface{}, extension string) *DjangoEngine {
 		globals:       make(map[string]interface{}),
 		filters:       make(map[string]FilterFunction),
 		templateCache: make(map[string]*pongo2.Template),
+		deps:          make(templateDeps),
 	}
 
 	return s
@@ -147,6 +154,11 @@ func (s *DjangoEngine) RootDir(root string) *DjangoEngine {
 	return s
 }
 
+// FileSystem returns the file system of the templates.
+func (s *DjangoEngine) FileSystem() fs.FS {
+	return s.fs
+}
+
 // Name returns the django engine's name.
 func (s *DjangoEngine) Name() string {
 	return "Django"
@@ -270,11 +282,73 @@ func (s *DjangoEngine) ParseTemplate(name string, contents []byte) error {
 	tmpl, err := s.Set.FromBytes(contents)
 	if err == nil {
 		s.templateCache[name] = tmpl
+		s.deps.set(name, contents, s.extension)
 	}
 
 	return err
 }
 
+// checkTemplate parses the "name" template file without caching it, see `Compile`.
+func (s *DjangoEngine) checkTemplate(name string, contents []byte) error {
+	s.rmu.Lock()
+	defer s.rmu.Unlock()
+
+	s.initSet()
+
+	_, err := s.Set.FromBytes(contents)
+	return err
+}
+
+// ReloadTemplates re-parses the "names" template files and the templates that depend on them,
+// e.g. the ones that extend or include them. New files are added and removed files are deleted.
+// On failure the previous templates keep serving.
+//
+// See `View.Watch` too.
+func (s *DjangoEngine) ReloadTemplates(names ...string) error {
+	s.rmu.Lock()
+	defer s.rmu.Unlock()
+
+	s.initSet()
+
+	var (
+		templates = make(map[string]*pongo2.Template)
+		removed   []string
+		deps      = s.deps.clone()
+	)
+
+	for _, name := range s.deps.dependents(names...) {
+		contents, err := asset(s.fs, name)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				removed = append(removed, name)
+				delete(deps, name)
+				continue
+			}
+
+			return err
+		}
+
+		tmpl, err := s.Set.FromBytes(contents)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		templates[name] = tmpl
+		deps.set(name, contents, s.extension)
+	}
+
+	for name, tmpl := range templates {
+		s.templateCache[name] = tmpl
+	}
+
+	for _, name := range removed {
+		delete(s.templateCache, name)
+	}
+
+	s.deps = deps
+	return nil
+}
+
 func (s *DjangoEngine) initSet() { // protected by the caller.
 	if s.Set == nil {
 		s.Set = pongo2.NewSet("", &tDjangoAssetLoader{fs: s.fs, rootDir: s.rootDir})
@@ -304,10 +378,9 @@ func getPongoContext(templateData interface{}) pongo2.Context {
 }
 
 func (s *DjangoEngine) fromCache(relativeName string) *pongo2.Template {
-	if s.reload {
-		s.rmu.RLock()
-		defer s.rmu.RUnlock()
-	}
+	// the templates may be replaced by a ReloadTemplates call.
+	s.rmu.RLock()
+	defer s.rmu.RUnlock()
 
 	if tmpl, ok := s.templateCache[relativeName]; ok {
 		return tmpl
@@ -315,6 +388,35 @@ func (s *DjangoEngine) fromCache(relativeName string) *pongo2.Template {
 	return nil
 }
 
+// ExecuteBlock executes only the {% block "block" %} of the "filename" template
+// and writes its result to the w writer.
+func (s *DjangoEngine) ExecuteBlock(w io.Writer, filename string, block string, bindingData interface{}) error {
+	// re-parse the templates if reload is enabled.
+	if s.reload {
+		if err := s.Load(); err != nil {
+			return err
+		}
+	}
+
+	tmpl := s.fromCache(filename)
+	if tmpl == nil {
+		return ErrNotExist{Name: filename, IsLayout: false, Data: bindingData}
+	}
+
+	result, err := tmpl.ExecuteBlocks(getPongoContext(bindingData), []string{block})
+	if err != nil {
+		return err
+	}
+
+	contents, ok := result[block]
+	if !ok {
+		return ErrNotExist{Name: block, IsLayout: false, Data: bindingData}
+	}
+
+	_, err = io.WriteString(w, contents)
+	return err
+}
+
 // ExecuteWriter executes a templates and write its results to the w writer
 // layout here is useless.
 func (s *DjangoEngine) ExecuteWriter(w io.Writer, filename string, _ string, bindingData interface{}) error {
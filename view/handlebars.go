@@ -1,6 +1,7 @@
 package view
 
 import (
+	"errors"
 	"fmt"
 	"html/template"
 	"io"
@@ -30,11 +31,13 @@ type HandlebarsEngine struct {
 	rmu           sync.RWMutex
 	funcs         template.FuncMap
 	templateCache map[string]*raymond.Template
+	deps          templateDeps
 }
 
 var (
-	_ Engine       = (*HandlebarsEngine)(nil)
-	_ EngineFuncer = (*HandlebarsEngine)(nil)
+	_ Engine         = (*HandlebarsEngine)(nil)
+	_ EngineFuncer   = (*HandlebarsEngine)(nil)
+	_ EngineReloader = (*HandlebarsEngine)(nil)
 )
 
 // Handlebars creates and returns a new handlebars view engine.
@@ -51,6 +54,7 @@ func Handlebars(fs interface{}, extension string) *HandlebarsEngine {
 		extension:     extension,
 		templateCache: make(map[string]*raymond.Template),
 		funcs:         make(template.FuncMap), // global
+		deps:          make(templateDeps),
 	}
 
 	// register the render helper here
@@ -81,6 +85,11 @@ func (s *HandlebarsEngine) RootDir(root string) *HandlebarsEngine {
 	return s
 }
 
+// FileSystem returns the file system of the templates.
+func (s *HandlebarsEngine) FileSystem() fs.FS {
+	return s.fs
+}
+
 // Name returns the handlebars engine's name.
 func (s *HandlebarsEngine) Name() string {
 	return "Handlebars"
@@ -173,28 +182,94 @@ func (s *HandlebarsEngine) ParseTemplate(name string, contents string, funcs tem
 	defer s.rmu.Unlock()
 
 	name = strings.TrimPrefix(name, "/")
-	tmpl, err := raymond.Parse(contents)
+	tmpl, err := s.parse(contents, funcs)
 	if err == nil {
-		// Add functions for this template.
-		for k, v := range s.funcs {
-			tmpl.RegisterHelper(k, v)
+		s.templateCache[name] = tmpl
+		s.deps.set(name, []byte(contents), s.extension)
+	}
+
+	return err
+}
+
+func (s *HandlebarsEngine) parse(contents string, funcs template.FuncMap) (*raymond.Template, error) {
+	tmpl, err := raymond.Parse(contents)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add functions for this template.
+	for k, v := range s.funcs {
+		tmpl.RegisterHelper(k, v)
+	}
+
+	for k, v := range funcs {
+		tmpl.RegisterHelper(k, v)
+	}
+
+	return tmpl, nil
+}
+
+// checkTemplate parses the "name" template file without caching it, see `Compile`.
+func (s *HandlebarsEngine) checkTemplate(name string, contents []byte) error {
+	s.rmu.RLock()
+	defer s.rmu.RUnlock()
+
+	_, err := s.parse(string(contents), nil)
+	return err
+}
+
+// ReloadTemplates re-parses the "names" template files and the templates that depend on them.
+// New files are added and removed files are deleted.
+// On failure the previous templates keep serving.
+//
+// See `View.Watch` too.
+func (s *HandlebarsEngine) ReloadTemplates(names ...string) error {
+	s.rmu.Lock()
+	defer s.rmu.Unlock()
+
+	var (
+		templates = make(map[string]*raymond.Template)
+		removed   []string
+		deps      = s.deps.clone()
+	)
+
+	for _, name := range s.deps.dependents(names...) {
+		contents, err := asset(s.fs, name)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				removed = append(removed, name)
+				delete(deps, name)
+				continue
+			}
+
+			return err
 		}
 
-		for k, v := range funcs {
-			tmpl.RegisterHelper(k, v)
+		tmpl, err := s.parse(string(contents), nil)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
 		}
 
+		templates[name] = tmpl
+		deps.set(name, contents, s.extension)
+	}
+
+	for name, tmpl := range templates {
 		s.templateCache[name] = tmpl
 	}
 
-	return err
+	for _, name := range removed {
+		delete(s.templateCache, name)
+	}
+
+	s.deps = deps
+	return nil
 }
 
 func (s *HandlebarsEngine) fromCache(relativeName string) *raymond.Template {
-	if s.reload {
-		s.rmu.RLock()
-		defer s.rmu.RUnlock()
-	}
+	// the templates may be replaced by a ReloadTemplates call.
+	s.rmu.RLock()
+	defer s.rmu.RUnlock()
 
 	if tmpl, ok := s.templateCache[relativeName]; ok {
 		return tmpl
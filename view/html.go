@@ -2,12 +2,14 @@ package view
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"html/template"
 	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"sync"
 
@@ -25,6 +27,8 @@ type HTMLEngine struct {
 	// if true, each time the ExecuteWriter is called the templates will be reloaded,
 	// each ExecuteWriter waits to be finished before writing to a new one.
 	reload bool
+	// if true, the pages are streamed, see `Stream`.
+	stream bool
 	// parser configuration
 	options     []string // (text) template options
 	left        string
@@ -41,6 +45,10 @@ type HTMLEngine struct {
 	Templates   *template.Template
 	customCache []customTmp // required to load them again if reload is true.
 	bufPool     *sync.Pool
+	// sources holds the text of the template files, after the middleware (e.g. pug and ace),
+	// so a reload re-reads and re-compiles only the changed files and their dependents.
+	sources map[string]string
+	deps    templateDeps
 	//
 }
 
@@ -51,8 +59,11 @@ type customTmp struct {
 }
 
 var (
-	_ Engine       = (*HTMLEngine)(nil)
-	_ EngineFuncer = (*HTMLEngine)(nil)
+	_ Engine         = (*HTMLEngine)(nil)
+	_ EngineFuncer   = (*HTMLEngine)(nil)
+	_ EngineReloader = (*HTMLEngine)(nil)
+
+	_ EngineBlockExecutor = (*HTMLEngine)(nil)
 )
 
 // HTML creates and returns a new html view engine.
@@ -110,6 +121,11 @@ func (s *HTMLEngine) FS(dirOrFS interface{}) *HTMLEngine {
 	return s
 }
 
+// FileSystem returns the file system of the templates.
+func (s *HTMLEngine) FileSystem() fs.FS {
+	return s.fs
+}
+
 // Name returns the engine's name.
 func (s *HTMLEngine) Name() string {
 	return s.name
@@ -133,6 +149,21 @@ func (s *HTMLEngine) Reload(developmentMode bool) *HTMLEngine {
 	return s
 }
 
+// Stream if set to true the pages are streamed to the client while they are rendered:
+// the response is flushed right after the layout's </head>
+// and the templates rendered through the "async" template function,
+// e.g. {{ async "partials/orders.html" . }}, are rendered in the background.
+// Each one is written, in the order they complete, as a <template> element after the page,
+// along with an inline script which swaps its placeholder with the rendered contents.
+// This improves the time-to-first-byte of pages which aggregate data of several slow backends.
+//
+// When streaming is disabled the "async" function renders the template in place.
+// Note that an error of an async template can not change the status code of the response.
+func (s *HTMLEngine) Stream(enable bool) *HTMLEngine {
+	s.stream = enable
+	return s
+}
+
 // Option sets options for the template. Options are described by
 // strings, either a simple string or "key=value". There can be at
 // most one equals sign in an option string. If the option string
@@ -203,6 +234,7 @@ func (s *HTMLEngine) AddLayoutFunc(funcName string, funcBody interface{}) *HTMLE
 // - url func(routeName string, args ...string) string
 // - urlpath func(routeName string, args ...string) string
 // - render func(fullPartialName string) (template.HTML, error).
+// - async func(fullPartialName string) (template.HTML, error), see `Stream`.
 // - tr func(lang, key string, args ...interface{}) string
 func (s *HTMLEngine) AddFunc(funcName string, funcBody interface{}) {
 	s.rmu.Lock()
@@ -248,18 +280,44 @@ func (s *HTMLEngine) load() error {
 		s.onLoad()
 	}
 
-	if err := s.reloadCustomTemplates(); err != nil {
+	sources := make(map[string]string)
+	deps := make(templateDeps)
+
+	var (
+		root *template.Template
+		err  error
+	)
+
+	// If only custom templates should be loaded the file system is not walked.
+	if s.fs != nil && !context.IsNoOpFS(s.fs) {
+		err = s.loadSources(sources, deps)
+	}
+
+	if err == nil {
+		root, err = s.parseSources(sources)
+	}
+
+	if s.onLoaded != nil {
+		s.onLoaded()
+	}
+
+	if err != nil {
 		return err
 	}
 
-	// If only custom templates should be loaded.
-	if (s.fs == nil || context.IsNoOpFS(s.fs)) && len(s.Templates.DefinedTemplates()) > 0 {
-		return nil
+	if root == nil {
+		return fmt.Errorf("no templates found")
 	}
 
+	s.Templates, s.sources, s.deps = root, sources, deps
+	return nil
+}
+
+// loadSources reads and compiles the template files of the file system.
+func (s *HTMLEngine) loadSources(sources map[string]string, deps templateDeps) error {
 	rootDirName := getRootDirName(s.fs)
 
-	err := walk(s.fs, "", func(path string, info os.FileInfo, err error) error {
+	return walk(s.fs, "", func(path string, info os.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -284,31 +342,114 @@ func (s *HTMLEngine) load() error {
 			return fmt.Errorf("%s: %w", path, err)
 		}
 
-		return s.parseTemplate(path, buf, nil)
+		text, err := s.compile(path, buf)
+		if err != nil {
+			return err
+		}
+
+		sources[path] = text
+		deps.set(path, buf, s.extension)
+		return nil
 	})
+}
 
-	if s.onLoaded != nil {
-		s.onLoaded()
+// compile returns the template text of the file contents, after the middleware if any.
+func (s *HTMLEngine) compile(name string, contents []byte) (string, error) {
+	if s.middleware != nil {
+		return s.middleware(strings.TrimPrefix(name, "/"), contents)
 	}
 
-	if err != nil {
-		return err
+	return string(contents), nil
+}
+
+// parseSources parses the custom templates and the "sources" to a new root template.
+// It returns a nil root template when there are no templates at all.
+func (s *HTMLEngine) parseSources(sources map[string]string) (*template.Template, error) {
+	if len(s.customCache) == 0 && len(sources) == 0 {
+		return nil, nil
 	}
 
-	if s.Templates == nil {
-		return fmt.Errorf("no templates found")
+	root := s.newRootTmpl()
+
+	for _, tmpl := range s.customCache {
+		text, err := s.compile(tmpl.name, tmpl.contents)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = s.parseText(root, tmpl.name, text, tmpl.funcs); err != nil {
+			return nil, err
+		}
 	}
 
-	return nil
+	names := make([]string, 0, len(sources))
+	for name := range sources {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if err := s.parseText(root, name, sources[name], nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return root, nil
 }
 
-func (s *HTMLEngine) reloadCustomTemplates() error {
-	for _, tmpl := range s.customCache {
-		if err := s.parseTemplate(tmpl.name, tmpl.contents, tmpl.funcs); err != nil {
+// ReloadTemplates re-reads and re-compiles the "names" template files
+// and the templates that depend on them, e.g. the ones that include them.
+// The rest of the templates are parsed again from memory.
+// New files are added and removed files are deleted.
+// On failure the previous templates keep serving.
+//
+// See `View.Watch` too.
+func (s *HTMLEngine) ReloadTemplates(names ...string) error {
+	s.rmu.Lock()
+	defer s.rmu.Unlock()
+
+	if s.sources == nil { // not loaded yet.
+		return s.load()
+	}
+
+	sources := make(map[string]string, len(s.sources))
+	for name, text := range s.sources {
+		sources[name] = text
+	}
+
+	deps := s.deps.clone()
+
+	for _, name := range s.deps.dependents(names...) {
+		contents, err := asset(s.fs, name)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				delete(sources, name)
+				delete(deps, name)
+				continue
+			}
+
 			return err
 		}
+
+		text, err := s.compile(name, contents)
+		if err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		sources[name] = text
+		deps.set(name, contents, s.extension)
+	}
+
+	root, err := s.parseSources(sources)
+	if err != nil {
+		return err
 	}
 
+	if root == nil {
+		return fmt.Errorf("no templates found")
+	}
+
+	s.Templates, s.sources, s.deps = root, sources, deps
 	return nil
 }
 
@@ -323,29 +464,38 @@ func (s *HTMLEngine) ParseTemplate(name string, contents []byte, funcs template.
 		funcs:    funcs,
 	})
 
-	return s.parseTemplate(name, contents, funcs)
+	if s.Templates == nil {
+		s.Templates = s.newRootTmpl()
+	}
+
+	text, err := s.compile(name, contents)
+	if err != nil {
+		return err
+	}
+
+	return s.parseText(s.Templates, name, text, funcs)
 }
 
-func (s *HTMLEngine) parseTemplate(name string, contents []byte, funcs template.FuncMap) (err error) {
-	s.initRootTmpl()
+// checkTemplate parses the "name" template file on a new root template, see `Compile`.
+func (s *HTMLEngine) checkTemplate(name string, contents []byte) error {
+	s.rmu.RLock()
+	defer s.rmu.RUnlock()
+
+	text, err := s.compile(name, contents)
+	if err != nil {
+		return err
+	}
+
+	return s.parseText(s.newRootTmpl(), name, text, nil)
+}
 
+func (s *HTMLEngine) parseText(root *template.Template, name string, text string, funcs template.FuncMap) (err error) {
 	name = strings.TrimPrefix(name, "/")
-	tmpl := s.Templates.New(name)
+	tmpl := root.New(name)
 	// tmpl.Option("missingkey=error")
 	tmpl.Option(s.options...)
 
-	var text string
-
-	if s.middleware != nil {
-		text, err = s.middleware(name, contents)
-		if err != nil {
-			return
-		}
-	} else {
-		text = string(contents)
-	}
-
-	tmpl.Funcs(s.getBuiltinFuncs(name)).Funcs(s.funcs)
+	tmpl.Funcs(s.getBuiltinFuncs(root, name)).Funcs(s.funcs)
 
 	if strings.Contains(name, "layout") {
 		tmpl.Funcs(s.layoutFuncs)
@@ -358,31 +508,26 @@ func (s *HTMLEngine) parseTemplate(name string, contents []byte, funcs template.
 	return
 }
 
-func (s *HTMLEngine) initRootTmpl() { // protected by the caller.
-	if s.Templates == nil {
-		// the root template should be the same,
-		// no matter how many reloads as the
-		// following unexported fields cannot be modified.
-		// However, on reload they should be cleared otherwise we get an error.
-		s.Templates = template.New(s.rootDir)
-		s.Templates.Delims(s.left, s.right)
-	}
+func (s *HTMLEngine) newRootTmpl() *template.Template { // protected by the caller.
+	root := template.New(s.rootDir)
+	root.Delims(s.left, s.right)
+	return root
 }
 
-func (s *HTMLEngine) executeTemplateBuf(name string, binding interface{}) (string, error) {
+func (s *HTMLEngine) executeTemplateBuf(root *template.Template, name string, binding interface{}) (string, error) {
 	buf := s.bufPool.Get().(*bytes.Buffer)
 	buf.Reset()
 
-	err := s.Templates.ExecuteTemplate(buf, name, binding)
+	err := root.ExecuteTemplate(buf, name, binding)
 	result := buf.String()
 	s.bufPool.Put(buf)
 	return result, err
 }
 
-func (s *HTMLEngine) getBuiltinRuntimeLayoutFuncs(name string) template.FuncMap {
+func (s *HTMLEngine) getBuiltinRuntimeLayoutFuncs(root *template.Template, name string) template.FuncMap {
 	funcs := template.FuncMap{
 		"yield": func(binding interface{}) (template.HTML, error) {
-			result, err := s.executeTemplateBuf(name, binding)
+			result, err := s.executeTemplateBuf(root, name, binding)
 			// Return safe HTML here since we are rendering our own template.
 			return template.HTML(result), err
 		},
@@ -391,12 +536,12 @@ func (s *HTMLEngine) getBuiltinRuntimeLayoutFuncs(name string) template.FuncMap
 	return funcs
 }
 
-func (s *HTMLEngine) getBuiltinFuncs(name string) template.FuncMap {
+func (s *HTMLEngine) getBuiltinFuncs(root *template.Template, name string) template.FuncMap {
 	funcs := template.FuncMap{
 		"part": func(partName string, binding interface{}) (template.HTML, error) {
 			nameTemp := strings.ReplaceAll(name, s.extension, "")
 			fullPartName := fmt.Sprintf("%s-%s", nameTemp, partName)
-			result, err := s.executeTemplateBuf(fullPartName, binding)
+			result, err := s.executeTemplateBuf(root, fullPartName, binding)
 			if err != nil {
 				return "", nil
 			}
@@ -407,8 +552,8 @@ func (s *HTMLEngine) getBuiltinFuncs(name string) template.FuncMap {
 		},
 		"partial": func(partialName string, binding interface{}) (template.HTML, error) {
 			fullPartialName := fmt.Sprintf("%s-%s", partialName, name)
-			if s.Templates.Lookup(fullPartialName) != nil {
-				result, err := s.executeTemplateBuf(fullPartialName, binding)
+			if root.Lookup(fullPartialName) != nil {
+				result, err := s.executeTemplateBuf(root, fullPartialName, binding)
 				return template.HTML(result), err
 			}
 			return "", nil
@@ -419,18 +564,22 @@ func (s *HTMLEngine) getBuiltinFuncs(name string) template.FuncMap {
 		// templates/users/index.html would load templates/users/index.script.html
 		"partial_r": func(partialName string, binding interface{}) (template.HTML, error) {
 			ext := filepath.Ext(name)
-			root := name[:len(name)-len(ext)]
-			fullPartialName := fmt.Sprintf("%s%s%s", root, partialName, ext)
-			if s.Templates.Lookup(fullPartialName) != nil {
-				result, err := s.executeTemplateBuf(fullPartialName, binding)
+			base := name[:len(name)-len(ext)]
+			fullPartialName := fmt.Sprintf("%s%s%s", base, partialName, ext)
+			if root.Lookup(fullPartialName) != nil {
+				result, err := s.executeTemplateBuf(root, fullPartialName, binding)
 				return template.HTML(result), err
 			}
 			return "", nil
 		},
 		"render": func(fullPartialName string, binding interface{}) (template.HTML, error) {
-			result, err := s.executeTemplateBuf(fullPartialName, binding)
+			result, err := s.executeTemplateBuf(root, fullPartialName, binding)
 			return template.HTML(result), err
 		},
+		"async": asyncFunc(func() bool { return s.stream }, func(fullPartialName string, binding interface{}) (template.HTML, error) {
+			result, err := s.executeTemplateBuf(root, fullPartialName, binding)
+			return template.HTML(result), err
+		}),
 	}
 
 	return funcs
@@ -438,30 +587,85 @@ func (s *HTMLEngine) getBuiltinFuncs(name string) template.FuncMap {
 
 // ExecuteWriter executes a template and writes its result to the w writer.
 func (s *HTMLEngine) ExecuteWriter(w io.Writer, name string, layout string, bindingData interface{}) error {
-	// re-parse the templates if reload is enabled.
+	if s.stream {
+		sw := newStreamWriter(w)
+		return sw.finish(s.executeWriter(sw, name, layout, bindingData))
+	}
+
+	return s.executeWriter(w, name, layout, bindingData)
+}
+
+// ExecuteBlock executes only the "block" template of the "name" template file and writes its result to the w writer.
+// The block is looked up as "name-block", e.g. {{ define "index-sidebar" }} for the index.html file
+// (same as the "part" template function), and then as "block", e.g. {{ define "sidebar" }}.
+func (s *HTMLEngine) ExecuteBlock(w io.Writer, name string, block string, bindingData interface{}) error {
+	root, release, err := s.root()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if root == nil || root.Lookup(name) == nil {
+		return ErrNotExist{Name: name, IsLayout: false, Data: bindingData}
+	}
+
+	t := root.Lookup(fmt.Sprintf("%s-%s", strings.TrimSuffix(name, s.extension), block))
+	if t == nil {
+		if t = root.Lookup(block); t == nil {
+			return ErrNotExist{Name: block, IsLayout: false, Data: bindingData}
+		}
+	}
+
+	return t.Execute(w, bindingData)
+}
+
+// root returns the root template, it re-parses the templates first if reload is enabled.
+// The "release" function should be called after the execution.
+func (s *HTMLEngine) root() (root *template.Template, release func(), err error) {
 	if s.reload {
 		s.rmu.Lock()
-		defer s.rmu.Unlock()
 
 		s.Templates = nil
 		// we lose the templates parsed manually, so store them when it's called
 		// in order for load to take care of them too.
 
-		if err := s.load(); err != nil {
-			return err
+		if err = s.load(); err != nil {
+			s.rmu.Unlock()
+			return nil, nil, err
 		}
+
+		return s.Templates, s.rmu.Unlock, nil
+	}
+
+	// the templates may be replaced by a ReloadTemplates call.
+	s.rmu.RLock()
+	root = s.Templates
+	s.rmu.RUnlock()
+
+	return root, func() {}, nil
+}
+
+func (s *HTMLEngine) executeWriter(w io.Writer, name string, layout string, bindingData interface{}) error {
+	root, release, err := s.root()
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	if root == nil {
+		return ErrNotExist{Name: name, IsLayout: false, Data: bindingData}
 	}
 
 	if layout = getLayout(layout, s.layout); layout != "" {
-		lt := s.Templates.Lookup(layout)
+		lt := root.Lookup(layout)
 		if lt == nil {
 			return ErrNotExist{Name: layout, IsLayout: true, Data: bindingData}
 		}
 
-		return lt.Funcs(s.getBuiltinRuntimeLayoutFuncs(name)).Execute(w, bindingData)
+		return lt.Funcs(s.getBuiltinRuntimeLayoutFuncs(root, name)).Execute(w, bindingData)
 	}
 
-	t := s.Templates.Lookup(name)
+	t := root.Lookup(name)
 	if t == nil {
 		return ErrNotExist{Name: name, IsLayout: false, Data: bindingData}
 	}
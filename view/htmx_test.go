@@ -0,0 +1,79 @@
+package view_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/kataras/iris/v12"
+	"github.com/kataras/iris/v12/context"
+	"github.com/kataras/iris/v12/httptest"
+)
+
+func TestViewHTMX(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, contents string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), os.FileMode(0644)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("layout.html", `<html>{{ yield . }}</html>`)
+	write("index.html", `{{ define "index-results" }}<li>{{ . }}</li>{{ end }}<ul id="results">{{ template "index-results" . }}</ul>`)
+	write("count.html", `{{ define "count" }}{{ . }} items{{ end }}`)
+	write("admin.html", `{{ define "secret" }}admin: {{ . }}{{ end }}admin page`)
+
+	app := iris.New()
+	app.RegisterView(iris.HTML(dir, ".html").Layout("layout.html"))
+	app.Get("/", func(ctx iris.Context) {
+		if ctx.IsHTMX() {
+			ctx.HTMXTrigger("searched", 1)
+		}
+
+		if err := ctx.ViewHTMX("index.html", "result"); err != nil {
+			return
+		}
+
+		if ctx.IsHTMX() {
+			ctx.ViewOOB("#count", "count.html", "count", 1)
+		}
+	})
+	app.Get("/block", func(ctx iris.Context) {
+		ctx.ViewBlock("index.html", "results", "block")
+	})
+	app.Post("/logout", func(ctx iris.Context) {
+		ctx.HTMXRedirect("/login")
+	})
+
+	e := httptest.New(t, app)
+
+	e.GET("/").Expect().Status(httptest.StatusOK).
+		Header(context.VaryHeaderKey).Equal(context.HTMXRequestHeaderKey)
+	e.GET("/").Expect().Status(httptest.StatusOK).
+		Body().IsEqual(`<html><ul id="results"><li>result</li></ul></html>`)
+
+	// The block of the target element.
+	resp := e.GET("/").WithHeader("HX-Request", "true").WithHeader("HX-Target", "results").Expect().Status(httptest.StatusOK)
+	resp.Header(context.HTMXTriggerHeaderKey).Equal(`{"searched":1}`)
+	resp.Body().IsEqual(`<li>result</li><div hx-swap-oob="innerHTML:#count">1 items</div>`)
+
+	// The whole template without the layout when there is no block for the target.
+	e.GET("/").WithHeader("HX-Request", "true").WithHeader("HX-Target", "main").Expect().Status(httptest.StatusOK).
+		Body().IsEqual(`<ul id="results"><li>result</li></ul><div hx-swap-oob="innerHTML:#count">1 items</div>`)
+
+	// Templates and blocks of other files are not rendered by the client's target.
+	for _, target := range []string{"secret", "admin.html", "layout.html", "count"} {
+		e.GET("/").WithHeader("HX-Request", "true").WithHeader("HX-Target", target).Expect().Status(httptest.StatusOK).
+			Body().IsEqual(`<ul id="results"><li>result</li></ul><div hx-swap-oob="innerHTML:#count">1 items</div>`)
+	}
+
+	// History restore requests need the whole page.
+	e.GET("/").WithHeader("HX-Request", "true").WithHeader("HX-History-Restore-Request", "true").Expect().Status(httptest.StatusOK).
+		Body().IsEqual(`<html><ul id="results"><li>result</li></ul></html>`)
+
+	e.GET("/block").Expect().Status(httptest.StatusOK).Body().IsEqual(`<li>block</li>`)
+
+	e.POST("/logout").WithHeader("HX-Request", "true").Expect().Status(httptest.StatusOK).
+		Header(context.HTMXRedirectHeaderKey).Equal("/login")
+}
@@ -1,6 +1,7 @@
 package view
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -32,6 +33,10 @@ type JetEngine struct {
 	// Available after `Load`.
 	Set *jet.Set
 	mu  sync.Mutex
+	// cache holds the parsed templates of the Set,
+	// a ReloadTemplates call removes the changed ones and their dependents.
+	cache *jetCache
+	deps  templateDeps
 
 	// Note that global vars and functions are set in a single spot on the jet parser.
 	// If AddFunc or AddVar called before `Load` then these will be set here to be used via `Load` and clear.
@@ -41,8 +46,9 @@ type JetEngine struct {
 }
 
 var (
-	_ Engine       = (*JetEngine)(nil)
-	_ EngineFuncer = (*JetEngine)(nil)
+	_ Engine         = (*JetEngine)(nil)
+	_ EngineFuncer   = (*JetEngine)(nil)
+	_ EngineReloader = (*JetEngine)(nil)
 )
 
 // jet library does not export or give us any option to modify them via Set
@@ -78,12 +84,19 @@ func Jet(dirOrFS interface{}, extension string) *JetEngine {
 		rootDir:           "/",
 		extension:         extension,
 		loader:            &jetLoader{fs: getFS(dirOrFS)},
+		cache:             new(jetCache),
+		deps:              make(templateDeps),
 		jetDataContextKey: "_jet",
 	}
 
 	return s
 }
 
+// FileSystem returns the file system of the templates.
+func (s *JetEngine) FileSystem() fs.FS {
+	return s.fs
+}
+
 // String returns the name of this view engine, the "jet".
 func (s *JetEngine) String() string {
 	return jetEngineName
@@ -265,14 +278,73 @@ func (s *JetEngine) ParseTemplate(name string, contents string) error {
 	s.initSet()
 
 	_, err := s.Set.Parse(name, contents)
+	if err == nil {
+		s.mu.Lock()
+		s.deps.set(strings.TrimPrefix(name, "/"), []byte(contents), s.extension)
+		s.mu.Unlock()
+	}
+
+	return err
+}
+
+// checkTemplate parses the "name" template file on a new Set, so it is not cached, see `Compile`.
+func (s *JetEngine) checkTemplate(name string, contents []byte) error {
+	set := jet.NewSet(s.loader, jet.WithDelims(s.left, s.right), jet.InDevelopmentMode())
+	_, err := set.Parse(name, string(contents))
 	return err
 }
 
+// ReloadTemplates parses the "names" template files and the templates that depend on them,
+// e.g. the ones that extend, include or import them, and, on success,
+// removes them from the Set's cache so they are loaded again on their next execution.
+// On failure the previous templates keep serving.
+//
+// See `View.Watch` too.
+func (s *JetEngine) ReloadTemplates(names ...string) error {
+	s.initSet()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deps := s.deps.clone()
+	affected := s.deps.dependents(names...)
+
+	// parse them on a new set, so the cached templates of the Set are not used.
+	set := jet.NewSet(s.loader, jet.WithDelims(s.left, s.right), jet.InDevelopmentMode())
+	for _, name := range affected {
+		contents, err := asset(s.fs, name)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				delete(deps, name)
+				continue
+			}
+
+			return err
+		}
+
+		if _, err = set.Parse(name, string(contents)); err != nil {
+			return fmt.Errorf("%s: %w", name, err)
+		}
+
+		deps.set(name, contents, s.extension)
+	}
+
+	for _, name := range affected {
+		// the templates are cached by their requested path, with or without the extension.
+		s.cache.delete("/" + name)
+		s.cache.delete("/" + strings.TrimSuffix(name, s.extension))
+	}
+
+	s.deps = deps
+	return nil
+}
+
 func (s *JetEngine) initSet() {
 	s.mu.Lock()
 	if s.Set == nil {
 		var opts = []jet.Option{
 			jet.WithDelims(s.left, s.right),
+			jet.WithCache(s.cache),
 		}
 		if s.developmentMode && !context.IsNoOpFS(s.fs) {
 			// this check is made to avoid jet's fs lookup on noOp fs (nil passed by the developer).
@@ -289,6 +361,31 @@ func (s *JetEngine) initSet() {
 	s.mu.Unlock()
 }
 
+// jetCache is the jet.Cache of the JetEngine's Set,
+// unlike the default one its templates can be removed.
+type jetCache struct {
+	m sync.Map
+}
+
+var _ jet.Cache = (*jetCache)(nil)
+
+func (c *jetCache) Get(templatePath string) *jet.Template {
+	t, ok := c.m.Load(templatePath)
+	if !ok {
+		return nil
+	}
+
+	return t.(*jet.Template)
+}
+
+func (c *jetCache) Put(templatePath string, t *jet.Template) {
+	c.m.Store(templatePath, t)
+}
+
+func (c *jetCache) delete(templatePath string) {
+	c.m.Delete(templatePath)
+}
+
 type (
 	// JetRuntimeVars is a type alias for `jet.VarMap`.
 	// Can be used at `AddJetRuntimeVars/JetEngine.AddRuntimeVars`
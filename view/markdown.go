@@ -0,0 +1,512 @@
+package view
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/kataras/iris/v12/context"
+
+	"github.com/gomarkdown/markdown"
+	"github.com/gomarkdown/markdown/ast"
+	"github.com/gomarkdown/markdown/html"
+	"github.com/gomarkdown/markdown/parser"
+	"gopkg.in/yaml.v3"
+)
+
+// MarkdownEngine contains the markdown view engine structure.
+// It renders markdown files to HTML, optionally wrapped into a layout of another view engine.
+type MarkdownEngine struct {
+	fs fs.FS
+	// files configuration
+	rootDir   string
+	extension string
+	reload    bool // if true, each time the ExecuteWriter is called the templates will be reloaded.
+	// parser configuration
+	extensions    parser.Extensions
+	renderOptions html.RendererOptions
+	highlight     func(w io.Writer, source, lang string) error
+	// layout configuration
+	layoutEngine Engine
+	layout       string
+
+	rmu   sync.RWMutex
+	pages map[string]*MarkdownPage
+}
+
+// MarkdownPage is a rendered markdown file.
+// It is the view data of the layout template, see `MarkdownEngine.Layout`.
+type MarkdownPage struct {
+	// Name is the template name of the markdown file.
+	Name string
+	// Title is the "title" of the front matter or the text of the first heading.
+	Title string
+	// Meta holds the YAML front matter of the markdown file, if any.
+	Meta map[string]interface{}
+	// Content is the rendered HTML of the markdown file.
+	Content template.HTML
+	// Headings holds the headings of the markdown file.
+	Headings []MarkdownHeading
+	// TOC is the rendered table of contents, a nested list of links to the headings.
+	TOC template.HTML
+	// Data is the binding data of the render call, e.g. the ctx.View's second argument.
+	Data interface{}
+}
+
+// MarkdownHeading is a heading of a markdown file, see `MarkdownPage.Headings`.
+type MarkdownHeading struct {
+	Level int
+	ID    string
+	Text  string
+}
+
+var (
+	_ Engine         = (*MarkdownEngine)(nil)
+	_ EngineFuncer   = (*MarkdownEngine)(nil)
+	_ EngineReloader = (*MarkdownEngine)(nil)
+)
+
+// Markdown creates and returns a new markdown view engine.
+// The given "extension" MUST begin with a dot, e.g. ".md".
+//
+// The markdown files can start with a YAML front matter, e.g.
+//
+//	---
+//	title: Getting Started
+//	tags: [docs]
+//	---
+//
+// which is available to the layout template, see the `Layout` and `LayoutEngine` methods.
+// The fenced code blocks are rendered with a "language-$lang" class,
+// see the `Highlight` method for server-side syntax highlighting.
+//
+// Usage:
+// Markdown("./docs", ".md") or
+// Markdown(iris.Dir("./docs"), ".md") or
+// Markdown(embed.FS, ".md") or Markdown(AssetFile(), ".md") for embedded data.
+func Markdown(fs interface{}, extension string) *MarkdownEngine {
+	s := &MarkdownEngine{
+		fs:            getFS(fs),
+		rootDir:       "/",
+		extension:     extension,
+		extensions:    parser.CommonExtensions | parser.AutoHeadingIDs,
+		renderOptions: html.RendererOptions{Flags: html.CommonFlags},
+		pages:         make(map[string]*MarkdownPage),
+	}
+
+	return s
+}
+
+// RootDir sets the directory to be used as a starting point
+// to load templates from the provided file system.
+func (s *MarkdownEngine) RootDir(root string) *MarkdownEngine {
+	if s.fs != nil && root != "" && root != "/" && root != "." && root != s.rootDir {
+		sub, err := fs.Sub(s.fs, root)
+		if err != nil {
+			panic(err)
+		}
+
+		s.fs = sub
+	}
+
+	s.rootDir = filepath.ToSlash(root)
+	return s
+}
+
+// FileSystem returns the file system of the templates.
+func (s *MarkdownEngine) FileSystem() fs.FS {
+	return s.fs
+}
+
+// Name returns the markdown engine's name.
+func (s *MarkdownEngine) Name() string {
+	return "Markdown"
+}
+
+// Ext returns the file extension which this view engine is responsible to render.
+// If the filename extension on ExecuteWriter is empty then this is appended.
+func (s *MarkdownEngine) Ext() string {
+	return s.extension
+}
+
+// Reload if set to true the templates are reloading on each render,
+// use it when you're in development and you're boring of restarting
+// the whole app when you edit a template file.
+func (s *MarkdownEngine) Reload(developmentMode bool) *MarkdownEngine {
+	s.reload = developmentMode
+	return s
+}
+
+// Extensions sets the markdown parser extensions,
+// defaults to parser.CommonExtensions | parser.AutoHeadingIDs.
+// The AutoHeadingIDs are required for the table of contents links.
+func (s *MarkdownEngine) Extensions(extensions parser.Extensions) *MarkdownEngine {
+	s.extensions = extensions
+	return s
+}
+
+// RenderOptions sets the markdown HTML renderer options,
+// defaults to html.RendererOptions{Flags: html.CommonFlags}.
+func (s *MarkdownEngine) RenderOptions(opts html.RendererOptions) *MarkdownEngine {
+	s.renderOptions = opts
+	return s
+}
+
+// Highlight sets a function which writes the highlighted HTML
+// of the "source" code of a code block to "w", e.g. through the github.com/alecthomas/chroma package.
+// The "lang" is the language of a fenced code block, it may be empty.
+//
+// By default the code blocks are rendered as <pre><code class="language-$lang">
+// so a client-side highlighter (e.g. highlight.js or Prism) can be used instead.
+func (s *MarkdownEngine) Highlight(fn func(w io.Writer, source, lang string) error) *MarkdownEngine {
+	s.highlight = fn
+	return s
+}
+
+// LayoutEngine sets the view engine of the layout templates,
+// e.g. LayoutEngine(view.HTML("./layouts", ".html")).Layout("docs.html").
+// The layouts are executed with a *MarkdownPage as their binding data,
+// e.g. {{ .Title }}, {{ .Meta.tags }}, {{ .TOC }} and {{ .Content }}.
+// Its `Load` is called by the markdown engine's one
+// and the functions added to the markdown engine are added to it too.
+func (s *MarkdownEngine) LayoutEngine(e Engine) *MarkdownEngine {
+	s.layoutEngine = e
+	return s
+}
+
+// Layout sets the default layout template of the layout engine,
+// see `LayoutEngine`. The layout of a render call (e.g. ctx.ViewLayout) overrides it.
+func (s *MarkdownEngine) Layout(layoutFile string) *MarkdownEngine {
+	s.layout = layoutFile
+	return s
+}
+
+// AddFunc adds a function to the layout engine, if any, see `LayoutEngine`.
+func (s *MarkdownEngine) AddFunc(funcName string, funcBody interface{}) {
+	if e, ok := s.layoutEngine.(EngineFuncer); ok {
+		e.AddFunc(funcName, funcBody)
+	}
+}
+
+// Load renders the markdown files and loads the layout engine, if any.
+//
+// Returns an error if something bad happens, user is responsible to catch it.
+func (s *MarkdownEngine) Load() error {
+	if s.layoutEngine != nil {
+		if err := s.layoutEngine.Load(); err != nil {
+			return fmt.Errorf("layout engine: %w", err)
+		}
+	}
+
+	if s.fs == nil || context.IsNoOpFS(s.fs) {
+		return nil
+	}
+
+	pages := make(map[string]*MarkdownPage)
+	rootDirName := getRootDirName(s.fs)
+
+	err := walk(s.fs, "", func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if info == nil || info.IsDir() {
+			return nil
+		}
+
+		if s.extension != "" {
+			if !strings.HasSuffix(path, s.extension) {
+				return nil
+			}
+		}
+
+		if s.rootDir == rootDirName {
+			path = strings.TrimPrefix(path, rootDirName)
+			path = strings.TrimPrefix(path, "/")
+		}
+
+		contents, err := asset(s.fs, path)
+		if err != nil {
+			return err
+		}
+
+		page, err := s.parse(path, contents)
+		if err != nil {
+			return err
+		}
+
+		pages[path] = page
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	s.rmu.Lock()
+	s.pages = pages
+	s.rmu.Unlock()
+	return nil
+}
+
+// ParseTemplate adds a custom markdown template from text.
+func (s *MarkdownEngine) ParseTemplate(name string, contents []byte) error {
+	name = strings.TrimPrefix(name, "/")
+	page, err := s.parse(name, contents)
+	if err != nil {
+		return err
+	}
+
+	s.rmu.Lock()
+	s.pages[name] = page
+	s.rmu.Unlock()
+	return nil
+}
+
+// ReloadTemplates re-renders the "names" markdown files,
+// the markdown files do not depend on each other.
+// New files are added and removed files are deleted.
+// On failure the previous pages keep serving.
+//
+// See `View.Watch` too.
+func (s *MarkdownEngine) ReloadTemplates(names ...string) error {
+	pages := make(map[string]*MarkdownPage, len(names))
+	var removed []string
+
+	for _, name := range names {
+		name = strings.TrimPrefix(name, "/")
+
+		contents, err := asset(s.fs, name)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				removed = append(removed, name)
+				continue
+			}
+
+			return err
+		}
+
+		page, err := s.parse(name, contents)
+		if err != nil {
+			return err
+		}
+
+		pages[name] = page
+	}
+
+	s.rmu.Lock()
+	for name, page := range pages {
+		s.pages[name] = page
+	}
+	for _, name := range removed {
+		delete(s.pages, name)
+	}
+	s.rmu.Unlock()
+
+	return nil
+}
+
+var frontMatterDelim = []byte("---")
+
+// splitFrontMatter returns the YAML front matter, if any, and the markdown body of the "contents".
+// The front matter starts with a "---" line and ends with the next "---" line.
+func splitFrontMatter(contents []byte) ([]byte, []byte) {
+	if !bytes.HasPrefix(contents, append(frontMatterDelim, '\n')) {
+		return nil, contents
+	}
+
+	rest := contents[len(frontMatterDelim)+1:]
+
+	end := -1
+	if bytes.HasPrefix(rest, frontMatterDelim) { // empty front matter.
+		end = 0
+	} else if idx := bytes.Index(rest, append([]byte{'\n'}, frontMatterDelim...)); idx != -1 {
+		end = idx + 1
+	}
+
+	if end == -1 {
+		return nil, contents // not closed.
+	}
+
+	body := rest[end+len(frontMatterDelim):]
+	if len(body) > 0 && body[0] != '\n' {
+		return nil, contents // not a delimiter line, e.g. "----".
+	}
+
+	return rest[:end], bytes.TrimPrefix(body, []byte{'\n'})
+}
+
+func (s *MarkdownEngine) parse(name string, contents []byte) (*MarkdownPage, error) {
+	contents = markdown.NormalizeNewlines(contents)
+	frontMatter, body := splitFrontMatter(contents)
+
+	page := &MarkdownPage{Name: name}
+
+	if len(frontMatter) > 0 {
+		if err := yaml.Unmarshal(frontMatter, &page.Meta); err != nil {
+			return nil, fmt.Errorf("%s: front matter: %w", name, err)
+		}
+
+		if title, ok := page.Meta["title"].(string); ok {
+			page.Title = title
+		}
+	}
+
+	doc := parser.NewWithExtensions(s.extensions).Parse(body)
+
+	ast.WalkFunc(doc, func(node ast.Node, entering bool) ast.WalkStatus {
+		if heading, ok := node.(*ast.Heading); ok && entering {
+			page.Headings = append(page.Headings, MarkdownHeading{
+				Level: heading.Level,
+				ID:    heading.HeadingID,
+				Text:  nodeText(heading),
+			})
+
+			return ast.SkipChildren
+		}
+
+		return ast.GoToNext
+	})
+
+	if page.Title == "" {
+		for _, heading := range page.Headings {
+			if heading.Level == 1 {
+				page.Title = heading.Text
+				break
+			}
+		}
+	}
+
+	var (
+		opts         = s.renderOptions
+		highlightErr error
+	)
+	if s.highlight != nil {
+		opts.RenderNodeHook = s.highlightHook(opts.RenderNodeHook, &highlightErr)
+	}
+
+	page.Content = template.HTML(markdown.Render(doc, html.NewRenderer(opts)))
+	if highlightErr != nil {
+		return nil, fmt.Errorf("%s: highlight: %w", name, highlightErr)
+	}
+
+	page.TOC = renderTOC(page.Headings)
+	return page, nil
+}
+
+// highlightHook returns a render hook which renders the code blocks through the `Highlight` function,
+// the rest of the nodes are rendered through the "next" hook, if any.
+func (s *MarkdownEngine) highlightHook(next html.RenderNodeFunc, highlightErr *error) html.RenderNodeFunc {
+	return func(w io.Writer, node ast.Node, entering bool) (ast.WalkStatus, bool) {
+		if code, ok := node.(*ast.CodeBlock); ok {
+			var lang string
+			if fields := strings.Fields(string(code.Info)); len(fields) > 0 {
+				lang = fields[0]
+			}
+
+			if err := s.highlight(w, string(code.Literal), lang); err != nil && *highlightErr == nil {
+				*highlightErr = err
+			}
+
+			return ast.GoToNext, true
+		}
+
+		if next != nil {
+			return next(w, node, entering)
+		}
+
+		return ast.GoToNext, false
+	}
+}
+
+// nodeText returns the text of the node's leaves, e.g. of a heading.
+func nodeText(node ast.Node) string {
+	var b strings.Builder
+	ast.WalkFunc(node, func(n ast.Node, entering bool) ast.WalkStatus {
+		if entering {
+			if leaf := n.AsLeaf(); leaf != nil {
+				b.Write(leaf.Literal)
+			}
+		}
+
+		return ast.GoToNext
+	})
+
+	return b.String()
+}
+
+// renderTOC returns a nested list of links to the "headings".
+func renderTOC(headings []MarkdownHeading) template.HTML {
+	if len(headings) == 0 {
+		return ""
+	}
+
+	var (
+		b      strings.Builder
+		levels []int // the levels of the open lists.
+	)
+
+	b.WriteString(`<nav class="toc">`)
+	for _, heading := range headings {
+		switch {
+		case len(levels) == 0 || heading.Level > levels[len(levels)-1]:
+			b.WriteString("<ul>")
+			levels = append(levels, heading.Level)
+		default:
+			for len(levels) > 1 && heading.Level < levels[len(levels)-1] {
+				b.WriteString("</li></ul>")
+				levels = levels[:len(levels)-1]
+			}
+			b.WriteString("</li>")
+		}
+
+		fmt.Fprintf(&b, `<li><a href="#%s">%s</a>`, template.HTMLEscapeString(heading.ID), template.HTMLEscapeString(heading.Text))
+	}
+
+	for range levels {
+		b.WriteString("</li></ul>")
+	}
+	b.WriteString("</nav>")
+
+	return template.HTML(b.String())
+}
+
+func (s *MarkdownEngine) fromCache(name string) *MarkdownPage {
+	// the pages may be replaced by a ReloadTemplates call.
+	s.rmu.RLock()
+	page := s.pages[name]
+	s.rmu.RUnlock()
+
+	return page
+}
+
+// ExecuteWriter writes the rendered markdown file to the w writer,
+// wrapped into the "layout" (or the default one) of the layout engine, if any.
+func (s *MarkdownEngine) ExecuteWriter(w io.Writer, filename string, layout string, bindingData interface{}) error {
+	// re-render the markdown files if reload is enabled.
+	if s.reload {
+		if err := s.Load(); err != nil {
+			return err
+		}
+	}
+
+	page := s.fromCache(strings.TrimPrefix(filename, "/"))
+	if page == nil {
+		return ErrNotExist{Name: filename, IsLayout: false, Data: bindingData}
+	}
+
+	if layout = getLayout(layout, s.layout); layout != "" && s.layoutEngine != nil {
+		data := *page // copy, the page is shared.
+		data.Data = bindingData
+		return s.layoutEngine.ExecuteWriter(w, layout, NoLayout, &data)
+	}
+
+	_, err := io.WriteString(w, string(page.Content))
+	return err
+}
@@ -0,0 +1,88 @@
+package view
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestMarkdown(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, contents string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(dir, name)), os.ModePerm); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), os.FileMode(0644)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("docs/install.md", "---\ntitle: Installation\ntags: [docs, setup]\n---\n# Install\n\n## Requirements\n\n### Go\n\n## Download\n\n```go\nfmt.Println(1)\n```\n")
+	write("layouts/docs.html", `<title>{{ .Title }}</title>{{ range .Meta.tags }}[{{ . }}]{{ end }}{{ .TOC }}<main>{{ .Content }}</main>{{ .Data }}`)
+
+	e := Markdown(dir, ".md").RootDir("docs").
+		LayoutEngine(HTML(dir, ".html").RootDir("layouts")).
+		Highlight(func(w io.Writer, source, lang string) error {
+			_, err := fmt.Fprintf(w, `<pre class="%s">%s</pre>`, lang, template.HTMLEscapeString(source))
+			return err
+		})
+	if err := e.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	var buf bytes.Buffer
+	if err := e.ExecuteWriter(&buf, "install.md", "", nil); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<h1 id="install">Install</h1>
+
+<h2 id="requirements">Requirements</h2>
+
+<h3 id="go">Go</h3>
+
+<h2 id="download">Download</h2>
+<pre class="go">fmt.Println(1)
+</pre>`
+	if got := buf.String(); got != expected {
+		t.Fatalf("expected:\n%s\nbut got:\n%s", expected, got)
+	}
+
+	buf.Reset()
+	if err := e.ExecuteWriter(&buf, "install.md", "docs.html", "data"); err != nil {
+		t.Fatal(err)
+	}
+
+	expected = `<title>Installation</title>[docs][setup]` +
+		`<nav class="toc"><ul><li><a href="#install">Install</a><ul><li><a href="#requirements">Requirements</a><ul><li><a href="#go">Go</a></li></ul></li><li><a href="#download">Download</a></li></ul></li></ul></nav>` +
+		`<main>` + expected + `</main>data`
+	if got := buf.String(); got != expected {
+		t.Fatalf("expected:\n%s\nbut got:\n%s", expected, got)
+	}
+}
+
+func TestSplitFrontMatter(t *testing.T) {
+	tests := []struct {
+		contents    string
+		frontMatter string
+		body        string
+	}{
+		{"---\ntitle: x\n---\n# body", "title: x\n", "# body"},
+		{"---\n---\nbody", "", "body"},
+		{"# no front matter\n---\n", "", "# no front matter\n---\n"},
+		{"---\ntitle: not closed", "", "---\ntitle: not closed"},
+		{"----\nrule", "", "----\nrule"},
+	}
+
+	for i, tt := range tests {
+		frontMatter, body := splitFrontMatter([]byte(tt.contents))
+		if string(frontMatter) != tt.frontMatter || string(body) != tt.body {
+			t.Fatalf("[%d] expected front matter: %q and body: %q but got: %q and %q", i, tt.frontMatter, tt.body, frontMatter, body)
+		}
+	}
+}
@@ -0,0 +1,302 @@
+package view
+
+import (
+	stdContext "context"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// EngineReloader is the interface for a view engine
+// which can reload a subset of its templates while serving requests.
+// All the builtin view engines implement it.
+//
+// See `View.Watch` and `View.ReloadTemplates` methods.
+type EngineReloader interface {
+	Engine
+	// FileSystem should return the file system of the templates,
+	// the template names are file paths of this file system.
+	FileSystem() fs.FS
+	// ReloadTemplates should re-parse the "names" templates and the templates that depend on them,
+	// e.g. the ones that include or extend them. On failure the previous templates should keep serving.
+	ReloadTemplates(names ...string) error
+}
+
+// templateReferenceRegex matches the names of the templates that a template file references, e.g.
+// {{ template "name" }}, {{ render "name" }}, {{ partial "name" }}, {% extends "name" %},
+// {% include "name" %}, {{ import "name" }}, {{> name }} and the pug and ace includes.
+var templateReferenceRegex = regexp.MustCompile(`(?:\b(?:template|render|partial|partial_r|extends|include|import)\s+|\{\{>\s*)["'` + "`" + `]?([\w./-]+)`)
+
+// templateDeps holds the possible names of the templates that each template references,
+// so a reload of a template reloads its dependents too.
+// It is protected by the view engine's lock.
+type templateDeps map[string][]string
+
+// set scans the "contents" of the "name" template for references to other templates.
+func (d templateDeps) set(name string, contents []byte, ext string) {
+	var refs []string
+
+	for _, m := range templateReferenceRegex.FindAllSubmatch(contents, -1) {
+		refs = append(refs, templateReferenceCandidates(name, string(m[1]), ext)...)
+	}
+
+	d[name] = refs
+}
+
+func (d templateDeps) clone() templateDeps {
+	c := make(templateDeps, len(d))
+	for name, refs := range d {
+		c[name] = refs
+	}
+
+	return c
+}
+
+// dependents returns the "names" and the templates that depend on them, directly or indirectly.
+func (d templateDeps) dependents(names ...string) []string {
+	affected := make(map[string]struct{})
+
+	queue := make([]string, 0, len(names))
+	for _, name := range names {
+		queue = append(queue, strings.TrimPrefix(name, "/"))
+	}
+
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+
+		if _, ok := affected[name]; ok {
+			continue
+		}
+		affected[name] = struct{}{}
+
+		for dependent, refs := range d {
+			for _, ref := range refs {
+				if ref == name {
+					queue = append(queue, dependent)
+					break
+				}
+			}
+		}
+	}
+
+	list := make([]string, 0, len(affected))
+	for name := range affected {
+		list = append(list, name)
+	}
+	sort.Strings(list)
+
+	return list
+}
+
+// ReloadTemplates reloads the "names" templates and the templates that depend on them,
+// or all the templates if the engine does not implement the `EngineReloader` interface.
+// The browsers connected to the `LiveReloadHandler` are notified on success.
+func (v *View) ReloadTemplates(names ...string) error {
+	if !v.Registered() {
+		return fmt.Errorf("no engine was registered")
+	}
+
+	var err error
+	if e, ok := v.Engine.(EngineReloader); ok {
+		err = e.ReloadTemplates(names...)
+	} else {
+		err = v.Engine.Load()
+	}
+
+	if v.OnReload != nil {
+		v.OnReload(names, err)
+	}
+
+	if err == nil {
+		v.liveReload.notify()
+	}
+
+	return err
+}
+
+type templateFile struct {
+	size    int64
+	modTime time.Time
+}
+
+// templateFiles returns the size and the modification time of the template files.
+func templateFiles(fileSystem fs.FS, ext string) (map[string]templateFile, error) {
+	files := make(map[string]templateFile)
+
+	err := fs.WalkDir(fileSystem, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.IsDir() || (ext != "" && !strings.HasSuffix(name, ext)) {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		files[name] = templateFile{size: info.Size(), modTime: info.ModTime()}
+		return nil
+	})
+
+	return files, err
+}
+
+// Watch checks the template files of the registered engine for changes every "interval"
+// (defaults to one second) until the "ctx" is canceled. The changed, new and removed templates
+// and the templates that depend on them are reloaded, the rest are kept as they are.
+// Unlike the engines' Reload(true) option, the templates are not parsed on each request.
+//
+// The engine should implement the `EngineReloader` interface, all the builtin engines do.
+// See the `OnReload` field to log the reloads and the `LiveReloadHandler` to refresh the browsers.
+func (v *View) Watch(ctx stdContext.Context, interval time.Duration) error {
+	if !v.Registered() {
+		return fmt.Errorf("no engine was registered")
+	}
+
+	e, ok := v.Engine.(EngineReloader)
+	if !ok {
+		return fmt.Errorf("%s: watch is not supported", v.Engine.Name())
+	}
+
+	fileSystem := e.FileSystem()
+	if fileSystem == nil || context.IsNoOpFS(fileSystem) {
+		return fmt.Errorf("%s: watch: templates are not loaded from a file system", v.Engine.Name())
+	}
+
+	if interval <= 0 {
+		interval = time.Second
+	}
+
+	files, err := templateFiles(fileSystem, e.Ext())
+	if err != nil {
+		return err
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				current, err := templateFiles(fileSystem, e.Ext())
+				if err != nil {
+					if v.OnReload != nil {
+						v.OnReload(nil, err)
+					}
+					continue
+				}
+
+				var changed []string
+				for name, file := range current {
+					if old, ok := files[name]; !ok || old != file {
+						changed = append(changed, name)
+					}
+				}
+
+				for name := range files {
+					if _, ok := current[name]; !ok {
+						changed = append(changed, name)
+					}
+				}
+
+				files = current
+
+				if len(changed) > 0 {
+					sort.Strings(changed)
+					v.ReloadTemplates(changed...) // the error is reported to OnReload.
+				}
+			}
+		}
+	}()
+
+	return nil
+}
+
+// LiveReloadPath is the request path of the `View.LiveReloadHandler`
+// which the `LiveReloadScript` connects to.
+const LiveReloadPath = "/iris-view-livereload"
+
+// LiveReloadScript is the script which refreshes the browser page
+// when the templates are reloaded, see `View.LiveReloadHandler`.
+var LiveReloadScript = []byte(`<script>(function () {
+    const events = new EventSource("` + LiveReloadPath + `");
+    events.onmessage = function () {
+        window.location.reload();
+    };
+}());</script>`)
+
+// liveReloadClients holds the connected browsers of the LiveReloadHandler.
+type liveReloadClients struct {
+	mu      sync.Mutex
+	clients map[chan struct{}]struct{}
+}
+
+func (c *liveReloadClients) add() chan struct{} {
+	ch := make(chan struct{}, 1)
+
+	c.mu.Lock()
+	if c.clients == nil {
+		c.clients = make(map[chan struct{}]struct{})
+	}
+	c.clients[ch] = struct{}{}
+	c.mu.Unlock()
+
+	return ch
+}
+
+func (c *liveReloadClients) remove(ch chan struct{}) {
+	c.mu.Lock()
+	delete(c.clients, ch)
+	c.mu.Unlock()
+}
+
+func (c *liveReloadClients) notify() {
+	c.mu.Lock()
+	for ch := range c.clients {
+		select {
+		case ch <- struct{}{}:
+		default: // a reload is already pending.
+		}
+	}
+	c.mu.Unlock()
+}
+
+// LiveReloadHandler is an Iris Handler which sends a server-sent event
+// to the connected browsers after each successful templates reload.
+// Register it on the `LiveReloadPath` and inject the `LiveReloadScript` to the rendered pages,
+// the Application.WatchViews method does that automatically in debug mode.
+func (v *View) LiveReloadHandler(ctx *context.Context) {
+	ch := v.liveReload.add()
+	defer v.liveReload.remove(ch)
+
+	ctx.ContentType("text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.StatusCode(http.StatusOK)
+	ctx.ResponseWriter().Flush()
+
+	for {
+		select {
+		case <-ctx.Request().Context().Done():
+			return
+		case <-ch:
+			if _, err := ctx.WriteString("data: reload\n\n"); err != nil {
+				return
+			}
+			ctx.ResponseWriter().Flush()
+		}
+	}
+}
@@ -0,0 +1,80 @@
+package view
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestHTMLReloadTemplates(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, contents string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), os.FileMode(0644)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("index.html", `<h1>{{ template "partial.html" }}</h1>`)
+	write("partial.html", `v1`)
+
+	v := new(View)
+	v.Register(HTML(dir, ".html"))
+	if err := v.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	expect := func(expected string) {
+		t.Helper()
+		var buf bytes.Buffer
+		if err := v.ExecuteWriter(&buf, "index.html", "", nil); err != nil {
+			t.Fatal(err)
+		}
+		if got := buf.String(); got != expected {
+			t.Fatalf("expected: %q but got: %q", expected, got)
+		}
+	}
+
+	expect("<h1>v1</h1>")
+
+	var reloaded []string
+	v.OnReload = func(names []string, err error) {
+		reloaded = names
+	}
+
+	write("partial.html", `v2`)
+	if err := v.ReloadTemplates("partial.html"); err != nil {
+		t.Fatal(err)
+	}
+	if len(reloaded) != 1 || reloaded[0] != "partial.html" {
+		t.Fatalf("expected OnReload to be called with the reloaded names but got: %v", reloaded)
+	}
+	expect("<h1>v2</h1>")
+
+	// A broken template is rejected and the previous templates keep serving.
+	write("partial.html", `{{ .Broken `)
+	if err := v.ReloadTemplates("partial.html"); err == nil {
+		t.Fatalf("expected a parse error")
+	}
+	expect("<h1>v2</h1>")
+}
+
+func TestTemplateDepsDependents(t *testing.T) {
+	deps := make(templateDeps)
+	deps.set("layouts/main.html", []byte(`{{ yield . }} {{ partial "partials/footer" }}`), ".html")
+	deps.set("index.html", []byte(`{{ render "partials/footer.html" }}`), ".html")
+	deps.set("partials/footer.html", []byte(`{{ template "partials/links.html" }}`), ".html")
+	deps.set("about.html", []byte(`about`), ".html")
+
+	expected := []string{"index.html", "layouts/main.html", "partials/footer.html", "partials/links.html"}
+	got := deps.dependents("partials/links.html")
+	if len(got) != len(expected) {
+		t.Fatalf("expected: %v but got: %v", expected, got)
+	}
+	for i := range expected {
+		if got[i] != expected[i] {
+			t.Fatalf("expected: %v but got: %v", expected, got)
+		}
+	}
+}
@@ -0,0 +1,243 @@
+package view
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/kataras/iris/v12/context"
+)
+
+// asyncBlocks holds the async template function calls of the running executions
+// until their streaming writer reads their markers and takes them over, see `streamWriter.start`.
+// The keys are random, so a response can only load the blocks of its own markers,
+// and a block whose marker never reaches a streaming writer is dropped after asyncBlockTimeout.
+var asyncBlocks sync.Map // string -> *asyncBlock
+
+// asyncBlockTimeout is the time an async template function call waits
+// for its marker to be written to the streaming writer.
+const asyncBlockTimeout = time.Minute
+
+type asyncBlock struct {
+	render  func() (template.HTML, error)
+	expires *time.Timer
+}
+
+var (
+	asyncMarkerPrefix = []byte("<!--iris-async:")
+	asyncMarkerSuffix = []byte("-->")
+	headCloseTag      = []byte("</head>")
+)
+
+// asyncFunc returns the "async" template function of the HTML and Blocks view engines.
+// When streaming is disabled the "name" template is rendered in place,
+// otherwise the function returns a marker which the streaming writer of the execution
+// replaces with a placeholder and starts rendering the template in the background.
+func asyncFunc(stream func() bool, render func(name string, data interface{}) (template.HTML, error)) func(string, interface{}) (template.HTML, error) {
+	return func(name string, data interface{}) (template.HTML, error) {
+		if !stream() {
+			return render(name, data)
+		}
+
+		key, err := newAsyncBlockKey()
+		if err != nil {
+			return "", err
+		}
+
+		asyncBlocks.Store(key, &asyncBlock{
+			render: func() (template.HTML, error) {
+				return render(name, data)
+			},
+			expires: time.AfterFunc(asyncBlockTimeout, func() {
+				asyncBlocks.Delete(key)
+			}),
+		})
+
+		return template.HTML(fmt.Sprintf("%s%s%s", asyncMarkerPrefix, key, asyncMarkerSuffix)), nil
+	}
+}
+
+func newAsyncBlockKey() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(b), nil
+}
+
+// asyncSwapScript is written once, before the first rendered async block.
+// It replaces a placeholder with the contents of its rendered block.
+const asyncSwapScript = `<script>function irisAsync(id){var p=document.getElementById("iris-async-"+id),c=document.getElementById("iris-async-content-"+id);if(p&&c){p.replaceWith(c.content);c.remove();}}</script>`
+
+type asyncResult struct {
+	id       int
+	contents template.HTML
+	err      error
+}
+
+// streamWriter is the writer of a streaming template execution.
+// It flushes the response after the head of the page,
+// writes placeholders in place of the async blocks and,
+// after the execution, writes each async block as soon as it is rendered.
+type streamWriter struct {
+	w     io.Writer
+	flush func()
+
+	headFlushed bool
+	swapWritten bool
+	// blocks are the async blocks of this execution, by their placeholder id,
+	// which are rendered in the background.
+	blocks  map[int]*asyncBlock
+	nextID  int
+	results chan asyncResult
+}
+
+func newStreamWriter(w io.Writer) *streamWriter {
+	sw := &streamWriter{
+		w:       w,
+		flush:   func() {},
+		blocks:  make(map[int]*asyncBlock),
+		results: make(chan asyncResult),
+	}
+
+	switch f := w.(type) {
+	case http.Flusher:
+		sw.flush = f.Flush
+	case interface{ ResponseWriter() context.ResponseWriter }: // the Iris Context.
+		sw.flush = f.ResponseWriter().Flush
+	}
+
+	return sw
+}
+
+func (sw *streamWriter) Write(p []byte) (int, error) {
+	n := len(p)
+
+	for {
+		start := bytes.Index(p, asyncMarkerPrefix)
+		if start == -1 {
+			break
+		}
+
+		end := bytes.Index(p[start:], asyncMarkerSuffix)
+		if end == -1 {
+			break
+		}
+		end += start
+
+		v, ok := asyncBlocks.LoadAndDelete(string(p[start+len(asyncMarkerPrefix) : end]))
+		if !ok { // not a marker of ours, write it as it is.
+			if err := sw.write(p[:end+len(asyncMarkerSuffix)]); err != nil {
+				return 0, err
+			}
+			p = p[end+len(asyncMarkerSuffix):]
+			continue
+		}
+
+		if err := sw.write(p[:start]); err != nil {
+			return 0, err
+		}
+
+		id := sw.start(v.(*asyncBlock))
+		if err := sw.write([]byte(`<template id="iris-async-` + strconv.Itoa(id) + `"></template>`)); err != nil {
+			return 0, err
+		}
+
+		p = p[end+len(asyncMarkerSuffix):]
+	}
+
+	if err := sw.write(p); err != nil {
+		return 0, err
+	}
+
+	return n, nil
+}
+
+func (sw *streamWriter) write(p []byte) error {
+	if len(p) == 0 {
+		return nil
+	}
+
+	if _, err := sw.w.Write(p); err != nil {
+		return err
+	}
+
+	if !sw.headFlushed && bytes.Contains(p, headCloseTag) {
+		sw.headFlushed = true
+		sw.flush()
+	}
+
+	return nil
+}
+
+// start takes over the async "block" and renders it in the background.
+// It returns the id of the block's placeholder.
+func (sw *streamWriter) start(block *asyncBlock) int {
+	block.expires.Stop()
+
+	sw.nextID++
+	id := sw.nextID
+	sw.blocks[id] = block
+
+	go func() {
+		contents, err := block.render()
+		sw.results <- asyncResult{id: id, contents: contents, err: err}
+	}()
+
+	return id
+}
+
+// finish waits for the async blocks and writes them in the order they complete.
+// The "err" is the error of the template execution.
+func (sw *streamWriter) finish(err error) error {
+	if err == nil {
+		sw.flush()
+	}
+
+	var errs []error
+	if err != nil {
+		errs = append(errs, err)
+	}
+
+	for len(sw.blocks) > 0 {
+		result := <-sw.results
+		delete(sw.blocks, result.id)
+
+		if len(errs) > 0 { // wait for the rest and discard them.
+			continue
+		}
+
+		if result.err != nil {
+			errs = append(errs, result.err)
+			continue
+		}
+
+		if !sw.swapWritten {
+			sw.swapWritten = true
+			if err := sw.write([]byte(asyncSwapScript)); err != nil {
+				errs = append(errs, err)
+				continue
+			}
+		}
+
+		// the contents may contain async blocks too.
+		chunk := fmt.Sprintf(`<template id="iris-async-content-%d">%s</template><script>irisAsync(%d)</script>`, result.id, result.contents, result.id)
+		if _, err := sw.Write([]byte(chunk)); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		sw.flush()
+	}
+
+	return errors.Join(errs...)
+}
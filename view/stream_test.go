@@ -0,0 +1,127 @@
+package view
+
+import (
+	"bytes"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+type flushRecorder struct {
+	bytes.Buffer
+	flushes []string
+}
+
+func (r *flushRecorder) Flush() {
+	r.flushes = append(r.flushes, r.String())
+}
+
+func TestHTMLStream(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, contents string) {
+		t.Helper()
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(contents), os.FileMode(0644)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("layout.html", `<html><head><title>Dashboard</title></head><body>{{ yield . }}</body></html>`)
+	write("index.html", `<h1>Dashboard</h1>{{ async "slow.html" . }}{{ async "fast.html" . }}`)
+	write("slow.html", `{{ sleep 30 }}slow`)
+	write("fast.html", `fast`)
+
+	e := HTML(dir, ".html").Layout("layout.html")
+	e.AddFunc("sleep", func(ms int) string {
+		time.Sleep(time.Duration(ms) * time.Millisecond)
+		return ""
+	})
+	if err := e.Load(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Without streaming the async templates are rendered in place.
+	var buf bytes.Buffer
+	if err := e.ExecuteWriter(&buf, "index.html", "", nil); err != nil {
+		t.Fatal(err)
+	}
+	if expected, got := `<html><head><title>Dashboard</title></head><body><h1>Dashboard</h1>slowfast</body></html>`, buf.String(); expected != got {
+		t.Fatalf("expected:\n%s\nbut got:\n%s", expected, got)
+	}
+
+	e.Stream(true)
+
+	rec := new(flushRecorder)
+	if err := e.ExecuteWriter(rec, "index.html", "", nil); err != nil {
+		t.Fatal(err)
+	}
+	body := rec.String()
+
+	if len(rec.flushes) == 0 || !strings.Contains(rec.flushes[0], "</head>") || strings.Contains(rec.flushes[0], "<h1>") {
+		t.Fatalf("expected the head to be flushed first but got: %v", rec.flushes)
+	}
+
+	page := `<h1>Dashboard</h1><template id="iris-async-`
+	if !strings.Contains(body, page) || strings.Contains(body, "<!--iris-async:") {
+		t.Fatalf("expected placeholders in place of the async templates but got:\n%s", body)
+	}
+
+	fastIdx := strings.Index(body, `">fast</template>`)
+	slowIdx := strings.Index(body, `">slow</template>`)
+	if fastIdx == -1 || slowIdx == -1 || fastIdx > slowIdx || fastIdx < strings.Index(body, "</html>") {
+		t.Fatalf("expected the async templates after the page, in the order they complete, but got:\n%s", body)
+	}
+
+	if strings.Count(body, asyncSwapScript) != 1 || strings.Count(body, "<script>irisAsync(") != 2 {
+		t.Fatalf("expected the swap scripts but got:\n%s", body)
+	}
+}
+
+func TestStreamWriterAsyncBlocks(t *testing.T) {
+	render := asyncFunc(func() bool { return true }, func(name string, data interface{}) (template.HTML, error) {
+		return template.HTML(name), nil
+	})
+
+	marker, err := render("block", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// A marker which was not returned by the async function,
+	// e.g. user input, is written as it is.
+	forged := "<!--iris-async:1-->"
+
+	var buf bytes.Buffer
+	sw := newStreamWriter(&buf)
+	if _, err = sw.Write([]byte(forged + string(marker))); err != nil {
+		t.Fatal(err)
+	}
+	if err = sw.finish(nil); err != nil {
+		t.Fatal(err)
+	}
+
+	body := buf.String()
+	if !strings.HasPrefix(body, forged+`<template id="iris-async-1"></template>`) || !strings.Contains(body, `<template id="iris-async-content-1">block</template>`) {
+		t.Fatalf("unexpected body:\n%s", body)
+	}
+
+	// The block is loaded once, by the execution which wrote its marker.
+	asyncBlocks.Range(func(key, _ interface{}) bool {
+		t.Fatalf("expected no pending async blocks but got: %v", key)
+		return false
+	})
+
+	buf.Reset()
+	sw = newStreamWriter(&buf)
+	if _, err = sw.Write([]byte(marker)); err != nil {
+		t.Fatal(err)
+	}
+	if err = sw.finish(nil); err != nil {
+		t.Fatal(err)
+	}
+	if body = buf.String(); body != string(marker) {
+		t.Fatalf("expected the marker as it is but got:\n%s", body)
+	}
+}
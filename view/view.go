@@ -19,13 +19,26 @@ type (
 	// which accepts builtin framework functions such as url, urlpath and tr.
 	// It's an alias of context.ViewEngineFuncer.
 	EngineFuncer = context.ViewEngineFuncer
+	// EngineBlockExecutor is the interface for a compatible Iris view engine
+	// which can render a single block of a template, e.g. for htmx partial page updates.
+	// The HTML, Django and Blocks engines implement it.
+	// It's an alias of context.ViewEngineBlockExecutor.
+	EngineBlockExecutor = context.ViewEngineBlockExecutor
 )
 
 // ErrNotExist reports whether a template was not found in the parsed templates tree.
 type ErrNotExist = context.ErrViewNotExist
 
 // View is just a wrapper on top of the registered template engine.
-type View struct{ Engine }
+type View struct {
+	Engine
+
+	// OnReload, if not nil, is called after each `ReloadTemplates`,
+	// including the ones of the `Watch` method, with the reloaded template names and the reload error.
+	OnReload func(names []string, err error)
+
+	liveReload liveReloadClients
+}
 
 // Register registers a view engine.
 func (v *View) Register(e Engine) {
@@ -65,6 +78,17 @@ func (v *View) ExecuteWriter(w io.Writer, filename string, layout string, bindin
 	return v.Engine.ExecuteWriter(w, filename, layout, bindingData)
 }
 
+// ExecuteBlock calls the view Engine's ExecuteBlock func,
+// it returns a context.ErrViewBlockNotSupported error if the engine does not support blocks.
+func (v *View) ExecuteBlock(w io.Writer, filename string, block string, bindingData interface{}) error {
+	e, ok := v.Engine.(EngineBlockExecutor)
+	if !ok {
+		return fmt.Errorf("%s: %w", v.Engine.Name(), context.ErrViewBlockNotSupported)
+	}
+
+	return e.ExecuteBlock(w, v.ensureTemplateName(filename), block, bindingData)
+}
+
 // AddFunc adds a function to all registered engines.
 // Each template engine that supports functions has its own AddFunc too.
 func (v *View) AddFunc(funcName string, funcBody interface{}) {
@@ -101,7 +125,7 @@ func (v *View) Load() error {
 }
 
 // NoLayout disables the configuration's layout for a specific execution.
-const NoLayout = "iris.nolayout"
+const NoLayout = context.NoLayout
 
 // returns empty if it's no layout or empty layout and empty configuration's layout.
 func getLayout(layout string, globalLayout string) string {